@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -13,6 +15,11 @@ import (
 // This list must be kept in sync with filter.DefaultRegistry() capabilities.
 var cocSupportedCommands = []string{
 	"git", "go", "cargo", "docker", "grep", "rg", "npm", "pip", "pip3", "yarn",
+	"staticcheck", "golangci-lint", "pytest", "py.test", "python", "python3",
+	"pnpm", "bun", "npx", "bunx", "jest", "vitest", "tsc", "eslint", "biome",
+	"prettier", "make", "gmake", "ninja", "cmake", "cc", "c++", "gcc", "g++",
+	"clang", "clang++", "mvn", "mvnw", "gradle", "gradlew", "kubectl",
+	"terraform", "tofu", "helm", "kustomize", "docker-compose", "journalctl",
 }
 
 // hookInput represents the JSON structure Claude Code sends to PreToolUse hooks.
@@ -119,7 +126,9 @@ func runHook(_ *cobra.Command, _ []string) error {
 }
 
 // containsShellOps checks if the command contains shell operators that would
-// prevent coc from wrapping it (pipes, chains, subshells, etc.).
+// prevent coc from wrapping it (pipes, chains, subshells, etc.). Output
+// redirected to a file (`>`, `>>`, `tee`) is left unwrapped too: it is meant
+// to be read as is, like `helm template ... > rendered.yaml`.
 //
 // NOTE: This uses naive string matching and may produce false positives for
 // operators inside quoted strings (e.g., git log --grep="|pattern"). This is
@@ -132,7 +141,9 @@ func containsShellOps(cmd string) bool {
 		strings.Contains(cmd, "||") ||
 		strings.Contains(cmd, ";") ||
 		strings.Contains(cmd, "$(") ||
-		strings.Contains(cmd, "`")
+		strings.Contains(cmd, "`") ||
+		strings.Contains(cmd, ">") ||
+		slices.Contains(strings.Fields(cmd), "tee")
 }
 
 // extractFirstWord returns the first whitespace-separated word from the command.
@@ -144,10 +155,19 @@ func extractFirstWord(cmd string) string {
 	return parts[0]
 }
 
-// isSupportedCommand checks if the command is in the list of coc-supported commands.
+// isSupportedCommand checks if the command is in the list of coc-supported
+// commands. Paths and Windows launcher extensions are ignored, so project
+// wrappers such as ./gradlew and mvnw.cmd are recognized.
 func isSupportedCommand(cmd string) bool {
+	if cmd == "" {
+		return false
+	}
+	name := filepath.Base(cmd)
+	if ext := strings.ToLower(filepath.Ext(name)); ext == ".bat" || ext == ".cmd" {
+		name = name[:len(name)-len(ext)]
+	}
 	for _, supported := range cocSupportedCommands {
-		if cmd == supported {
+		if name == supported {
 			return true
 		}
 	}
@@ -22,6 +22,9 @@ func TestContainsShellOps(t *testing.T) {
 		{"command substitution dollar", "echo $(git status)", true},
 		{"command substitution backtick", "echo `git status`", true},
 		{"multiple ops", "git diff | grep foo && echo found", true},
+		{"redirect", "helm template ./chart > rendered.yaml", true},
+		{"append redirect", "kustomize build overlays/prod >> out.yaml", true},
+		{"tee", "tee out.yaml", true},
 
 		// Should NOT detect shell ops
 		{"simple command", "git status", false},
@@ -84,17 +87,50 @@ func TestIsSupportedCommand(t *testing.T) {
 		{"pip", "pip", true},
 		{"pip3", "pip3", true},
 		{"yarn", "yarn", true},
+		{"staticcheck", "staticcheck", true},
+		{"golangci-lint", "golangci-lint", true},
+		{"pytest", "pytest", true},
+		{"py.test", "py.test", true},
+		{"python", "python", true},
+		{"python3", "python3", true},
+		{"pnpm", "pnpm", true},
+		{"bun", "bun", true},
+		{"npx", "npx", true},
+		{"bunx", "bunx", true},
+		{"jest", "jest", true},
+		{"vitest", "vitest", true},
+		{"tsc", "tsc", true},
+		{"eslint", "eslint", true},
+		{"biome", "biome", true},
+		{"prettier", "prettier", true},
+		{"make", "make", true},
+		{"ninja", "ninja", true},
+		{"cmake", "cmake", true},
+		{"gcc", "gcc", true},
+		{"clang++", "clang++", true},
+		{"mvn", "mvn", true},
+		{"gradle", "gradle", true},
+		{"gradle wrapper", "./gradlew", true},
+		{"maven wrapper cmd", "mvnw.cmd", true},
+		{"kubectl", "kubectl", true},
+		{"terraform", "terraform", true},
+		{"tofu", "tofu", true},
+		{"helm", "helm", true},
+		{"kustomize", "kustomize", true},
+		{"docker-compose", "docker-compose", true},
+		{"journalctl", "journalctl", true},
+		{"absolute path", "/usr/bin/git", true},
 
 		// Not supported
 		{"echo", "echo", false},
 		{"ls", "ls", false},
 		{"curl", "curl", false},
-		{"make", "make", false},
-		{"python", "python", false},
+		{"python2", "python2", false},
 		{"coc", "coc", false},
 		{"empty", "", false},
 		{"partial match", "gitk", false},
 		{"case sensitive", "GIT", false},
+		{"unsupported path", "./scripts/deploy.sh", false},
 	}
 
 	for _, tc := range tests {
@@ -260,6 +296,23 @@ func TestShouldWrapCommandIntegration(t *testing.T) {
 		{"pip install requests", "pip install requests", true},
 		{"pip3 install flask", "pip3 install flask", true},
 		{"yarn add lodash", "yarn add lodash", true},
+		{"pytest -x tests/", "pytest -x tests/", true},
+		{"python script.py", "python script.py", true},
+		{"python3 manage.py migrate", "python3 manage.py migrate", true},
+		{"npm test", "npm test", true},
+		{"npx vitest run", "npx vitest run", true},
+		{"pnpm test", "pnpm test", true},
+		{"tsc --noEmit", "tsc --noEmit", true},
+		{"npx tsc --noEmit", "npx tsc --noEmit", true},
+		{"eslint .", "eslint .", true},
+		{"prettier --check .", "prettier --check .", true},
+		{"make build", "make build", true},
+		{"cmake --build build", "cmake --build build", true},
+		{"mvn -B test", "mvn -B test", true},
+		{"./gradlew build", "./gradlew build", true},
+		{"kubectl get pods -A", "kubectl get pods -A", true},
+		{"terraform plan", "terraform plan -out=tfplan", true},
+		{"helm template", "helm template web ./chart", true},
 		{"git with leading space", "  git status", true},
 		{"git bare", "git", true},
 
@@ -271,8 +324,6 @@ func TestShouldWrapCommandIntegration(t *testing.T) {
 		{"echo hello", "echo hello", false},
 		{"ls -la", "ls -la", false},
 		{"curl https://example.com", "curl https://example.com", false},
-		{"make build", "make build", false},
-		{"python script.py", "python script.py", false},
 
 		// Should NOT wrap - pipelines and chains
 		{"git diff | head", "git diff | head", false},
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Fuabioo/coc/internal/executor"
 	"github.com/Fuabioo/coc/internal/filter"
+	"github.com/Fuabioo/coc/internal/redact"
 )
 
 // Version and Commit are set via ldflags at build time.
@@ -64,10 +65,19 @@ func Execute() int {
 func runRoot(cmd *cobra.Command, _ []string) error {
 	// Local flag state — not package-level, so tests can call runRoot safely
 	var (
-		flagVerbose  int
-		flagLogDir   string
-		flagNoFilter bool
-		flagNoLog    bool
+		flagVerbose   int
+		flagLogDir    string
+		flagNoFilter  bool
+		flagNoLog     bool
+		flagSinceLast bool
+		flagFilter    string
+		flagFullPaths bool
+		flagNoRedact  bool
+		// COC_REDACT_PATTERNS holds extra redaction regexps, one per line
+		flagRedact     = strings.Split(os.Getenv("COC_REDACT_PATTERNS"), "\n")
+		flagRedactLogs = os.Getenv("COC_REDACT_LOGS") == "1"
+		// COC_STRUCTURED lets hook-rewritten commands opt in without a flag
+		flagStructured = os.Getenv("COC_STRUCTURED") == "1"
 	)
 
 	args := os.Args[1:]
@@ -91,6 +101,12 @@ func runRoot(cmd *cobra.Command, _ []string) error {
 		case args[i] == "--log-dir" && i+1 < len(args):
 			flagLogDir = args[i+1]
 			i += 2
+		case strings.HasPrefix(args[i], "--filter="):
+			flagFilter = strings.TrimPrefix(args[i], "--filter=")
+			i++
+		case args[i] == "--filter" && i+1 < len(args):
+			flagFilter = args[i+1]
+			i += 2
 		case args[i] == "--no-filter":
 			flagNoFilter = true
 			i++
@@ -98,6 +114,27 @@ func runRoot(cmd *cobra.Command, _ []string) error {
 			flagNoLog = true
 			flagNoFilter = true
 			i++
+		case args[i] == "--since-last":
+			flagSinceLast = true
+			i++
+		case args[i] == "--full-paths":
+			flagFullPaths = true
+			i++
+		case strings.HasPrefix(args[i], "--redact="):
+			flagRedact = append(flagRedact, strings.TrimPrefix(args[i], "--redact="))
+			i++
+		case args[i] == "--redact" && i+1 < len(args):
+			flagRedact = append(flagRedact, args[i+1])
+			i += 2
+		case args[i] == "--redact-logs":
+			flagRedactLogs = true
+			i++
+		case args[i] == "--no-redact":
+			flagNoRedact = true
+			i++
+		case args[i] == "--structured":
+			flagStructured = true
+			i++
 		case args[i] == "-h" || args[i] == "--help":
 			return cmd.Help()
 		case args[i] == "--version":
@@ -113,14 +150,35 @@ func runRoot(cmd *cobra.Command, _ []string) error {
 		return cmd.Help()
 	}
 
+	registry := filter.DefaultRegistry()
+	if flagFilter != "" {
+		if _, ok := registry.Lookup(flagFilter); !ok {
+			return fmt.Errorf("unknown filter %q (available: %s)", flagFilter, strings.Join(registry.Names(), ", "))
+		}
+	}
+
+	var redactor *redact.Redactor
+	if !flagNoRedact {
+		var err error
+		if redactor, err = redact.New(flagRedact); err != nil {
+			return err
+		}
+	}
+
 	cfg := executor.Config{
-		Command:  proxiedArgs[0],
-		Args:     proxiedArgs[1:],
-		LogDir:   flagLogDir,
-		NoFilter: flagNoFilter,
-		NoLog:    flagNoLog,
-		Verbose:  flagVerbose > 0,
-		Registry: filter.DefaultRegistry(),
+		Command:    proxiedArgs[0],
+		Args:       proxiedArgs[1:],
+		LogDir:     flagLogDir,
+		NoFilter:   flagNoFilter,
+		NoLog:      flagNoLog,
+		Verbose:    flagVerbose > 0,
+		SinceLast:  flagSinceLast,
+		Structured: flagStructured,
+		Filter:     flagFilter,
+		FullPaths:  flagFullPaths,
+		Redactor:   redactor,
+		RedactLogs: flagRedactLogs,
+		Registry:   registry,
 	}
 
 	result := executor.Run(cfg)
@@ -2,17 +2,22 @@ package executor
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/Fuabioo/coc/internal/filter"
+	"github.com/Fuabioo/coc/internal/history"
 	"github.com/Fuabioo/coc/internal/logpath"
+	"github.com/Fuabioo/coc/internal/redact"
 )
 
 // smallOutputThreshold is the byte count below which a log file is considered
@@ -39,7 +44,24 @@ type Config struct {
 	NoFilter bool
 	NoLog    bool
 	Verbose  bool
-	Registry *filter.Registry
+	// SinceLast replaces the curated output with a report of what changed
+	// since the previous run of the same command in the same directory.
+	SinceLast bool
+	// Structured asks supported tools for machine-readable output (e.g.
+	// `go test -json`) so a structured strategy can parse it.
+	Structured bool
+	// Filter names a strategy to use instead of the one the registry would
+	// pick for the command (e.g. "log-cluster"). NoFilter takes precedence.
+	Filter string
+	// FullPaths keeps absolute paths as printed instead of shortening them
+	// relative to the repository and to dependency root aliases.
+	FullPaths bool
+	// Redactor masks secrets in the output (see Run); nil disables redaction.
+	Redactor *redact.Redactor
+	// RedactLogs also masks secrets in the log file, which otherwise keeps
+	// the output as printed.
+	RedactLogs bool
+	Registry   *filter.Registry
 }
 
 // Result holds the execution result.
@@ -51,12 +73,34 @@ type Result struct {
 // Run executes the command with the MultiWriter tee pattern.
 func Run(cfg Config) Result {
 	command := filepath.Base(cfg.Command)
+	if cfg.Structured && !cfg.NoFilter && !cfg.NoLog {
+		cfg.Args = filter.StructuredArgs(command, cfg.Args)
+	}
 
 	// Resolve filter strategy
 	strategy := cfg.Registry.Find(command, cfg.Args)
+	if cfg.Filter != "" {
+		if s, ok := cfg.Registry.Lookup(cfg.Filter); ok {
+			strategy = s
+		}
+	}
+	// Run history is recorded even with --no-filter, so keep the resolved parser.
+	parser, _ := strategy.(filter.OutcomeParser)
+	if cfg.NoLog {
+		parser = nil
+	}
 	if cfg.NoFilter || cfg.NoLog {
 		strategy = &filter.PassthroughStrategy{}
 	}
+	// Strategies whose tools report on stderr get it buffered and filtered too.
+	stderrFilter, _ := strategy.(filter.StderrFilter)
+	// Secrets are masked in what reaches the agent, except with --no-log (pure
+	// passthrough) and for commands printing file contents, which must stay
+	// exact.
+	outputRedactor := cfg.Redactor
+	if cfg.NoLog || filter.PrintsSource(command, cfg.Args) {
+		outputRedactor = nil
+	}
 
 	if cfg.Verbose {
 		fmt.Fprintf(os.Stderr, "coc: command=%s args=%v filter=%s\n", command, cfg.Args, strategy.Name())
@@ -80,10 +124,17 @@ func Run(cfg Config) Result {
 	// the small-output cleanup path without double-close.
 
 	// Wrap logFile in a syncWriter so concurrent stdout/stderr goroutines
-	// don't interleave writes.
-	var logWriter io.Writer
+	// don't interleave writes. Redacting writers hold partial lines, so each
+	// stream gets its own.
+	var stdoutLog, stderrLog io.Writer
+	var redactWriters []*redact.Writer
 	if logFile != nil {
-		logWriter = &syncWriter{w: logFile}
+		logWriter := &syncWriter{w: logFile}
+		stdoutLog, stderrLog = logWriter, logWriter
+		if cfg.RedactLogs && cfg.Redactor != nil {
+			redactWriters = []*redact.Writer{redact.NewWriter(cfg.Redactor, logWriter), redact.NewWriter(cfg.Redactor, logWriter)}
+			stdoutLog, stderrLog = redactWriters[0], redactWriters[1]
+		}
 	}
 
 	// Set up command
@@ -142,14 +193,29 @@ func Run(cfg Config) Result {
 	// sequentially, both sides stall. Concurrent reads prevent this.
 	var stdoutBuf bytes.Buffer
 	var stdoutReader io.Reader = stdoutPipe
-	if logWriter != nil {
-		stdoutReader = io.TeeReader(stdoutPipe, logWriter)
+	if stdoutLog != nil {
+		stdoutReader = io.TeeReader(stdoutPipe, stdoutLog)
 	}
 
+	// Streamed stderr is redacted line by line on its way to the terminal.
 	var stderrWriters []io.Writer
-	stderrWriters = append(stderrWriters, os.Stderr)
-	if logWriter != nil {
-		stderrWriters = append(stderrWriters, logWriter)
+	var stderrRedact *redact.Writer
+	if stderrFilter == nil {
+		if outputRedactor != nil {
+			stderrRedact = redact.NewWriter(outputRedactor, os.Stderr)
+			stderrWriters = append(stderrWriters, stderrRedact)
+		} else {
+			stderrWriters = append(stderrWriters, os.Stderr)
+		}
+	}
+	if stderrLog != nil {
+		stderrWriters = append(stderrWriters, stderrLog)
+	}
+	// Compiler errors usually arrive on stderr, so outcome parsing and stderr
+	// filtering need a copy.
+	var stderrBuf bytes.Buffer
+	if parser != nil || stderrFilter != nil {
+		stderrWriters = append(stderrWriters, &stderrBuf)
 	}
 	stderrMulti := io.MultiWriter(stderrWriters...)
 
@@ -169,6 +235,14 @@ func Run(cfg Config) Result {
 	}()
 
 	wg.Wait()
+	for _, w := range redactWriters {
+		if err := w.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "coc: warning: error writing log: %v\n", err)
+		}
+	}
+	if stderrRedact != nil {
+		_ = stderrRedact.Close()
+	}
 
 	// Log warnings for copy errors
 	if stdoutCopyErr != nil {
@@ -193,18 +267,68 @@ func Run(cfg Config) Result {
 
 	// Apply filter
 	result := strategy.Filter(stdoutBuf.Bytes(), command, cfg.Args, exitCode)
+	var stderrResult filter.Result
+	if stderrFilter != nil {
+		stderrResult = stderrFilter.FilterStderr(stderrBuf.Bytes(), command, cfg.Args, exitCode)
+	}
+	if _, ok := strategy.(filter.RepeatFolder); ok {
+		result = filter.FoldRepeats(result)
+		if stderrFilter != nil {
+			stderrResult = filter.FoldRepeats(stderrResult)
+		}
+	}
 
-	// Write filtered stdout
-	if _, err := fmt.Fprint(os.Stdout, result.Filtered); err != nil {
+	// Compare with previous runs and record this one for next time
+	if parser != nil {
+		combined := make([]byte, 0, stdoutBuf.Len()+stderrBuf.Len())
+		combined = append(combined, stdoutBuf.Bytes()...)
+		combined = append(combined, stderrBuf.Bytes()...)
+		if outcome, ok := parser.ParseOutcome(combined, command, cfg.Args, exitCode); ok {
+			result = applyRunHistory(cfg, command, outcome, result)
+		}
+	}
+
+	// Shorten paths last, so they are also shortened in run history reports
+	_, rewritesPaths := strategy.(filter.PathRewriter)
+	if rewritesPaths && !cfg.NoFilter && !cfg.NoLog && !cfg.FullPaths {
+		if dir, err := os.Getwd(); err == nil {
+			shortener := filter.NewPathShortener(dir, history.RepoRoot(dir))
+			result = shortener.Shorten(result)
+			if stderrFilter != nil {
+				stderrResult = shortener.Shorten(stderrResult)
+			}
+		}
+	}
+
+	// Mask secrets before anything reaches the agent, even unfiltered output
+	if outputRedactor != nil {
+		result = redactResult(cfg, outputRedactor, result)
+		if stderrFilter != nil {
+			stderrResult = redactResult(cfg, outputRedactor, stderrResult)
+		}
+	}
+
+	// Write filtered stdout, then filtered stderr even if stdout is gone:
+	// compiler errors are usually on stderr.
+	_, stdoutErr := fmt.Fprint(os.Stdout, result.Filtered)
+	if stderrFilter != nil {
+		fmt.Fprint(os.Stderr, stderrResult.Filtered)
+	}
+	if stdoutErr != nil {
 		if logFile != nil {
 			logFile.Close()
 		}
 		return Result{ExitCode: exitCode, LogPath: logFilePath}
 	}
+	wasReduced := result.WasReduced || stderrResult.WasReduced
 
 	// Small output cleanup: if the raw output was small and wasn't reduced,
 	// the log file is disk clutter for zero benefit — remove it.
-	if logFile != nil && !result.WasReduced && stdoutBuf.Len() <= smallOutputThreshold {
+	rawLen := stdoutBuf.Len()
+	if stderrFilter != nil {
+		rawLen += stderrBuf.Len()
+	}
+	if logFile != nil && !wasReduced && rawLen <= smallOutputThreshold {
 		logFile.Close()
 		logFile = nil // prevent double close below
 		if err := os.Remove(logFilePath); err == nil {
@@ -220,13 +344,111 @@ func Run(cfg Config) Result {
 	}
 
 	// Write footer if output was reduced
-	if result.WasReduced && logFilePath != "" {
+	if wasReduced && logFilePath != "" {
 		fmt.Fprintf(os.Stderr, "\nOutput was reduced, see the full logs at %s\n", logFilePath)
 	}
 
 	return Result{ExitCode: exitCode, LogPath: logFilePath}
 }
 
+// redactResult masks the secrets in a curated result. Masked output counts as
+// reduced, so the footer points to the log file.
+func redactResult(cfg Config, r *redact.Redactor, result filter.Result) filter.Result {
+	redacted, n := r.Redact(result.Filtered)
+	if n == 0 {
+		return result
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "coc: redacted %d secrets\n", n)
+	}
+	return filter.Result{Filtered: redacted, WasReduced: true}
+}
+
+// applyRunHistory records the run's outcome and layers history-derived
+// information onto the curated result: the --since-last diff and flaky-test notes.
+func applyRunHistory(cfg Config, command string, outcome filter.Outcome, result filter.Result) filter.Result {
+	dir, err := os.Getwd()
+	if err != nil {
+		return result
+	}
+
+	report, found := compareWithLastRun(cfg, dir, command, outcome)
+	flakyNote := recordTestRun(cfg, dir, command, outcome)
+
+	if cfg.NoFilter {
+		return result
+	}
+	if cfg.SinceLast {
+		if found {
+			result = filter.Result{Filtered: report, WasReduced: true}
+		} else {
+			fmt.Fprintf(os.Stderr, "coc: no previous run of this command to compare against\n")
+		}
+	}
+	if flakyNote != "" {
+		if result.Filtered != "" && !strings.HasSuffix(result.Filtered, "\n") {
+			result.Filtered += "\n"
+		}
+		result.Filtered += flakyNote
+	}
+	return result
+}
+
+// compareWithLastRun diffs outcome against the stored snapshot of the previous
+// run of the same command in dir, then saves outcome as the new snapshot.
+// It returns the diff report and whether a previous run was found.
+func compareWithLastRun(cfg Config, dir, command string, outcome filter.Outcome) (string, bool) {
+	path := history.Path(cfg.LogDir, dir, command, cfg.Args)
+	prev, err := history.Load(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "coc: warning: %v\n", err)
+	}
+
+	snap := &history.Snapshot{
+		Command: command,
+		Args:    cfg.Args,
+		Dir:     dir,
+		Time:    time.Now(),
+		Outcome: outcome,
+	}
+	if err := history.Save(path, snap); err != nil {
+		fmt.Fprintf(os.Stderr, "coc: warning: could not save run history: %v\n", err)
+	}
+
+	if prev == nil {
+		return "", false
+	}
+	return filter.DiffOutcomes(prev.Outcome, outcome, prev.Time), true
+}
+
+// recordTestRun adds a test run to the repository's test history and returns
+// a notice for failing tests that also passed recently on the same code.
+// Runs without any test results (builds) are not recorded.
+func recordTestRun(cfg Config, dir, command string, outcome filter.Outcome) string {
+	if len(outcome.Failed) == 0 && len(outcome.Passed) == 0 {
+		return ""
+	}
+
+	root := history.RepoRoot(dir)
+	path := history.TestHistoryPath(cfg.LogDir, root)
+	run := history.TestRun{
+		Time:    time.Now(),
+		Code:    history.CodeFingerprint(root),
+		Command: strings.Join(append([]string{command}, cfg.Args...), " "),
+		Outcome: outcome,
+	}
+	var flaky []history.FlakyTest
+	err := history.UpdateTestHistory(path, root, func(h *history.TestHistory) {
+		flaky = h.FlakyTests(run)
+		h.Record(run)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "coc: warning: could not save test history: %v\n", err)
+	}
+
+	return history.FormatFlaky(flaky)
+}
+
 // isNotFound checks if the error is a command-not-found error.
 func isNotFound(err error) bool {
 	if err == nil {
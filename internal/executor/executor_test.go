@@ -2,10 +2,15 @@ package executor
 
 import (
 	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/Fuabioo/coc/internal/filter"
+	"github.com/Fuabioo/coc/internal/redact"
 )
 
 func TestSyncWriter(t *testing.T) {
@@ -137,3 +142,228 @@ func TestIsNotFound(t *testing.T) {
 		t.Error("isNotFound(nil) should be false")
 	}
 }
+
+// stderrStub reduces stderr to a fixed line, to exercise stderr filtering.
+type stderrStub struct{ filter.PassthroughStrategy }
+
+func (s *stderrStub) Name() string { return "stderr-stub" }
+
+func (s *stderrStub) FilterStderr(raw []byte, _ string, _ []string, _ int) filter.Result {
+	return filter.Result{Filtered: "reduced\n", WasReduced: true}
+}
+
+func TestRunStderrFilterKeepsRawLog(t *testing.T) {
+	cfg := Config{
+		Command:  "sh",
+		Args:     []string{"-c", "echo noisy stderr >&2"},
+		LogDir:   t.TempDir(),
+		Registry: filter.NewRegistry(&stderrStub{}),
+	}
+
+	result := Run(cfg)
+	if result.ExitCode != 0 {
+		t.Fatalf("sh should exit 0, got %d", result.ExitCode)
+	}
+	if result.LogPath == "" {
+		t.Fatal("reduced stderr should keep the log file")
+	}
+	data, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if !strings.Contains(string(data), "noisy stderr") {
+		t.Errorf("log should contain raw stderr, got %q", data)
+	}
+}
+
+// unmatchedStub is only used when selected by name.
+type unmatchedStub struct{ stderrStub }
+
+func (s *unmatchedStub) CanHandle(_ string, _ []string) bool { return false }
+
+func TestRunFilterOverridesMatch(t *testing.T) {
+	cfg := Config{
+		Command:  "sh",
+		Args:     []string{"-c", "echo noisy stderr >&2"},
+		LogDir:   t.TempDir(),
+		Filter:   "stderr-stub",
+		Registry: filter.NewRegistry(&unmatchedStub{}),
+	}
+
+	result := Run(cfg)
+	if result.ExitCode != 0 {
+		t.Fatalf("sh should exit 0, got %d", result.ExitCode)
+	}
+	if result.LogPath == "" {
+		t.Error("--filter should apply the named strategy, whose reduced stderr keeps the log file")
+	}
+
+	cfg.Filter = ""
+	if result := Run(cfg); result.LogPath != "" {
+		t.Errorf("without --filter the passthrough fallback should drop the small log, got %q", result.LogPath)
+	}
+}
+
+// foldingStub passes output through and opts in to repeated-block folding.
+type foldingStub struct{ filter.PassthroughStrategy }
+
+func (s *foldingStub) FoldRepeats() {}
+
+func TestRunFoldsRepeatsForOptedInStrategy(t *testing.T) {
+	cfg := Config{
+		Command:  "sh",
+		Args:     []string{"-c", "for i in 1 2 3 4; do echo retry $i; done"},
+		LogDir:   t.TempDir(),
+		Registry: filter.NewRegistry(&foldingStub{}),
+	}
+
+	result := Run(cfg)
+	if result.ExitCode != 0 {
+		t.Fatalf("sh should exit 0, got %d", result.ExitCode)
+	}
+	if result.LogPath == "" {
+		t.Error("folded output should count as reduced and keep the log file")
+	}
+}
+
+// pathStub passes output through and opts in to path shortening.
+type pathStub struct{ filter.PassthroughStrategy }
+
+func (s *pathStub) ShortenPaths() {}
+
+func TestRunShortensPathsOnlyForOptedInStrategy(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "echo " + filepath.Join(dir, "main.go") + ":3: undefined: x"
+	tests := []struct {
+		name     string
+		registry *filter.Registry
+		args     []string
+		want     string
+	}{
+		{"opted in", filter.NewRegistry(&pathStub{}), []string{"-c", script}, "main.go:3: undefined: x\n"},
+		{"passthrough", filter.NewRegistry(), []string{"-c", script}, dir + "/main.go:3: undefined: x\n"},
+		{"generic", filter.DefaultRegistry(), []string{"-c", "pwd"}, dir + "\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{Command: "sh", Args: tc.args, LogDir: t.TempDir(), Registry: tc.registry}
+			stdout, _ := captureOutput(t, func() { Run(cfg) })
+			if stdout != tc.want {
+				t.Errorf("stdout = %q, want %q", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunRedactsLogsWhenAsked(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{
+		Command:    "sh",
+		Args:       []string{"-c", "echo API_TOKEN=abc123; echo DB_PASSWORD=hunter2 >&2; echo done"},
+		LogDir:     t.TempDir(),
+		Redactor:   redactor,
+		RedactLogs: true,
+		Registry:   filter.NewRegistry(),
+	}
+
+	result := Run(cfg)
+	if result.LogPath == "" {
+		t.Fatal("redacted output should count as reduced and keep the log file")
+	}
+	data, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	log := string(data)
+	if strings.Contains(log, "abc123") || strings.Contains(log, "hunter2") {
+		t.Errorf("log should be redacted, got %q", log)
+	}
+	if !strings.Contains(log, "API_TOKEN=[REDACTED]") || !strings.Contains(log, "done") {
+		t.Errorf("log should keep the rest of the output, got %q", log)
+	}
+}
+
+// captureOutput runs f with os.Stdout and os.Stderr redirected and returns
+// what was written to each.
+func captureOutput(t *testing.T, f func()) (stdout, stderr string) {
+	t.Helper()
+	capture := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		orig := *target
+		*target = w
+		done := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			done <- string(data)
+		}()
+		return func() string {
+			*target = orig
+			w.Close()
+			return <-done
+		}
+	}
+	stopStdout := capture(&os.Stdout)
+	stopStderr := capture(&os.Stderr)
+	f()
+	return stopStdout(), stopStderr()
+}
+
+func TestRunRedactsUnfilteredOutput(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := "echo API_TOKEN=abc123; echo DB_PASSWORD=hunter2 >&2"
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantStdout string
+		wantStderr string
+	}{
+		{"no filter", Config{Command: "sh", Args: []string{"-c", script}, NoFilter: true, LogDir: t.TempDir()},
+			"API_TOKEN=[REDACTED]\n", "DB_PASSWORD=[REDACTED]\n"},
+		{"no log is passthrough", Config{Command: "sh", Args: []string{"-c", script}, NoLog: true},
+			"API_TOKEN=abc123\n", "DB_PASSWORD=hunter2\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Redactor = redactor
+			tc.cfg.Registry = filter.NewRegistry()
+			stdout, stderr := captureOutput(t, func() { Run(tc.cfg) })
+			if stdout != tc.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tc.wantStdout)
+			}
+			// The footer follows the streamed stderr
+			if !strings.HasPrefix(stderr, tc.wantStderr) {
+				t.Errorf("streamed stderr = %q, want prefix %q", stderr, tc.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunLeavesSourceOutputUnredacted(t *testing.T) {
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "config.go")
+	content := "const apiKey = \"k3y-v4lue\"\n"
+	if err := os.WriteFile(src, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Command: "cat", Args: []string{src}, LogDir: t.TempDir(), Redactor: redactor, Registry: filter.NewRegistry()}
+
+	stdout, _ := captureOutput(t, func() { Run(cfg) })
+	if stdout != content {
+		t.Errorf("stdout = %q, want the file as is", stdout)
+	}
+}
@@ -35,6 +35,9 @@ var (
 	cargoTestPassedCountRe = regexp.MustCompile(`(\d+) passed`)
 )
 
+// ShortenPaths opts in to path shortening for panic locations.
+func (s *CargoTestStrategy) ShortenPaths() {}
+
 func (s *CargoTestStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
 	filterName := s.Name()
 	defer func() {
@@ -128,6 +131,54 @@ func (s *CargoTestStrategy) Filter(raw []byte, command string, args []string, ex
 	return Result{Filtered: filtered, WasReduced: wasReduced}
 }
 
+// Package-level compiled regexes for CargoTestStrategy.ParseOutcome.
+var (
+	cargoTestLineRe = regexp.MustCompile(`^test (\S+) \.\.\. (ok|FAILED)`)
+	cargoLocationRe = regexp.MustCompile(`^\s*--> (\S+:\d+:\d+)`)
+	// cargoErrorSummaryRe matches the trailing "error: ..." lines cargo prints
+	// after the real diagnostics; they are not errors in their own right.
+	cargoErrorSummaryRe = regexp.MustCompile(`^error: (aborting due to|could not compile|test failed|build failed)`)
+)
+
+// ParseOutcome extracts per-test results and compiler errors from `cargo test` output.
+func (s *CargoTestStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	found := false
+	for _, line := range lines {
+		if m := cargoTestLineRe.FindStringSubmatch(line); len(m) > 2 {
+			if m[2] == "ok" {
+				o.Passed = append(o.Passed, m[1])
+			} else {
+				o.Failed = append(o.Failed, m[1])
+			}
+			found = true
+		}
+	}
+	o.Errors = parseCargoErrors(lines)
+	return o, found || len(o.Errors) > 0
+}
+
+// parseCargoErrors returns rustc error headlines prefixed with their primary
+// location, e.g. "src/main.rs:3:5: error[E0425]: cannot find value `x`".
+func parseCargoErrors(lines []string) []string {
+	var errs []string
+	for i, line := range lines {
+		if !cargoBuildErrorRe.MatchString(line) || cargoErrorSummaryRe.MatchString(line) {
+			continue
+		}
+		entry := line
+		for j := i + 1; j < len(lines) && j <= i+3; j++ {
+			if m := cargoLocationRe.FindStringSubmatch(lines[j]); len(m) > 1 {
+				entry = m[1] + ": " + line
+				break
+			}
+		}
+		errs = append(errs, entry)
+	}
+	return errs
+}
+
 // countCargoTestsFromResult extracts the passed count from a "test result: ok. N passed; ..." line.
 func countCargoTestsFromResult(line string) int {
 	m := cargoTestPassedCountRe.FindStringSubmatch(line)
@@ -145,20 +196,20 @@ func countCargoTestsFromResult(line string) int {
 // CargoBuildStrategy
 // ---------------------------------------------------------------------------
 
-// CargoBuildStrategy filters `cargo build`, `cargo check`, and `cargo clippy` output.
+// CargoBuildStrategy filters `cargo build` output. `cargo check` and
+// `cargo clippy` have their own lint-grouping strategies (see cargo_lint.go).
 type CargoBuildStrategy struct{}
 
 func (s *CargoBuildStrategy) Name() string { return "cargo-build" }
 
 func (s *CargoBuildStrategy) CanHandle(command string, args []string) bool {
-	if command != "cargo" {
-		return false
-	}
-	return isSubcommand(args, "build", cargoValueFlags) ||
-		isSubcommand(args, "check", cargoValueFlags) ||
-		isSubcommand(args, "clippy", cargoValueFlags)
+	return command == "cargo" && isSubcommand(args, "build", cargoValueFlags)
 }
 
+// FoldRepeats folds the warnings cargo prints again for each crate that
+// triggers them.
+func (s *CargoBuildStrategy) FoldRepeats() {}
+
 // Package-level compiled regexes for CargoBuildStrategy.
 var (
 	cargoBuildErrorRe   = regexp.MustCompile(`^error\[|^error:`)
@@ -170,6 +221,10 @@ var (
 	cargoBuildPipeRe    = regexp.MustCompile(`^\s*\d*\s*\|`)
 )
 
+// ShortenPaths opts in to path shortening: rustc errors point into the
+// repository and into $CARGO_HOME.
+func (s *CargoBuildStrategy) ShortenPaths() {}
+
 func (s *CargoBuildStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
 	filterName := s.Name()
 	defer func() {
@@ -221,3 +276,14 @@ func (s *CargoBuildStrategy) Filter(raw []byte, command string, args []string, e
 
 	return Result{Filtered: filtered, WasReduced: true}
 }
+
+// FilterStderr filters compiler diagnostics, which cargo writes to stderr.
+func (s *CargoBuildStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ParseOutcome extracts compiler errors from `cargo build` output.
+func (s *CargoBuildStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	return Outcome{Errors: parseCargoErrors(lines)}, true
+}
@@ -0,0 +1,334 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// CargoJSONStrategy
+// ---------------------------------------------------------------------------
+
+// CargoJSONStrategy filters `cargo build/check/clippy --message-format=json`
+// output. It decodes compiler-message records into deduplicated diagnostics
+// grouped by file and collapses artifact records into counts.
+type CargoJSONStrategy struct{}
+
+func (s *CargoJSONStrategy) Name() string { return "cargo-json" }
+
+func (s *CargoJSONStrategy) CanHandle(command string, args []string) bool {
+	if command != "cargo" || !hasCargoJSONFormat(args) {
+		return false
+	}
+	return isSubcommand(args, "build", cargoValueFlags) ||
+		isSubcommand(args, "check", cargoValueFlags) ||
+		isSubcommand(args, "clippy", cargoValueFlags)
+}
+
+// hasCargoJSONFormat reports whether args select one of cargo's JSON message formats.
+func hasCargoJSONFormat(args []string) bool {
+	for i, a := range args {
+		if a == "--" {
+			return false
+		}
+		value := ""
+		if after, ok := strings.CutPrefix(a, "--message-format="); ok {
+			value = after
+		} else if a == "--message-format" && i+1 < len(args) {
+			value = args[i+1]
+		}
+		if strings.HasPrefix(value, "json") {
+			return true
+		}
+	}
+	return false
+}
+
+// cargoRecord is one line of cargo's JSON message stream.
+type cargoRecord struct {
+	Reason  string        `json:"reason"`
+	Message *rustcMessage `json:"message"`
+	Fresh   bool          `json:"fresh"`
+	Success *bool         `json:"success"`
+}
+
+// rustcMessage is a rustc JSON diagnostic.
+type rustcMessage struct {
+	Message  string         `json:"message"`
+	Code     *rustcCode     `json:"code"`
+	Level    string         `json:"level"`
+	Spans    []rustcSpan    `json:"spans"`
+	Children []rustcMessage `json:"children"`
+}
+
+type rustcCode struct {
+	Code string `json:"code"`
+}
+
+type rustcSpan struct {
+	FileName    string          `json:"file_name"`
+	LineStart   int             `json:"line_start"`
+	ColumnStart int             `json:"column_start"`
+	IsPrimary   bool            `json:"is_primary"`
+	Label       *string         `json:"label"`
+	Text        []rustcSpanText `json:"text"`
+}
+
+type rustcSpanText struct {
+	Text string `json:"text"`
+}
+
+// cargoDiagnostic is a flattened, deduplicatable rustc diagnostic.
+type cargoDiagnostic struct {
+	level   string
+	code    string
+	message string
+	file    string
+	line    int
+	col     int
+	snippet string
+	label   string
+	help    string // first help/note child, one line
+}
+
+// header renders "error[E0425]: message" style headlines.
+func (d cargoDiagnostic) header() string {
+	if d.code != "" {
+		return fmt.Sprintf("%s[%s]: %s", d.level, d.code, d.message)
+	}
+	return fmt.Sprintf("%s: %s", d.level, d.message)
+}
+
+// cargoJSONRun is the decoded form of a cargo JSON message stream.
+type cargoJSONRun struct {
+	diagnostics  []cargoDiagnostic
+	artifacts    int
+	fresh        int
+	buildScripts int
+	finished     *bool
+	other        []string
+	records      int
+}
+
+// rustcSummaryMessages are span-less messages rustc emits after the real diagnostics.
+var rustcSummaryMessages = []string{"aborting due to", "could not compile"}
+
+// parseCargoJSON decodes cargo's JSON message stream, deduplicating diagnostics
+// that several targets (lib, bin, tests) report identically.
+func parseCargoJSON(cleaned string) *cargoJSONRun {
+	run := &cargoJSONRun{}
+	seen := map[cargoDiagnostic]bool{}
+
+	for _, line := range strings.Split(cleaned, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var rec cargoRecord
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &rec) != nil || rec.Reason == "" {
+			run.other = append(run.other, line)
+			continue
+		}
+		run.records++
+
+		switch rec.Reason {
+		case "compiler-artifact":
+			run.artifacts++
+			if rec.Fresh {
+				run.fresh++
+			}
+		case "build-script-executed":
+			run.buildScripts++
+		case "build-finished":
+			run.finished = rec.Success
+		case "compiler-message":
+			if rec.Message == nil || isRustcSummary(rec.Message) {
+				continue
+			}
+			d := flattenRustcMessage(rec.Message)
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			run.diagnostics = append(run.diagnostics, d)
+		}
+	}
+	return run
+}
+
+// isRustcSummary reports whether m is a trailing summary such as
+// "aborting due to 2 previous errors" or "3 warnings emitted".
+func isRustcSummary(m *rustcMessage) bool {
+	if len(m.Spans) > 0 {
+		return false
+	}
+	for _, prefix := range rustcSummaryMessages {
+		if strings.HasPrefix(m.Message, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(m.Message, "emitted")
+}
+
+// flattenRustcMessage reduces a rustc diagnostic to its primary span, a
+// one-line source snippet and its first help/note.
+func flattenRustcMessage(m *rustcMessage) cargoDiagnostic {
+	d := cargoDiagnostic{level: m.Level, message: m.Message}
+	if m.Code != nil {
+		d.code = m.Code.Code
+	}
+	for _, sp := range m.Spans {
+		if !sp.IsPrimary {
+			continue
+		}
+		d.file, d.line, d.col = sp.FileName, sp.LineStart, sp.ColumnStart
+		if len(sp.Text) > 0 {
+			d.snippet = strings.TrimSpace(sp.Text[0].Text)
+		}
+		if sp.Label != nil {
+			d.label = *sp.Label
+		}
+		break
+	}
+	for _, c := range m.Children {
+		if c.Level == "help" || c.Level == "note" {
+			d.help = c.Level + ": " + firstLine(c.Message)
+			break
+		}
+	}
+	return d
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// rustcLevelRank orders diagnostics so errors come before warnings.
+func rustcLevelRank(level string) int {
+	switch level {
+	case "error", "error: internal compiler error":
+		return 0
+	case "warning":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ShortenPaths opts in to path shortening for rendered diagnostic locations.
+func (s *CargoJSONStrategy) ShortenPaths() {}
+
+func (s *CargoJSONStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	run := parseCargoJSON(cleaned)
+
+	// Not a cargo JSON stream — nothing to decode
+	if run.records == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	// Group diagnostics by file, keeping first-seen file order
+	byFile := map[string][]cargoDiagnostic{}
+	var files []string
+	errCount, warnCount := 0, 0
+	for _, d := range run.diagnostics {
+		if _, ok := byFile[d.file]; !ok {
+			files = append(files, d.file)
+		}
+		byFile[d.file] = append(byFile[d.file], d)
+		switch rustcLevelRank(d.level) {
+		case 0:
+			errCount++
+		case 1:
+			warnCount++
+		}
+	}
+
+	var out []string
+	for _, file := range files {
+		diags := byFile[file]
+		sort.SliceStable(diags, func(i, j int) bool {
+			if ri, rj := rustcLevelRank(diags[i].level), rustcLevelRank(diags[j].level); ri != rj {
+				return ri < rj
+			}
+			return diags[i].line < diags[j].line
+		})
+		if file == "" {
+			out = append(out, "(no location):")
+		} else {
+			out = append(out, file+":")
+		}
+		for _, d := range diags {
+			if d.file != "" {
+				out = append(out, fmt.Sprintf("  %d:%d %s", d.line, d.col, d.header()))
+			} else {
+				out = append(out, "  "+d.header())
+			}
+			if d.snippet != "" {
+				snippet := "      | " + d.snippet
+				if d.label != "" {
+					snippet += "  <- " + d.label
+				}
+				out = append(out, snippet)
+			}
+			if d.help != "" {
+				out = append(out, "      = "+d.help)
+			}
+		}
+	}
+
+	out = append(out, run.other...)
+
+	if run.artifacts > 0 || run.buildScripts > 0 {
+		out = append(out, fmt.Sprintf("artifacts: %d (%d fresh, %d compiled), %d build scripts run",
+			run.artifacts, run.fresh, run.artifacts-run.fresh, run.buildScripts))
+	}
+	fileCount := len(files)
+	if _, ok := byFile[""]; ok {
+		fileCount--
+	}
+	summary := fmt.Sprintf("%d errors, %d warnings in %d files", errCount, warnCount, fileCount)
+	if run.finished != nil {
+		if *run.finished {
+			summary += " (build finished)"
+		} else {
+			summary += " (build failed)"
+		}
+	}
+	out = append(out, summary)
+
+	filtered := strings.Join(out, "\n") + "\n"
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// ParseOutcome extracts error diagnostics as "file:line:col: error[code]: message"
+// lines, matching CargoBuildStrategy's format so runs compare across formats.
+func (s *CargoJSONStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	run := parseCargoJSON(StripANSIString(string(raw)))
+	var o Outcome
+	for _, d := range run.diagnostics {
+		if rustcLevelRank(d.level) != 0 {
+			continue
+		}
+		if d.file != "" {
+			o.Errors = append(o.Errors, fmt.Sprintf("%s:%d:%d: %s", d.file, d.line, d.col, d.header()))
+		} else {
+			o.Errors = append(o.Errors, d.header())
+		}
+	}
+	return o, run.records > 0
+}
@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// CargoJSONStrategy
+// ---------------------------------------------------------------------------
+
+func TestCargoJSONStrategy_CanHandle(t *testing.T) {
+	s := &CargoJSONStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"build json", "cargo", []string{"build", "--message-format=json"}, true},
+		{"check json short", "cargo", []string{"check", "--message-format=json-diagnostic-short"}, true},
+		{"clippy json separate value", "cargo", []string{"clippy", "--message-format", "json"}, true},
+		{"build human", "cargo", []string{"build", "--message-format=human"}, false},
+		{"build plain", "cargo", []string{"build"}, false},
+		{"test json", "cargo", []string{"test", "--message-format=json"}, false},
+		{"json after --", "cargo", []string{"clippy", "--", "--message-format=json"}, false},
+		{"not cargo", "go", []string{"build", "--message-format=json"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCargoJSONStrategy_Name(t *testing.T) {
+	s := &CargoJSONStrategy{}
+	if got := s.Name(); got != "cargo-json" {
+		t.Errorf("Name() = %q, want %q", got, "cargo-json")
+	}
+}
+
+const cargoJSONFixture = `{"reason":"compiler-artifact","package_id":"libc 0.2.150","fresh":true}
+{"reason":"compiler-artifact","package_id":"serde 1.0.0","fresh":true}
+{"reason":"build-script-executed","package_id":"demo 0.1.0"}
+{"reason":"compiler-message","package_id":"demo 0.1.0","message":{"message":"unused variable: ` + "`y`" + `","code":{"code":"unused_variables","explanation":null},"level":"warning","spans":[{"file_name":"src/main.rs","line_start":4,"column_start":9,"is_primary":true,"label":null,"text":[{"text":"    let y = 1;"}]}],"children":[{"message":"if this is intentional, prefix it with an underscore: ` + "`_y`" + `","level":"help","spans":[],"children":[]}],"rendered":"warning: unused variable"}}
+{"reason":"compiler-message","package_id":"demo 0.1.0","message":{"message":"cannot find value ` + "`x`" + ` in this scope","code":{"code":"E0425","explanation":"long text"},"level":"error","spans":[{"file_name":"src/main.rs","line_start":3,"column_start":13,"is_primary":true,"label":"not found in this scope","text":[{"text":"    let z = x + 1;"}]}],"children":[],"rendered":"error[E0425]: ..."}}
+{"reason":"compiler-message","package_id":"demo 0.1.0","message":{"message":"cannot find value ` + "`x`" + ` in this scope","code":{"code":"E0425","explanation":"long text"},"level":"error","spans":[{"file_name":"src/main.rs","line_start":3,"column_start":13,"is_primary":true,"label":"not found in this scope","text":[{"text":"    let z = x + 1;"}]}],"children":[],"rendered":"error[E0425]: ..."}}
+{"reason":"compiler-message","package_id":"demo 0.1.0","message":{"message":"aborting due to 1 previous error","code":null,"level":"error","spans":[],"children":[],"rendered":"error: aborting"}}
+{"reason":"compiler-message","package_id":"demo 0.1.0","message":{"message":"1 warning emitted","code":null,"level":"warning","spans":[],"children":[],"rendered":"warning: 1 warning emitted"}}
+{"reason":"compiler-artifact","package_id":"util 0.1.0","fresh":false}
+{"reason":"build-finished","success":false}
+`
+
+func TestCargoJSONStrategy_Filter(t *testing.T) {
+	s := &CargoJSONStrategy{}
+	result := s.Filter([]byte(cargoJSONFixture), "cargo", []string{"build", "--message-format=json"}, 101)
+
+	want := "src/main.rs:\n" +
+		"  3:13 error[E0425]: cannot find value `x` in this scope\n" +
+		"      | let z = x + 1;  <- not found in this scope\n" +
+		"  4:9 warning[unused_variables]: unused variable: `y`\n" +
+		"      | let y = 1;\n" +
+		"      = help: if this is intentional, prefix it with an underscore: `_y`\n" +
+		"artifacts: 3 (2 fresh, 1 compiled), 1 build scripts run\n" +
+		"1 errors, 1 warnings in 1 files (build failed)\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestCargoJSONStrategy_Filter_NotJSON(t *testing.T) {
+	s := &CargoJSONStrategy{}
+	input := "error: unexpected argument '--bogus' found\n"
+	result := s.Filter([]byte(input), "cargo", []string{"build", "--message-format=json"}, 1)
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("non-JSON output should pass through, got %q", result.Filtered)
+	}
+}
+
+func TestCargoJSONStrategy_ParseOutcome(t *testing.T) {
+	s := &CargoJSONStrategy{}
+	o, ok := s.ParseOutcome([]byte(cargoJSONFixture), "cargo", []string{"build", "--message-format=json"}, 101)
+	if !ok {
+		t.Fatal("expected outcome to be parsed")
+	}
+	want := "src/main.rs:3:13: error[E0425]: cannot find value `x` in this scope"
+	if len(o.Errors) != 1 || o.Errors[0] != want {
+		t.Errorf("Errors = %v, want [%s]", o.Errors, want)
+	}
+}
+
+func TestStructuredArgs_Cargo(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"build", []string{"build", "--release"}, []string{"build", "--message-format=json", "--release"}},
+		{"clippy with manifest", []string{"--manifest-path", "x/Cargo.toml", "clippy"}, []string{"--manifest-path", "x/Cargo.toml", "clippy", "--message-format=json"}},
+		{"explicit format kept", []string{"check", "--message-format=short"}, []string{"check", "--message-format=short"}},
+		{"test untouched", []string{"test"}, []string{"test"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := StructuredArgs("cargo", tc.args)
+			if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("StructuredArgs(cargo, %v) = %v, want %v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// CargoLintStrategy
+// ---------------------------------------------------------------------------
+
+// CargoLintStrategy filters `cargo clippy` and `cargo check` output by grouping
+// warnings by lint. Subcommand ("clippy" or "check") selects the command it
+// handles; the registry holds one instance for each.
+type CargoLintStrategy struct {
+	Subcommand string
+}
+
+func (s *CargoLintStrategy) Name() string { return "cargo-" + s.Subcommand }
+
+func (s *CargoLintStrategy) CanHandle(command string, args []string) bool {
+	return command == "cargo" && isSubcommand(args, s.Subcommand, cargoValueFlags)
+}
+
+// ShortenPaths opts in to path shortening for lint locations.
+func (s *CargoLintStrategy) ShortenPaths() {}
+
+func (s *CargoLintStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+	return filterCargoLints(raw)
+}
+
+// FilterStderr groups lints, which cargo writes to stderr.
+func (s *CargoLintStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ParseOutcome extracts compiler errors, like CargoBuildStrategy.
+func (s *CargoLintStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	return Outcome{Errors: parseCargoErrors(lines)}, true
+}
+
+// Package-level compiled regexes for cargo lint grouping.
+var (
+	// cargoDiagHeadRe matches a rustc diagnostic headline: level, optional code, message.
+	cargoDiagHeadRe = regexp.MustCompile(`^(error|warning)(?:\[([^\]]+)\])?: (.*)$`)
+	// cargoLintAttrRe extracts the lint name from `#[warn(clippy::foo)]` style notes.
+	cargoLintAttrRe = regexp.MustCompile("#\\[(?:warn|deny|forbid)\\(([a-z0-9_:]+)\\)\\]")
+	// cargoLintURLRe extracts the lint name from clippy's lint-help URL.
+	cargoLintURLRe = regexp.MustCompile(`rust-clippy/[^ ]*index\.html#([a-z0-9_]+)`)
+	// cargoNoiseHeadRe matches cargo's own summary headlines, which are not diagnostics.
+	cargoNoiseHeadRe = regexp.MustCompile(`^warning: .* generated \d+ warnings?|^warning: build failed|^error: aborting due to`)
+	// cargoStatusRe matches cargo progress lines (Compiling, Checking, ...).
+	cargoStatusRe = regexp.MustCompile(`^\s+(Compiling|Checking|Downloaded|Downloading|Updating|Locking|Adding|Blocking|Fresh|Documenting|Running)\s`)
+	// cargoBacktickRe matches `code` spans inside diagnostic messages.
+	cargoBacktickRe = regexp.MustCompile("`[^`]*`")
+)
+
+const (
+	// cargoLintShown is how many occurrences of each lint are listed.
+	cargoLintShown = 3
+	// cargoLintFilesShown is how many files are named in the "and N more" line.
+	cargoLintFilesShown = 3
+)
+
+// cargoDiagBlock is one rustc diagnostic in human-readable form.
+type cargoDiagBlock struct {
+	level    string
+	code     string
+	message  string
+	location string // "src/lib.rs:10:5"
+	lines    []string
+}
+
+// file returns the file part of the block's location.
+func (b *cargoDiagBlock) file() string {
+	if i := strings.Index(b.location, ":"); i >= 0 {
+		return b.location[:i]
+	}
+	return b.location
+}
+
+// cargoLintGroup collects occurrences of one lint.
+type cargoLintGroup struct {
+	name   string
+	blocks []*cargoDiagBlock
+	help   string // the lint's help URL line, kept once
+	note   string // the lint's `#[warn(...)]` note line, kept once
+}
+
+// parseCargoDiagBlocks splits rustc human-readable output into diagnostic
+// blocks and the remaining non-diagnostic lines worth keeping (e.g. Finished).
+func parseCargoDiagBlocks(lines []string) ([]*cargoDiagBlock, []string) {
+	var blocks []*cargoDiagBlock
+	var rest []string
+	var cur *cargoDiagBlock
+
+	for _, line := range lines {
+		if m := cargoDiagHeadRe.FindStringSubmatch(line); m != nil && !cargoNoiseHeadRe.MatchString(line) && !cargoErrorSummaryRe.MatchString(line) {
+			cur = &cargoDiagBlock{level: m[1], code: m[2], message: m[3], lines: []string{line}}
+			blocks = append(blocks, cur)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		if cur != nil {
+			if m := cargoLocationRe.FindStringSubmatch(line); len(m) > 1 && cur.location == "" {
+				cur.location = m[1]
+			}
+			cur.lines = append(cur.lines, line)
+			continue
+		}
+		if cargoStatusRe.MatchString(line) || cargoNoiseHeadRe.MatchString(line) {
+			continue
+		}
+		rest = append(rest, line)
+	}
+	return blocks, rest
+}
+
+// cargoLintName derives a lint's identity from the block: the `#[warn(...)]`
+// note, the clippy help URL, the diagnostic code, or else the message with
+// backtick spans masked. learned maps masked messages to names seen earlier,
+// since rustc only prints the `#[warn(...)]` note on the first occurrence.
+func cargoLintName(b *cargoDiagBlock, learned map[string]string) string {
+	masked := cargoBacktickRe.ReplaceAllString(b.message, "`_`")
+	for _, line := range b.lines {
+		if m := cargoLintAttrRe.FindStringSubmatch(line); len(m) > 1 {
+			learned[masked] = m[1]
+			return m[1]
+		}
+	}
+	for _, line := range b.lines {
+		if m := cargoLintURLRe.FindStringSubmatch(line); len(m) > 1 {
+			learned[masked] = "clippy::" + m[1]
+			return "clippy::" + m[1]
+		}
+	}
+	if name, ok := learned[masked]; ok {
+		return name
+	}
+	if b.code != "" {
+		return b.code
+	}
+	return masked
+}
+
+// filterCargoLints shows errors in full and groups warnings by lint name and
+// file, capping repeated lints and keeping one help URL and note per lint.
+func filterCargoLints(raw []byte) Result {
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	blocks, rest := parseCargoDiagBlocks(lines)
+	if len(blocks) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	var groups []*cargoLintGroup
+	groupIndex := map[string]*cargoLintGroup{}
+	learned := map[string]string{}
+	errCount, warnCount := 0, 0
+	files := map[string]bool{}
+
+	// Errors first, in full
+	for _, b := range blocks {
+		if b.location != "" {
+			files[b.file()] = true
+		}
+		if b.level == "error" {
+			errCount++
+			out = append(out, b.lines...)
+			out = append(out, "")
+			continue
+		}
+		warnCount++
+		name := cargoLintName(b, learned)
+		g, ok := groupIndex[name]
+		if !ok {
+			g = &cargoLintGroup{name: name}
+			groupIndex[name] = g
+			groups = append(groups, g)
+		}
+		g.blocks = append(g.blocks, b)
+		for _, line := range b.lines {
+			trimmed := strings.TrimSpace(line)
+			if g.help == "" && strings.HasPrefix(trimmed, "= help: for further information visit") {
+				g.help = trimmed
+			}
+			if g.note == "" && strings.HasPrefix(trimmed, "= note:") && cargoLintAttrRe.MatchString(trimmed) {
+				g.note = trimmed
+			}
+		}
+	}
+
+	// Warnings grouped by lint
+	for _, g := range groups {
+		out = append(out, renderCargoLintGroup(g)...)
+	}
+
+	out = append(out, rest...)
+	out = append(out, fmt.Sprintf("%d errors, %d warnings (%d lints) in %d files", errCount, warnCount, len(groups), len(files)))
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// renderCargoLintGroup renders one lint: a headline with counts, the first few
+// occurrences, an "and N more in ..." line, and the lint's help/note once.
+func renderCargoLintGroup(g *cargoLintGroup) []string {
+	fileOrder := []string{}
+	perFile := map[string]int{}
+	for _, b := range g.blocks {
+		f := b.file()
+		if perFile[f] == 0 {
+			fileOrder = append(fileOrder, f)
+		}
+		perFile[f]++
+	}
+
+	out := []string{fmt.Sprintf("warning: %s (%d in %d files)", g.name, len(g.blocks), len(fileOrder))}
+	for i, b := range g.blocks {
+		if i >= cargoLintShown {
+			break
+		}
+		loc := b.location
+		if loc == "" {
+			loc = "(no location)"
+		}
+		out = append(out, fmt.Sprintf("  %s: %s", loc, b.message))
+	}
+
+	if extra := len(g.blocks) - cargoLintShown; extra > 0 {
+		remaining := map[string]int{}
+		var remainingOrder []string
+		for _, b := range g.blocks[cargoLintShown:] {
+			f := b.file()
+			if remaining[f] == 0 {
+				remainingOrder = append(remainingOrder, f)
+			}
+			remaining[f]++
+		}
+		var parts []string
+		for i, f := range remainingOrder {
+			if i >= cargoLintFilesShown {
+				parts = append(parts, fmt.Sprintf("%d other files", len(remainingOrder)-cargoLintFilesShown))
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%s (%d)", f, remaining[f]))
+		}
+		out = append(out, fmt.Sprintf("  ... and %d more in %s", extra, strings.Join(parts, ", ")))
+	}
+
+	if g.help != "" {
+		out = append(out, "  "+g.help)
+	}
+	if g.note != "" {
+		out = append(out, "  "+g.note)
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// CargoLintStrategy
+// ---------------------------------------------------------------------------
+
+func TestCargoLintStrategy_Clippy_CanHandle(t *testing.T) {
+	s := &CargoLintStrategy{Subcommand: "clippy"}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"cargo clippy", "cargo", []string{"clippy"}, true},
+		{"cargo clippy with manifest", "cargo", []string{"--manifest-path", "a/Cargo.toml", "clippy", "--all-targets"}, true},
+		{"cargo check", "cargo", []string{"check"}, false},
+		{"cargo build", "cargo", []string{"build"}, false},
+		{"not cargo", "clippy", nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCargoLintStrategy_Check_CanHandle(t *testing.T) {
+	s := &CargoLintStrategy{Subcommand: "check"}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"cargo check", "cargo", []string{"check"}, true},
+		{"cargo check workspace", "cargo", []string{"check", "--workspace"}, true},
+		{"cargo clippy", "cargo", []string{"clippy"}, false},
+		{"not cargo", "check", nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCargoLintStrategy_Name(t *testing.T) {
+	if got := (&CargoLintStrategy{Subcommand: "clippy"}).Name(); got != "cargo-clippy" {
+		t.Errorf("Name() = %q, want %q", got, "cargo-clippy")
+	}
+	if got := (&CargoLintStrategy{Subcommand: "check"}).Name(); got != "cargo-check" {
+		t.Errorf("Name() = %q, want %q", got, "cargo-check")
+	}
+}
+
+// clippyWarning renders a clippy needless_return warning at the given location.
+// The `#[warn(...)]` note is only printed on the first occurrence, as clippy does.
+func clippyWarning(file string, line int, first bool) string {
+	s := "warning: unneeded `return` statement\n" +
+		fmt.Sprintf("  --> %s:%d:5\n", file, line) +
+		"   |\n" +
+		fmt.Sprintf("%d |     return x;\n", line) +
+		"   |     ^^^^^^^^^\n" +
+		"   |\n" +
+		"   = help: for further information visit https://rust-lang.github.io/rust-clippy/master/index.html#needless_return\n"
+	if first {
+		s += "   = note: `#[warn(clippy::needless_return)]` on by default\n"
+	}
+	return s + "\n"
+}
+
+func TestCargoLintStrategy_Clippy_Filter_GroupsLints(t *testing.T) {
+	s := &CargoLintStrategy{Subcommand: "clippy"}
+
+	input := "    Checking demo v0.1.0 (/work/demo)\n" +
+		clippyWarning("src/lib.rs", 10, true) +
+		clippyWarning("src/lib.rs", 20, false) +
+		clippyWarning("src/lib.rs", 30, false) +
+		clippyWarning("src/lib.rs", 40, false) +
+		clippyWarning("src/util.rs", 5, false) +
+		"warning: unused variable: `y`\n" +
+		" --> src/main.rs:4:9\n" +
+		"  |\n" +
+		"4 |     let y = 1;\n" +
+		"  |         ^ help: if this is intentional, prefix it with an underscore: `_y`\n" +
+		"  |\n" +
+		"  = note: `#[warn(unused_variables)]` on by default\n" +
+		"\n" +
+		"error[E0425]: cannot find value `x` in this scope\n" +
+		" --> src/main.rs:3:13\n" +
+		"  |\n" +
+		"3 |     let z = x + 1;\n" +
+		"  |             ^ not found in this scope\n" +
+		"\n" +
+		"warning: `demo` (lib) generated 6 warnings\n" +
+		"error: could not compile `demo` (bin \"demo\") due to 1 previous error\n"
+
+	result := s.Filter([]byte(input), "cargo", []string{"clippy"}, 101)
+
+	want := "error[E0425]: cannot find value `x` in this scope\n" +
+		" --> src/main.rs:3:13\n" +
+		"  |\n" +
+		"3 |     let z = x + 1;\n" +
+		"  |             ^ not found in this scope\n" +
+		"\n" +
+		"warning: clippy::needless_return (5 in 2 files)\n" +
+		"  src/lib.rs:10:5: unneeded `return` statement\n" +
+		"  src/lib.rs:20:5: unneeded `return` statement\n" +
+		"  src/lib.rs:30:5: unneeded `return` statement\n" +
+		"  ... and 2 more in src/lib.rs (1), src/util.rs (1)\n" +
+		"  = help: for further information visit https://rust-lang.github.io/rust-clippy/master/index.html#needless_return\n" +
+		"  = note: `#[warn(clippy::needless_return)]` on by default\n" +
+		"warning: unused_variables (1 in 1 files)\n" +
+		"  src/main.rs:4:9: unused variable: `y`\n" +
+		"  = note: `#[warn(unused_variables)]` on by default\n" +
+		"error: could not compile `demo` (bin \"demo\") due to 1 previous error\n" +
+		"1 errors, 6 warnings (2 lints) in 3 files\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestCargoLintStrategy_Check_Filter_LearnsLintNameFromFirstNote(t *testing.T) {
+	s := &CargoLintStrategy{Subcommand: "check"}
+
+	var b strings.Builder
+	for i, name := range []string{"a", "b", "c"} {
+		b.WriteString(fmt.Sprintf("warning: unused variable: `%s`\n", name))
+		b.WriteString(fmt.Sprintf(" --> src/main.rs:%d:9\n", i+1))
+		b.WriteString("  |\n")
+		if i == 0 {
+			b.WriteString("  = note: `#[warn(unused_variables)]` on by default\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("    Finished `dev` profile [unoptimized + debuginfo] target(s) in 0.50s\n")
+
+	result := s.Filter([]byte(b.String()), "cargo", []string{"check"}, 0)
+
+	if !strings.Contains(result.Filtered, "warning: unused_variables (3 in 1 files)") {
+		t.Errorf("later occurrences should join the lint learned from the first note, got:\n%s", result.Filtered)
+	}
+	if strings.Count(result.Filtered, "#[warn(unused_variables)]") != 1 {
+		t.Errorf("lint note should be kept once, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "Finished `dev` profile") {
+		t.Errorf("Finished line should be kept, got:\n%s", result.Filtered)
+	}
+}
+
+func TestCargoLintStrategy_Check_Filter_NoDiagnostics(t *testing.T) {
+	s := &CargoLintStrategy{Subcommand: "check"}
+	input := strings.Repeat("    Checking dep v1.0.0\n", 12) + "    Finished dev target(s) in 1.0s\n"
+	result := s.Filter([]byte(input), "cargo", []string{"check"}, 0)
+	if result.WasReduced || result.Filtered != input {
+		t.Errorf("output without diagnostics should pass through, got %q", result.Filtered)
+	}
+}
@@ -192,8 +192,8 @@ func TestCargoBuildStrategy_CanHandle(t *testing.T) {
 		want    bool
 	}{
 		{"cargo build bare", "cargo", []string{"build"}, true},
-		{"cargo check bare", "cargo", []string{"check"}, true},
-		{"cargo clippy bare", "cargo", []string{"clippy"}, true},
+		{"cargo check bare", "cargo", []string{"check"}, false},
+		{"cargo clippy bare", "cargo", []string{"clippy"}, false},
 		{"cargo test", "cargo", []string{"test"}, false},
 		{"cargo run", "cargo", []string{"run"}, false},
 		{"gcc build", "gcc", []string{"build"}, false},
@@ -329,3 +329,31 @@ func TestCargoBuildStrategy_Filter(t *testing.T) {
 		}
 	})
 }
+
+func TestCargoTestStrategy_ParseOutcome(t *testing.T) {
+	s := &CargoTestStrategy{}
+
+	input := "running 3 tests\n" +
+		"test tests::a ... ok\n" +
+		"test tests::b ... FAILED\n" +
+		"test tests::c ... ignored\n" +
+		"error[E0425]: cannot find value `x` in this scope\n" +
+		" --> src/lib.rs:3:5\n" +
+		"  |\n" +
+		"error: could not compile `demo` due to previous error\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "cargo", []string{"test"}, 101)
+	if !ok {
+		t.Fatal("expected outcome to be parsed")
+	}
+	if len(o.Passed) != 1 || o.Passed[0] != "tests::a" {
+		t.Errorf("Passed = %v, want [tests::a]", o.Passed)
+	}
+	if len(o.Failed) != 1 || o.Failed[0] != "tests::b" {
+		t.Errorf("Failed = %v, want [tests::b]", o.Failed)
+	}
+	want := "src/lib.rs:3:5: error[E0425]: cannot find value `x` in this scope"
+	if len(o.Errors) != 1 || o.Errors[0] != want {
+		t.Errorf("Errors = %v, want [%s]", o.Errors, want)
+	}
+}
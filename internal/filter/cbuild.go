@@ -0,0 +1,335 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// CBuildStrategy
+// ---------------------------------------------------------------------------
+
+// CBuildStrategy filters C and C++ builds run through make, ninja,
+// `cmake --build` or a compiler directly. Progress and echoed command lines are
+// dropped; GCC/Clang diagnostics are kept with their caret snippets, template
+// instantiation chains are shortened, diagnostics repeated across translation
+// units (typically warnings in a shared header) are shown once, and the
+// make/ninja lines naming the failed target are moved to the end. make and
+// ninja are only handled when they build (see isBuildInvocation).
+type CBuildStrategy struct{}
+
+func (s *CBuildStrategy) Name() string { return "c-build" }
+
+func (s *CBuildStrategy) CanHandle(command string, args []string) bool {
+	switch filepath.Base(command) {
+	case "make", "gmake", "ninja":
+		return isBuildInvocation(command, args)
+	case "cc", "c++", "gcc", "g++", "clang", "clang++":
+		return true
+	case "cmake":
+		for _, a := range args {
+			if a == "--build" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// makeValueFlags and ninjaValueFlags are the flags whose value is the next
+// argument. make's -j and -l take a value only when it is a number.
+var (
+	makeValueFlags  = map[string]bool{"-C": true, "-f": true, "-I": true, "-o": true, "-W": true}
+	ninjaValueFlags = map[string]bool{"-C": true, "-f": true, "-j": true, "-k": true, "-l": true, "-d": true, "-w": true}
+)
+
+// buildTargetRe matches targets that build code: "all", "build" and object,
+// library and executable files.
+var buildTargetRe = regexp.MustCompile(`^(all|build)$|\.(o|obj|a|so|dylib|lib|dll|exe)$`)
+
+// isBuildInvocation reports whether make or ninja args build code: the default
+// target, "all", "build" or object and library files. Other targets (test,
+// clean, install, docs) run arbitrary recipes whose output is left alone, and
+// ninja tools (-t) are not builds.
+func isBuildInvocation(command string, args []string) bool {
+	ninja := filepath.Base(command) == "ninja"
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case ninja && a == "-t":
+			return false
+		case ninja && ninjaValueFlags[a], !ninja && makeValueFlags[a]:
+			i++
+		case !ninja && (a == "-j" || a == "-l"):
+			if i+1 < len(args) && isDigits(args[i+1]) {
+				i++
+			}
+		case strings.HasPrefix(a, "-"), strings.Contains(a, "="):
+			// flag with an inline value, or a make variable assignment
+		case !buildTargetRe.MatchString(a):
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// Package-level compiled regexes for CBuildStrategy.
+var (
+	// cDiagRe matches a diagnostic headline: "src/a.cpp:12:5: error: msg".
+	cDiagRe = regexp.MustCompile(`^(\S.*?):(\d+):(?:(\d+):)? (fatal error|error|warning|note): (.*)$`)
+	// cIncludeRe matches gcc's "In file included from a.h:3," include chain.
+	cIncludeRe = regexp.MustCompile(`^(In file included from|\s+from) \S+:\d+(:\d+)?[,:]$`)
+	// cContextRe matches "a.cpp: In function 'int main()':" style context lines.
+	cContextRe = regexp.MustCompile(`^\S.*?: (In (member |static member )?function|In constructor|In destructor|In lambda function|At global scope|In instantiation of|In substitution of)\b.*:$`)
+	// cRequiredRe matches template instantiation backtrace lines.
+	cRequiredRe = regexp.MustCompile(`^\S.*?:\d+(:\d+)?:\s+(required from|required by substitution|recursively required|in (constexpr )?expansion of)`)
+	// cProgressRe matches cmake's "[ 42%] Building CXX object" and ninja's "[3/40] ..." lines.
+	cProgressRe = regexp.MustCompile(`^\[\s*\d+%\] |^\[\d+/\d+\] `)
+	// cCommandRe matches compile, link and archive commands echoed by make or ninja.
+	cCommandRe = regexp.MustCompile(`^\s*(cd \S+ && )?(\S*/)?(ccache |sccache )?(cc|c\+\+|gcc|g\+\+|clang|clang\+\+|\S+-(gcc|g\+\+|clang|clang\+\+)|(gcc|g\+\+|clang|clang\+\+)-\d+|ld|ld\.lld|ar|ranlib|nvcc|libtool:|cmake -E)(\s|$)`)
+	// cMakeNoiseRe matches make and CMake bookkeeping lines.
+	cMakeNoiseRe = regexp.MustCompile(`^(g?make(\[\d+\])?: (Entering|Leaving) directory|Scanning dependencies of target|Consolidate compiler generated dependencies|Built target |-- |ninja: (Entering directory|no work to do))`)
+	// cSnippetRe matches caret snippet lines: "   12 |   foo();", "      |   ^~~".
+	cSnippetRe = regexp.MustCompile(`^\s+(\d+\s+)?\|`)
+	// cLinkErrorRe matches linker errors.
+	cLinkErrorRe = regexp.MustCompile(`: undefined reference to |: multiple definition of |^(\S*/)?(ld|ld\.lld|lld): error: `)
+	// cFailedTargetRe matches the lines naming a failed target.
+	cFailedTargetRe = regexp.MustCompile(`^(g?make(\[\d+\])?: \*\*\* |FAILED: |ninja: build stopped)`)
+)
+
+const (
+	// cMaxNotes caps the notes kept per diagnostic (candidate lists can be long).
+	cMaxNotes = 3
+	// cMaxSnippet caps the snippet lines kept per headline.
+	cMaxSnippet = 6
+)
+
+// cDiag is one diagnostic with the context printed before it and the notes
+// printed after it.
+type cDiag struct {
+	includes    []string // "In file included from" chain
+	context     []string // "In function" lines
+	instantiate []string // "In instantiation of" and "required from" chain
+	headline    string
+	severity    string
+	snippet     []string
+	notes       [][]string // each note headline followed by its snippet
+	count       int        // times this diagnostic was reported
+}
+
+// cItem is either a diagnostic or a plain output line, in output order.
+type cItem struct {
+	diag *cDiag
+	line string
+}
+
+// parseCBuild splits compiler output into diagnostics and other kept lines,
+// dropping progress, echoed commands and build-system bookkeeping.
+// Identical diagnostics (same headline) are merged into the first one.
+func parseCBuild(lines []string) []cItem {
+	var items []cItem
+	seen := map[string]*cDiag{}
+	var includes, context, instantiate []string
+	var cur *cDiag
+	var curSnippet *[]string // snippet of the current headline or note
+	skipCommand := false     // ninja prints the failed command after FAILED:
+
+	resetContext := func() { includes, context, instantiate = nil, nil, nil }
+
+	for _, line := range lines {
+		if skipCommand {
+			skipCommand = false
+			if !cDiagRe.MatchString(line) {
+				continue
+			}
+		}
+		switch {
+		case strings.TrimSpace(line) == "", cProgressRe.MatchString(line),
+			cMakeNoiseRe.MatchString(line), cCommandRe.MatchString(line):
+			continue
+		case cIncludeRe.MatchString(line):
+			includes = append(includes, line)
+			cur = nil
+			continue
+		case cContextRe.MatchString(line):
+			if strings.Contains(line, ": In instantiation of") || strings.Contains(line, ": In substitution of") {
+				instantiate = append(instantiate, line)
+			} else {
+				context = append(context, line)
+			}
+			cur = nil
+			continue
+		case cRequiredRe.MatchString(line):
+			instantiate = append(instantiate, line)
+			cur = nil
+			continue
+		case cSnippetRe.MatchString(line) && curSnippet != nil:
+			*curSnippet = append(*curSnippet, line)
+			continue
+		}
+
+		if m := cDiagRe.FindStringSubmatch(line); m != nil {
+			if m[4] == "note" && cur != nil {
+				cur.notes = append(cur.notes, []string{line})
+				curSnippet = &cur.notes[len(cur.notes)-1]
+				continue
+			}
+			if prev, ok := seen[line]; ok {
+				prev.count++
+				// Keep collecting into a throwaway diagnostic so its notes
+				// and snippet are dropped with it
+				cur = &cDiag{}
+				curSnippet = &cur.snippet
+				resetContext()
+				continue
+			}
+			cur = &cDiag{includes: includes, context: context, instantiate: instantiate,
+				headline: line, severity: m[4], count: 1}
+			curSnippet = &cur.snippet
+			seen[line] = cur
+			items = append(items, cItem{diag: cur})
+			resetContext()
+			continue
+		}
+
+		// Anything else ends the current diagnostic
+		cur, curSnippet = nil, nil
+		if strings.HasPrefix(line, "FAILED: ") {
+			skipCommand = true
+		}
+		items = append(items, cItem{line: line})
+	}
+	return items
+}
+
+// ShortenPaths opts in to path shortening: CMake builds report absolute
+// source paths.
+func (s *CBuildStrategy) ShortenPaths() {}
+
+func (s *CBuildStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out, failed []string
+	errors, warnings, merged := 0, 0, 0
+	for _, it := range parseCBuild(lines) {
+		if it.diag == nil {
+			// Parallel builds interleave these with later diagnostics; they
+			// are collected and shown last
+			if cFailedTargetRe.MatchString(it.line) {
+				failed = append(failed, it.line)
+			} else {
+				out = append(out, it.line)
+			}
+			if cLinkErrorRe.MatchString(it.line) {
+				errors++
+			}
+			continue
+		}
+		d := it.diag
+		switch d.severity {
+		case "error", "fatal error":
+			errors++
+		case "warning":
+			warnings++
+		}
+		merged += d.count - 1
+		out = append(out, renderCDiag(d)...)
+	}
+	out = append(out, failed...)
+
+	if errors+warnings > 0 {
+		summary := fmt.Sprintf("%d errors, %d warnings", errors, warnings)
+		if merged > 0 {
+			summary += fmt.Sprintf(" (%d repeated diagnostics merged)", merged)
+		}
+		out = append(out, summary)
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// FilterStderr filters stderr like stdout: compilers write their diagnostics
+// there while make echoes commands on stdout.
+func (s *CBuildStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// renderCDiag renders a diagnostic with its include chain, a shortened
+// instantiation chain, its snippet and at most cMaxNotes notes.
+func renderCDiag(d *cDiag) []string {
+	var out []string
+	out = append(out, d.includes...)
+	out = append(out, d.context...)
+	if n := len(d.instantiate); n > 2 {
+		// The first line names the template, the last is where the user's
+		// code asked for it
+		out = append(out, d.instantiate[0])
+		out = append(out, fmt.Sprintf("  ... %d more instantiation frames", n-2))
+		out = append(out, d.instantiate[n-1])
+	} else {
+		out = append(out, d.instantiate...)
+	}
+	headline := d.headline
+	if d.count > 1 {
+		headline += fmt.Sprintf(" (reported %d times)", d.count)
+	}
+	out = append(out, headline)
+	out = append(out, capLines(d.snippet, cMaxSnippet)...)
+	for i, note := range d.notes {
+		if i == cMaxNotes {
+			out = append(out, fmt.Sprintf("  ... %d more notes", len(d.notes)-cMaxNotes))
+			break
+		}
+		out = append(out, note[0])
+		out = append(out, capLines(note[1:], cMaxSnippet)...)
+	}
+	return out
+}
+
+// capLines returns at most n lines.
+func capLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[:n]
+	}
+	return lines
+}
+
+// ParseOutcome reports error headlines and linker errors so `--since-last`
+// can show new and fixed build errors.
+func (s *CBuildStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	for _, it := range parseCBuild(strings.Split(StripANSIString(string(raw)), "\n")) {
+		switch {
+		case it.diag != nil && it.diag.severity != "warning" && it.diag.severity != "note":
+			o.Errors = append(o.Errors, it.diag.headline)
+		case it.diag == nil && cLinkErrorRe.MatchString(it.line):
+			o.Errors = append(o.Errors, it.line)
+		}
+	}
+	return o, true
+}
@@ -0,0 +1,192 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// CBuildStrategy
+// ---------------------------------------------------------------------------
+
+func TestCBuildStrategy_CanHandle(t *testing.T) {
+	s := &CBuildStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"make", "make", nil, true},
+		{"make -j", "make", []string{"-j8", "all"}, true},
+		{"gmake", "gmake", nil, true},
+		{"make vars", "make", []string{"-C", "src", "CC=clang", "app.o"}, true},
+		{"make test", "make", []string{"test"}, false},
+		{"make -j test", "make", []string{"-j", "test"}, false},
+		{"make -j 8", "make", []string{"-j", "8", "build"}, true},
+		{"make clean all", "make", []string{"clean", "all"}, false},
+		{"make -f test", "make", []string{"-f", "test.mk"}, true},
+		{"ninja", "ninja", []string{"-C", "build"}, true},
+		{"ninja tool", "ninja", []string{"-t", "clean"}, false},
+		{"ninja test", "ninja", []string{"-C", "build", "test"}, false},
+		{"cmake --build", "cmake", []string{"--build", "build", "-j"}, true},
+		{"cmake configure", "cmake", []string{"-S", ".", "-B", "build"}, false},
+		{"g++", "g++", []string{"-c", "main.cpp"}, true},
+		{"clang path", "/usr/bin/clang", []string{"main.c"}, true},
+		{"go", "go", []string{"build"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCBuildStrategy_Name(t *testing.T) {
+	if got := (&CBuildStrategy{}).Name(); got != "c-build" {
+		t.Errorf("Name() = %q, want %q", got, "c-build")
+	}
+}
+
+// cmakeMakeBuild is `cmake --build` over Makefiles: a header warning reported
+// from two translation units and an error in a template instantiation.
+const cmakeMakeBuild = `[  0%] Building CXX object CMakeFiles/app.dir/src/util.cpp.o
+/usr/bin/c++ -O2 -Wall -o CMakeFiles/app.dir/src/util.cpp.o -c /work/app/src/util.cpp
+In file included from /work/app/src/util.cpp:1:
+/work/app/src/util.h:7:9: warning: unused variable 'tmp' [-Wunused-variable]
+    7 |     int tmp = 0;
+      |         ^~~
+[ 33%] Building CXX object CMakeFiles/app.dir/src/main.cpp.o
+/usr/bin/c++ -O2 -Wall -o CMakeFiles/app.dir/src/main.cpp.o -c /work/app/src/main.cpp
+In file included from /work/app/src/main.cpp:2:
+/work/app/src/util.h:7:9: warning: unused variable 'tmp' [-Wunused-variable]
+    7 |     int tmp = 0;
+      |         ^~~
+/usr/include/c++/11/bits/stl_vector.h: In instantiation of 'void std::vector<_Tp, _Alloc>::push_back(const value_type&) [with _Tp = Widget]':
+/work/app/src/registry.h:14:20:   required from 'void Registry<T>::add(const T&) [with T = Widget]'
+/work/app/src/registry.h:22:9:   required from 'void Registry<T>::fill(int) [with T = Widget]'
+/work/app/src/main.cpp:10:15:   required from here
+/usr/include/c++/11/bits/stl_vector.h:1187:9: error: use of deleted function 'Widget::Widget(const Widget&)'
+ 1187 |         _Alloc_traits::construct(this->_M_impl, this->_M_impl._M_finish,
+      |         ^~~~~~~~~~~~~
+/work/app/src/widget.h:5:5: note: declared here
+    5 |     Widget(const Widget&) = delete;
+      |     ^~~~~~
+/work/app/src/widget.h:5:5: note: candidate 1
+/work/app/src/widget.h:6:5: note: candidate 2
+/work/app/src/widget.h:7:5: note: candidate 3
+make[2]: *** [CMakeFiles/app.dir/build.make:90: CMakeFiles/app.dir/src/main.cpp.o] Error 1
+make[1]: *** [CMakeFiles/Makefile2:83: CMakeFiles/app.dir/all] Error 2
+make: *** [Makefile:91: all] Error 2
+`
+
+func TestCBuildStrategy_Filter(t *testing.T) {
+	s := &CBuildStrategy{}
+	result := s.FilterStderr([]byte(cmakeMakeBuild), "cmake", []string{"--build", "build"}, 2)
+
+	want := `In file included from /work/app/src/util.cpp:1:
+/work/app/src/util.h:7:9: warning: unused variable 'tmp' [-Wunused-variable] (reported 2 times)
+    7 |     int tmp = 0;
+      |         ^~~
+/usr/include/c++/11/bits/stl_vector.h: In instantiation of 'void std::vector<_Tp, _Alloc>::push_back(const value_type&) [with _Tp = Widget]':
+  ... 2 more instantiation frames
+/work/app/src/main.cpp:10:15:   required from here
+/usr/include/c++/11/bits/stl_vector.h:1187:9: error: use of deleted function 'Widget::Widget(const Widget&)'
+ 1187 |         _Alloc_traits::construct(this->_M_impl, this->_M_impl._M_finish,
+      |         ^~~~~~~~~~~~~
+/work/app/src/widget.h:5:5: note: declared here
+    5 |     Widget(const Widget&) = delete;
+      |     ^~~~~~
+/work/app/src/widget.h:5:5: note: candidate 1
+/work/app/src/widget.h:6:5: note: candidate 2
+  ... 1 more notes
+make[2]: *** [CMakeFiles/app.dir/build.make:90: CMakeFiles/app.dir/src/main.cpp.o] Error 1
+make[1]: *** [CMakeFiles/Makefile2:83: CMakeFiles/app.dir/all] Error 2
+make: *** [Makefile:91: all] Error 2
+1 errors, 1 warnings (1 repeated diagnostics merged)
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestCBuildStrategy_Filter_Ninja(t *testing.T) {
+	s := &CBuildStrategy{}
+	input := `[1/6] Building C object CMakeFiles/app.dir/src/a.c.o
+[2/6] Building C object CMakeFiles/app.dir/src/b.c.o
+[3/6] Building C object CMakeFiles/app.dir/src/c.c.o
+[4/6] Building C object CMakeFiles/app.dir/src/main.c.o
+FAILED: CMakeFiles/app.dir/src/main.c.o
+/usr/bin/cc -O2 -MD -MT CMakeFiles/app.dir/src/main.c.o -o CMakeFiles/app.dir/src/main.c.o -c /work/app/src/main.c
+/work/app/src/main.c:12:5: error: implicit declaration of function 'frobnicate' [-Wimplicit-function-declaration]
+   12 |     frobnicate(x);
+      |     ^~~~~~~~~~
+/work/app/src/main.c: In function 'main':
+/work/app/src/main.c:20:1: warning: control reaches end of non-void function [-Wreturn-type]
+   20 | }
+      | ^
+ninja: build stopped: subcommand failed.
+`
+	result := s.Filter([]byte(input), "ninja", []string{"-C", "build"}, 1)
+
+	want := `/work/app/src/main.c:12:5: error: implicit declaration of function 'frobnicate' [-Wimplicit-function-declaration]
+   12 |     frobnicate(x);
+      |     ^~~~~~~~~~
+/work/app/src/main.c: In function 'main':
+/work/app/src/main.c:20:1: warning: control reaches end of non-void function [-Wreturn-type]
+   20 | }
+      | ^
+FAILED: CMakeFiles/app.dir/src/main.c.o
+ninja: build stopped: subcommand failed.
+1 errors, 1 warnings
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestCBuildStrategy_Filter_KeepsOtherOutput(t *testing.T) {
+	s := &CBuildStrategy{}
+	input := "make[1]: Entering directory '/work/app'\n" +
+		"gcc -O2 -c a.c -o a.o\n" +
+		"gcc -O2 -c b.c -o b.o\n" +
+		"gcc -O2 -c c.c -o c.o\n" +
+		"gcc -o app a.o b.o c.o\n" +
+		"/usr/bin/ld: b.o: in function `run':\n" +
+		"b.c:(.text+0x1f): undefined reference to `frobnicate'\n" +
+		"collect2: error: ld returned 1 exit status\n" +
+		"make[1]: *** [Makefile:8: app] Error 1\n" +
+		"make[1]: Leaving directory '/work/app'\n"
+
+	result := s.Filter([]byte(input), "make", nil, 2)
+
+	want := "/usr/bin/ld: b.o: in function `run':\n" +
+		"b.c:(.text+0x1f): undefined reference to `frobnicate'\n" +
+		"collect2: error: ld returned 1 exit status\n" +
+		"make[1]: *** [Makefile:8: app] Error 1\n" +
+		"1 errors, 0 warnings\n"
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestCBuildStrategy_ParseOutcome(t *testing.T) {
+	s := &CBuildStrategy{}
+	o, ok := s.ParseOutcome([]byte(cmakeMakeBuild+"b.c:(.text+0x1f): undefined reference to `frobnicate'\n"), "make", nil, 2)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := "/usr/include/c++/11/bits/stl_vector.h:1187:9: error: use of deleted function 'Widget::Widget(const Widget&)'\n" +
+		"b.c:(.text+0x1f): undefined reference to `frobnicate'"
+	if got := strings.Join(o.Errors, "\n"); got != want {
+		t.Errorf("Errors = %q, want %q", got, want)
+	}
+}
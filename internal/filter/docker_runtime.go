@@ -0,0 +1,507 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// dockerCustomFormat reports whether args ask for output other than the
+// default table: a --format template or IDs only (-q, also combined as -aq).
+func dockerCustomFormat(args []string) bool {
+	for _, a := range args {
+		switch {
+		case a == "--format" || strings.HasPrefix(a, "--format=") || a == "--quiet":
+			return true
+		case strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--") && strings.Contains(a, "q"):
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// DockerPsStrategy
+// ---------------------------------------------------------------------------
+
+// DockerPsStrategy groups `docker ps` (`docker container ls`) rows by status.
+// Rows keep the short container ID, name, image and status; the COMMAND,
+// CREATED, PORTS and SIZE columns are dropped unless --no-trunc or --size asks
+// for details (see dockerPsDetail). A custom --format or -q output is left to
+// the generic strategy.
+type DockerPsStrategy struct{}
+
+func (s *DockerPsStrategy) Name() string { return "docker-ps" }
+
+func (s *DockerPsStrategy) CanHandle(command string, args []string) bool {
+	if command != "docker" {
+		return false
+	}
+	first, second := dockerSubcommands(args, dockerValueFlags)
+	if first != "ps" && (first != "container" || (second != "ls" && second != "list" && second != "ps")) {
+		return false
+	}
+	return !dockerCustomFormat(args)
+}
+
+// Package-level compiled regexes for DockerPsStrategy.
+var (
+	// dockerExitStatusRe matches the stable part of an exited or restarting status: "Exited (137)".
+	dockerExitStatusRe = regexp.MustCompile(`^(?:Exited|Restarting) \(-?\d+\)`)
+	// dockerHealthRe matches the health or pause note of a running container: "(unhealthy)".
+	dockerHealthRe = regexp.MustCompile(`\((healthy|unhealthy|health: starting|Paused)\)$`)
+)
+
+// dockerPsDetailColumns are the columns kept after the status when
+// dockerPsDetail is set.
+var dockerPsDetailColumns = []string{"COMMAND", "PORTS", "SIZE"}
+
+// dockerPsDetail reports whether args ask `docker ps` for details: the full
+// IDs and commands (--no-trunc) or the sizes (-s, --size, also combined as
+// -as). The rows then keep the full ID and the COMMAND, PORTS and SIZE columns.
+func dockerPsDetail(args []string) bool {
+	for _, a := range args {
+		switch {
+		case a == "--no-trunc" || a == "--size" || strings.HasPrefix(a, "--size="):
+			return true
+		case strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--") && strings.Contains(a, "s"):
+			return true
+		}
+	}
+	return false
+}
+
+// dockerStatusGroup returns the status a container is grouped under, without
+// the time part: "Up 2 hours (healthy)" → "Up (healthy)", "Exited (1) 3 minutes ago" → "Exited (1)".
+func dockerStatusGroup(status string) string {
+	if strings.HasPrefix(status, "Up ") {
+		if m := dockerHealthRe.FindStringSubmatch(status); m != nil {
+			return "Up (" + m[1] + ")"
+		}
+		return "Up"
+	}
+	if m := dockerExitStatusRe.FindString(status); m != "" {
+		return m
+	}
+	return status
+}
+
+func (s *DockerPsStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 || !strings.HasPrefix(lines[0], "CONTAINER ID") {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	cols := newTableColumns(lines[0])
+	detail := dockerPsDetail(args)
+	var extraCols []string
+	if detail {
+		for _, c := range dockerPsDetailColumns {
+			if cols.has(c) {
+				extraCols = append(extraCols, c)
+			}
+		}
+	}
+	type psRow struct {
+		id, name, image, status string
+		extra                   []string
+	}
+	var groups []string
+	rows := map[string][]psRow{}
+	idWidth, nameWidth, imageWidth, statusWidth := 0, 0, 0, 0
+	extraWidths := make([]int, len(extraCols))
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		r := psRow{
+			id:     cols.field(line, "CONTAINER ID"),
+			name:   cols.field(line, "NAMES"),
+			image:  cols.field(line, "IMAGE"),
+			status: cols.field(line, "STATUS"),
+		}
+		// --no-trunc prints full 64-character IDs, shortened unless details
+		// were asked for
+		if len(r.id) > 12 && !detail {
+			r.id = r.id[:12]
+		}
+		for i, c := range extraCols {
+			r.extra = append(r.extra, cols.field(line, c))
+			extraWidths[i] = max(extraWidths[i], utf8.RuneCountInString(r.extra[i]))
+		}
+		idWidth = max(idWidth, len(r.id))
+		nameWidth = max(nameWidth, len(r.name))
+		imageWidth = max(imageWidth, len(r.image))
+		statusWidth = max(statusWidth, len(r.status))
+
+		g := dockerStatusGroup(r.status)
+		if _, ok := rows[g]; !ok {
+			groups = append(groups, g)
+		}
+		rows[g] = append(rows[g], r)
+	}
+
+	// Containers that need attention first, healthy running ones last
+	running := func(g string) int {
+		if g == "Up" || g == "Up (healthy)" {
+			return 1
+		}
+		return 0
+	}
+	slices.SortStableFunc(groups, func(a, b string) int { return running(a) - running(b) })
+
+	var out []string
+	for _, g := range groups {
+		out = append(out, fmt.Sprintf("%d %s:", len(rows[g]), g))
+		for _, r := range rows[g] {
+			row := fmt.Sprintf("  %-*s  %-*s  %-*s  %s", idWidth, r.id, nameWidth, r.name, imageWidth, r.image, r.status)
+			if len(extraCols) > 0 {
+				row = fmt.Sprintf("%-*s", 2+idWidth+2+nameWidth+2+imageWidth+2+statusWidth, row)
+				for i, v := range r.extra {
+					row += fmt.Sprintf("  %-*s", extraWidths[i], v)
+				}
+				row = strings.TrimRight(row, " ")
+			}
+			out = append(out, row)
+		}
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// ---------------------------------------------------------------------------
+// DockerImagesStrategy
+// ---------------------------------------------------------------------------
+
+// DockerImagesStrategy lists `docker images` (`docker image ls`) as
+// "repository:tag  size" lines, folds dangling images into a count and closes
+// with the number of images and their total size.
+type DockerImagesStrategy struct{}
+
+func (s *DockerImagesStrategy) Name() string { return "docker-images" }
+
+func (s *DockerImagesStrategy) CanHandle(command string, args []string) bool {
+	if command != "docker" {
+		return false
+	}
+	first, second := dockerSubcommands(args, dockerValueFlags)
+	if first != "images" && (first != "image" || (second != "ls" && second != "list")) {
+		return false
+	}
+	return !dockerCustomFormat(args)
+}
+
+// dockerSizeRe matches a size as docker prints it: "182MB", "5.58kB", "0B".
+var dockerSizeRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kMGT]?B)$`)
+
+// dockerSizeUnits are docker's decimal size units.
+var dockerSizeUnits = []string{"B", "kB", "MB", "GB", "TB"}
+
+// parseDockerSize returns the size in bytes, or 0 if s is not a size.
+func parseDockerSize(s string) float64 {
+	m := dockerSizeRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	for _, u := range dockerSizeUnits {
+		if u == m[2] {
+			break
+		}
+		v *= 1000
+	}
+	return v
+}
+
+// formatDockerSize formats bytes the way docker does: "1.21GB".
+func formatDockerSize(v float64) string {
+	i := 0
+	for v >= 1000 && i < len(dockerSizeUnits)-1 {
+		v /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.3g%s", v, dockerSizeUnits[i])
+}
+
+func (s *DockerImagesStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 || !strings.HasPrefix(lines[0], "REPOSITORY") {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	cols := newTableColumns(lines[0])
+	if !cols.has("TAG") || !cols.has("SIZE") {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var names, sizes []string
+	images, dangling := 0, 0
+	var total, danglingSize float64
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		size := cols.field(line, "SIZE")
+		images++
+		total += parseDockerSize(size)
+		if cols.field(line, "TAG") == "<none>" {
+			dangling++
+			danglingSize += parseDockerSize(size)
+			continue
+		}
+		names = append(names, cols.field(line, "REPOSITORY")+":"+cols.field(line, "TAG"))
+		sizes = append(sizes, size)
+	}
+
+	width := 0
+	for _, n := range names {
+		width = max(width, len(n))
+	}
+	var out []string
+	for i, n := range names {
+		out = append(out, fmt.Sprintf("%-*s  %s", width, n, sizes[i]))
+	}
+	summary := fmt.Sprintf("%d images, %s", images, formatDockerSize(total))
+	if dangling > 0 {
+		summary += fmt.Sprintf("; %d dangling (<none>), %s", dangling, formatDockerSize(danglingSize))
+	}
+	out = append(out, summary+" (shared layers are counted once per image)")
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// ---------------------------------------------------------------------------
+// DockerLogsStrategy
+// ---------------------------------------------------------------------------
+
+// dockerLogTail is the number of final log lines shown verbatim after the
+// clustered summary.
+const dockerLogTail = 10
+
+// DockerLogsStrategy clusters `docker logs` output like KubectlLogsStrategy
+// and then shows the last lines as printed, so the container's latest state
+// is visible. Containers log to both streams, so stderr is filtered the same way.
+type DockerLogsStrategy struct{}
+
+func (s *DockerLogsStrategy) Name() string { return "docker-logs" }
+
+func (s *DockerLogsStrategy) CanHandle(command string, args []string) bool {
+	if command != "docker" {
+		return false
+	}
+	first, second := dockerSubcommands(args, dockerValueFlags)
+	return first == "logs" || (first == "container" && second == "logs")
+}
+
+func (s *DockerLogsStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	clustered, templates := summarizeLog(lines)
+	out := append(clustered, "", fmt.Sprintf("%d log lines, %d distinct", nonBlankLines(lines), templates))
+
+	var tail []string
+	for i := len(lines) - 1; i >= 0 && len(tail) < dockerLogTail; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			tail = append(tail, lines[i])
+		}
+	}
+	slices.Reverse(tail)
+	out = append(out, "", fmt.Sprintf("Last %d lines:", len(tail)))
+	out = append(out, tail...)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// FilterStderr filters the container's stderr the same way as its stdout.
+func (s *DockerLogsStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ---------------------------------------------------------------------------
+// DockerComposeStrategy
+// ---------------------------------------------------------------------------
+
+// DockerComposeStrategy filters `docker compose up`, `build` and `logs` (and
+// the standalone `docker-compose`). Service-prefixed log lines are grouped
+// per service and clustered; container, network and pull progress is dropped.
+// `build` output is filtered like `docker build`.
+type DockerComposeStrategy struct{}
+
+func (s *DockerComposeStrategy) Name() string { return "docker-compose" }
+
+// composeSubcommand returns the compose subcommand of a `docker compose` or
+// `docker-compose` invocation, or "" if command is neither.
+func composeSubcommand(command string, args []string) string {
+	if command == "docker" {
+		first, second := dockerSubcommands(args, dockerValueFlags)
+		if first == "compose" {
+			return second
+		}
+		return ""
+	}
+	if filepath.Base(command) == "docker-compose" {
+		first, _ := dockerSubcommands(args, dockerSubcmdValueFlags["compose"])
+		return first
+	}
+	return ""
+}
+
+func (s *DockerComposeStrategy) CanHandle(command string, args []string) bool {
+	switch composeSubcommand(command, args) {
+	case "up", "build", "logs":
+		return true
+	}
+	return false
+}
+
+// Package-level compiled regexes for DockerComposeStrategy.
+var (
+	// composeLogRe matches a service-prefixed log line: "api-1  | listening on :8080".
+	composeLogRe = regexp.MustCompile(`^([\w.-]+)\s+\| ?(.*)$`)
+	// composeProgressRe matches container, network, volume and pull progress
+	// (compose v2, and v1's "Creating app_db_1 ... done").
+	composeProgressRe = regexp.MustCompile(`^\s*(?:[✔⠿⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏]\s*)?(?:(?:Container|Network|Volume|Image)\s+\S+|\S+)\s+(?:Creating|Created|Starting|Started|Stopping|Stopped|Removing|Removed|Recreate|Recreated|Running|Waiting|Healthy|Pulling|Pulled|Skipped|Built|Building|Exists)\b.*$` +
+		`|^\s*\[\+\] (?:Running|Building|Pulling|Stopping)` +
+		`|^\s*(?:[✔⠿⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏]\s*)?[0-9a-f]{12} (?:Waiting|Downloading|Extracting|Verifying Checksum|Download complete|Pull complete|Already exists|Pulling fs layer)` +
+		`|^(?:Creating|Starting|Recreating|Stopping|Removing|Pulling) \S+ \.\.\.` +
+		`|^Attaching to `)
+)
+
+// FilterStderr filters stderr like stdout: compose v2 writes its container,
+// network and pull progress there.
+func (s *DockerComposeStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+func (s *DockerComposeStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	if composeSubcommand(command, args) == "build" {
+		return (&DockerBuildStrategy{}).Filter(raw, command, args, exitCode)
+	}
+
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	// Lines before the first service line, the service logs, and the other
+	// lines after it ("api-1 exited with code 1", errors)
+	var pre, post, services []string
+	logs := map[string][]string{}
+	progress := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if composeProgressRe.MatchString(line) {
+			progress++
+			continue
+		}
+		if m := composeLogRe.FindStringSubmatch(line); m != nil {
+			if _, ok := logs[m[1]]; !ok {
+				services = append(services, m[1])
+			}
+			logs[m[1]] = append(logs[m[1]], m[2])
+			continue
+		}
+		if len(services) == 0 {
+			pre = append(pre, line)
+		} else {
+			post = append(post, line)
+		}
+	}
+
+	var out []string
+	if progress > 0 {
+		out = append(out, fmt.Sprintf("Progress output stripped (%d lines removed):", progress))
+	}
+	out = append(out, pre...)
+	for _, svc := range services {
+		out = append(out, fmt.Sprintf("%s (%d lines):", svc, nonBlankLines(logs[svc])))
+		clustered, _ := summarizeLog(logs[svc])
+		for _, line := range clustered {
+			out = append(out, "  "+line)
+		}
+	}
+	out = append(out, post...)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
@@ -0,0 +1,275 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDockerRuntimeStrategies_CanHandle(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    string // strategy name, or "" for none
+	}{
+		{"ps", "docker", []string{"ps", "-a"}, "docker-ps"},
+		{"container ls", "docker", []string{"container", "ls", "--all"}, "docker-ps"},
+		{"ps with context", "docker", []string{"--context", "prod", "ps"}, "docker-ps"},
+		{"ps quiet", "docker", []string{"ps", "-aq"}, ""},
+		{"ps format", "docker", []string{"ps", "--format", "{{.Names}}"}, ""},
+		{"ps format table", "docker", []string{"ps", "--format", "table {{.Names}}\t{{.Ports}}"}, ""},
+		{"ps no-trunc", "docker", []string{"ps", "--no-trunc"}, "docker-ps"},
+		{"images", "docker", []string{"images"}, "docker-images"},
+		{"image ls", "docker", []string{"image", "ls", "-a"}, "docker-images"},
+		{"image prune", "docker", []string{"image", "prune"}, ""},
+		{"logs", "docker", []string{"logs", "--tail", "500", "api"}, "docker-logs"},
+		{"container logs", "docker", []string{"container", "logs", "api"}, "docker-logs"},
+		{"compose up", "docker", []string{"compose", "up"}, "docker-compose"},
+		{"compose -f logs", "docker", []string{"compose", "-f", "dev.yml", "logs", "api"}, "docker-compose"},
+		{"compose build", "docker", []string{"compose", "build"}, "docker-compose"},
+		{"docker-compose up", "docker-compose", []string{"-p", "shop", "up"}, "docker-compose"},
+		{"compose ps", "docker", []string{"compose", "ps"}, ""},
+		{"not docker", "podman", []string{"ps"}, ""},
+	}
+	strategies := []Strategy{&DockerPsStrategy{}, &DockerImagesStrategy{}, &DockerLogsStrategy{}, &DockerComposeStrategy{}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, s := range strategies {
+				if got := s.CanHandle(tc.command, tc.args); got != (s.Name() == tc.want) {
+					t.Errorf("%s CanHandle(%q, %v) = %v", s.Name(), tc.command, tc.args, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDockerPsStrategy_Filter(t *testing.T) {
+	s := &DockerPsStrategy{}
+	input := `CONTAINER ID   IMAGE                 COMMAND                  CREATED        STATUS                       PORTS                    NAMES
+4f1c2a9b8e7d   nginx:1.25            "/docker-entrypoint.…"   2 hours ago    Up 2 hours                   0.0.0.0:8080->80/tcp     web
+9b2e4f1c2a9b   ghcr.io/acme/api:1.4  "/app/api serve"         2 hours ago    Up 2 hours (healthy)         8080/tcp                 api
+1a2b3c4d5e6f   postgres:16           "docker-entrypoint.s…"   2 hours ago    Up 2 hours (unhealthy)       5432/tcp                 db
+7c8d9e0f1a2b   ghcr.io/acme/worker   "/app/worker"            3 hours ago    Exited (1) 3 minutes ago                              worker
+3d4e5f6a7b8c   busybox               "sh"                     5 days ago     Exited (0) 5 days ago                                 scratch
+5e6f7a8b9c0d   redis:7               "docker-entrypoint.s…"   2 hours ago    Up 2 hours                   6379/tcp                 cache
+6f7a8b9c0d1e   ghcr.io/acme/worker   "/app/worker"            3 hours ago    Exited (1) 10 minutes ago                             worker-2
+8a9b0c1d2e3f   alpine                "sleep 1000"             1 minute ago   Created                                               tmp
+`
+	result := s.Filter([]byte(input), "docker", []string{"ps", "-a"}, 0)
+
+	want := `1 Up (unhealthy):
+  1a2b3c4d5e6f  db        postgres:16           Up 2 hours (unhealthy)
+2 Exited (1):
+  7c8d9e0f1a2b  worker    ghcr.io/acme/worker   Exited (1) 3 minutes ago
+  6f7a8b9c0d1e  worker-2  ghcr.io/acme/worker   Exited (1) 10 minutes ago
+1 Exited (0):
+  3d4e5f6a7b8c  scratch   busybox               Exited (0) 5 days ago
+1 Created:
+  8a9b0c1d2e3f  tmp       alpine                Created
+2 Up:
+  4f1c2a9b8e7d  web       nginx:1.25            Up 2 hours
+  5e6f7a8b9c0d  cache     redis:7               Up 2 hours
+1 Up (healthy):
+  9b2e4f1c2a9b  api       ghcr.io/acme/api:1.4  Up 2 hours (healthy)
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestDockerPsStrategy_Filter_Sizes(t *testing.T) {
+	s := &DockerPsStrategy{}
+	input := `CONTAINER ID   IMAGE                 COMMAND                  CREATED        STATUS                       PORTS                    NAMES      SIZE
+4f1c2a9b8e7d   nginx:1.25            "/docker-entrypoint.…"   2 hours ago    Up 2 hours                   0.0.0.0:8080->80/tcp     web        2B (virtual 187MB)
+9b2e4f1c2a9b   ghcr.io/acme/api:1.4  "/app/api serve"         2 hours ago    Up 2 hours (healthy)         8080/tcp                 api        0B (virtual 41.2MB)
+1a2b3c4d5e6f   postgres:16           "docker-entrypoint.s…"   2 hours ago    Up 2 hours (unhealthy)       5432/tcp                 db         63B (virtual 432MB)
+7c8d9e0f1a2b   ghcr.io/acme/worker   "/app/worker"            3 hours ago    Exited (1) 3 minutes ago                              worker     0B (virtual 38MB)
+3d4e5f6a7b8c   busybox               "sh"                     5 days ago     Exited (0) 5 days ago                                 scratch    5B (virtual 4.26MB)
+5e6f7a8b9c0d   redis:7               "docker-entrypoint.s…"   2 hours ago    Up 2 hours                   6379/tcp                 cache      0B (virtual 117MB)
+6f7a8b9c0d1e   ghcr.io/acme/worker   "/app/worker"            3 hours ago    Exited (1) 10 minutes ago                             worker-2   0B (virtual 38MB)
+8a9b0c1d2e3f   alpine                "sleep 1000"             1 minute ago   Created                                               tmp        0B (virtual 7.8MB)
+`
+	result := s.Filter([]byte(input), "docker", []string{"ps", "-as"}, 0)
+
+	want := `1 Up (unhealthy):
+  1a2b3c4d5e6f  db        postgres:16           Up 2 hours (unhealthy)     "docker-entrypoint.s…"  5432/tcp              63B (virtual 432MB)
+2 Exited (1):
+  7c8d9e0f1a2b  worker    ghcr.io/acme/worker   Exited (1) 3 minutes ago   "/app/worker"                                 0B (virtual 38MB)
+  6f7a8b9c0d1e  worker-2  ghcr.io/acme/worker   Exited (1) 10 minutes ago  "/app/worker"                                 0B (virtual 38MB)
+1 Exited (0):
+  3d4e5f6a7b8c  scratch   busybox               Exited (0) 5 days ago      "sh"                                          5B (virtual 4.26MB)
+1 Created:
+  8a9b0c1d2e3f  tmp       alpine                Created                    "sleep 1000"                                  0B (virtual 7.8MB)
+2 Up:
+  4f1c2a9b8e7d  web       nginx:1.25            Up 2 hours                 "/docker-entrypoint.…"  0.0.0.0:8080->80/tcp  2B (virtual 187MB)
+  5e6f7a8b9c0d  cache     redis:7               Up 2 hours                 "docker-entrypoint.s…"  6379/tcp              0B (virtual 117MB)
+1 Up (healthy):
+  9b2e4f1c2a9b  api       ghcr.io/acme/api:1.4  Up 2 hours (healthy)       "/app/api serve"        8080/tcp              0B (virtual 41.2MB)
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestDockerStatusGroup(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Up 2 hours", "Up"},
+		{"Up About a minute (health: starting)", "Up (health: starting)"},
+		{"Up 3 days (Paused)", "Up (Paused)"},
+		{"Exited (137) 2 seconds ago", "Exited (137)"},
+		{"Restarting (1) 5 seconds ago", "Restarting (1)"},
+		{"Created", "Created"},
+		{"Dead", "Dead"},
+	}
+	for _, tc := range tests {
+		if got := dockerStatusGroup(tc.status); got != tc.want {
+			t.Errorf("dockerStatusGroup(%q) = %q, want %q", tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestDockerImagesStrategy_Filter(t *testing.T) {
+	s := &DockerImagesStrategy{}
+	input := `REPOSITORY              TAG       IMAGE ID       CREATED        SIZE
+ghcr.io/acme/api        1.4.2     9b2e4f1c2a9b   2 days ago     182MB
+ghcr.io/acme/api        1.4.1     1f2e3d4c5b6a   9 days ago     181MB
+<none>                  <none>    1a2b3c4d5e6f   3 days ago     180MB
+<none>                  <none>    2b3c4d5e6f7a   4 days ago     179MB
+postgres                16        3c4d5e6f7a8b   3 weeks ago    432MB
+<none>                  <none>    4d5e6f7a8b9c   5 days ago     1.2GB
+redis                   7         5e6f7a8b9c0d   4 weeks ago    117MB
+busybox                 latest    6f7a8b9c0d1e   2 months ago   4.26MB
+`
+	result := s.Filter([]byte(input), "docker", []string{"images"}, 0)
+
+	want := `ghcr.io/acme/api:1.4.2  182MB
+ghcr.io/acme/api:1.4.1  181MB
+postgres:16             432MB
+redis:7                 117MB
+busybox:latest          4.26MB
+8 images, 2.48GB; 3 dangling (<none>), 1.56GB (shared layers are counted once per image)
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestParseDockerSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"0B", 0},
+		{"5.58kB", 5580},
+		{"182MB", 182e6},
+		{"1.2GB", 1.2e9},
+		{"n/a", 0},
+	}
+	for _, tc := range tests {
+		if got := parseDockerSize(tc.in); got != tc.want {
+			t.Errorf("parseDockerSize(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestDockerLogsStrategy_Filter(t *testing.T) {
+	s := &DockerLogsStrategy{}
+	var b strings.Builder
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "172.17.0.1 - - [12/Feb/2026:14:30:%02d +0000] \"GET /healthz HTTP/1.1\" 200 2 \"-\" \"kube-probe/1.30\"\n", i)
+	}
+	b.WriteString("2026/02/12 14:31:02 [error] 29#29: *41 connect() failed (111: Connection refused) while connecting to upstream\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "2026/02/12 14:31:%02d [warn] 29#29: *%d upstream server temporarily disabled while reading response header from upstream\n", i+10, 42+i)
+	}
+
+	result := s.Filter([]byte(b.String()), "docker", []string{"logs", "web"}, 0)
+
+	want := `<IP> - - [<TS>] "GET /healthz HTTP/<N>" <N> <N> "-" "kube-probe/<N>"  (30 lines)
+  e.g. 172.17.0.1 - - [12/Feb/2026:14:30:00 +0000] "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.30"
+2026/02/12 14:31:02 [error] 29#29: *41 connect() failed (111: Connection refused) while connecting to upstream
+<TS> [warn] <N>#<N>: *<N> upstream server temporarily disabled while reading response header from upstream  (12 lines)
+  e.g. 2026/02/12 14:31:10 [warn] 29#29: *42 upstream server temporarily disabled while reading response header from upstream
+
+43 log lines, 3 distinct
+
+Last 10 lines:
+`
+	if !strings.HasPrefix(result.Filtered, want) {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant prefix:\n%s", result.Filtered, want)
+	}
+	if !strings.HasSuffix(result.Filtered, "14:31:21 [warn] 29#29: *53 upstream server temporarily disabled while reading response header from upstream\n") {
+		t.Errorf("expected the last log line at the end, got:\n%s", result.Filtered)
+	}
+	if n := strings.Count(result.Filtered, "\n"); n != 19 {
+		t.Errorf("expected 19 lines, got %d:\n%s", n, result.Filtered)
+	}
+}
+
+func TestDockerComposeStrategy_FilterStderr_Progress(t *testing.T) {
+	// compose v2 writes progress to stderr and the service logs to stdout.
+	s := &DockerComposeStrategy{}
+	input := `[+] Running 3/3
+ ✔ db Pulled                                                          4.1s
+   ✔ 6e771e15690e Pull complete                                       1.2s
+   ✔ 2c8d2f1a9b0e Pull complete                                       1.8s
+ ✔ Network shop_default  Created                                      0.1s
+ ✔ Container shop-db-1   Created                                      0.2s
+ ✔ Container shop-api-1  Created                                      0.2s
+ ✔ Container shop-db-1   Started                                      0.5s
+ ✔ Container shop-api-1  Started                                      0.6s
+Error response from daemon: driver failed programming external connectivity on endpoint shop-api-1: Bind for 0.0.0.0:8080 failed: port is already allocated
+`
+	result := s.FilterStderr([]byte(input), "docker", []string{"compose", "up", "-d"}, 1)
+
+	want := `Progress output stripped (9 lines removed):
+Error response from daemon: driver failed programming external connectivity on endpoint shop-api-1: Bind for 0.0.0.0:8080 failed: port is already allocated
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestDockerComposeStrategy_Filter_Up(t *testing.T) {
+	s := &DockerComposeStrategy{}
+	input := `[+] Running 4/4
+ ✔ Network shop_default  Created
+ ✔ Container shop-db-1   Created
+ ✔ Container shop-api-1  Created
+Attaching to api-1, db-1
+db-1   | 2026-02-12 14:30:22.101 UTC [1] LOG:  starting PostgreSQL 16.2
+api-1  | 2026-02-12T14:30:23Z INFO waiting for database attempt=1
+db-1   | 2026-02-12 14:30:22.310 UTC [1] LOG:  listening on IPv4 address "0.0.0.0", port 5432
+api-1  | 2026-02-12T14:30:24Z INFO waiting for database attempt=2
+api-1  | 2026-02-12T14:30:25Z INFO waiting for database attempt=3
+db-1   | 2026-02-12 14:30:22.402 UTC [1] LOG:  database system is ready to accept connections
+api-1  | 2026-02-12T14:30:26Z ERROR migration 0042 failed: column "sku" does not exist
+api-1 exited with code 1
+`
+	result := s.Filter([]byte(input), "docker", []string{"compose", "up"}, 1)
+
+	want := `Progress output stripped (5 lines removed):
+db-1 (3 lines):
+  2026-02-12 14:30:22.101 UTC [1] LOG:  starting PostgreSQL 16.2
+  2026-02-12 14:30:22.310 UTC [1] LOG:  listening on IPv4 address "0.0.0.0", port 5432
+  2026-02-12 14:30:22.402 UTC [1] LOG:  database system is ready to accept connections
+api-1 (4 lines):
+  <TS> INFO waiting for database attempt=<N>  (3 lines)
+    e.g. 2026-02-12T14:30:23Z INFO waiting for database attempt=1
+  2026-02-12T14:30:26Z ERROR migration 0042 failed: column "sku" does not exist
+api-1 exited with code 1
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
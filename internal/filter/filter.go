@@ -12,3 +12,10 @@ type Strategy interface {
 	CanHandle(command string, args []string) bool
 	Filter(raw []byte, command string, args []string, exitCode int) Result
 }
+
+// StderrFilter is implemented by strategies for tools that write their
+// diagnostics to stderr. For these, the executor buffers stderr instead of
+// streaming it and writes the filtered result once the command exits.
+type StderrFilter interface {
+	FilterStderr(raw []byte, command string, args []string, exitCode int) Result
+}
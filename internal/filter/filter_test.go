@@ -135,6 +135,25 @@ func TestRegistry(t *testing.T) {
 	})
 }
 
+func TestRegistryLookup(t *testing.T) {
+	r := DefaultRegistry()
+
+	for _, name := range []string{"log-cluster", "git-status", "generic-error", "passthrough"} {
+		s, ok := r.Lookup(name)
+		if !ok || s.Name() != name {
+			t.Errorf("Lookup(%q) = %v, %v", name, s, ok)
+		}
+	}
+	if _, ok := r.Lookup("no-such-filter"); ok {
+		t.Error("Lookup of an unknown name should fail")
+	}
+
+	names := r.Names()
+	if len(names) == 0 || names[len(names)-1] != "passthrough" {
+		t.Errorf("Names should end with the fallback, got %v", names)
+	}
+}
+
 func TestRegistryPriority(t *testing.T) {
 	r := DefaultRegistry()
 
@@ -154,20 +173,77 @@ func TestRegistryPriority(t *testing.T) {
 		// Go strategies
 		{"go test", "go", []string{"test"}, "go-test"},
 		{"go test all", "go", []string{"test", "./..."}, "go-test"},
+		{"go test json", "go", []string{"test", "-json", "./..."}, "go-test-json"},
 		{"go build", "go", []string{"build"}, "go-build"},
 		{"go build all", "go", []string{"build", "./..."}, "go-build"},
-		{"go vet", "go", []string{"vet"}, "go-build"},
-		{"go vet all", "go", []string{"vet", "./..."}, "go-build"},
+		{"go vet", "go", []string{"vet"}, "go-lint"},
+		{"go vet all", "go", []string{"vet", "./..."}, "go-lint"},
+		{"staticcheck", "staticcheck", []string{"./..."}, "go-lint"},
+		{"golangci-lint run", "golangci-lint", []string{"run", "./..."}, "go-lint"},
+		{"golangci-lint linters", "golangci-lint", []string{"linters"}, "generic-error"},
 		{"go install", "go", []string{"install"}, "go-build"},
+		{"go mod tidy", "go", []string{"mod", "tidy"}, "go-mod"},
+		{"go get", "go", []string{"get", "-u", "./..."}, "go-mod"},
 		// Cargo strategies
 		{"cargo test", "cargo", []string{"test"}, "cargo-test"},
 		{"cargo test all", "cargo", []string{"test", "--all"}, "cargo-test"},
 		{"cargo build", "cargo", []string{"build"}, "cargo-build"},
-		{"cargo check", "cargo", []string{"check"}, "cargo-build"},
-		{"cargo clippy", "cargo", []string{"clippy"}, "cargo-build"},
+		{"cargo build json", "cargo", []string{"build", "--message-format=json"}, "cargo-json"},
+		{"cargo check", "cargo", []string{"check"}, "cargo-check"},
+		{"cargo clippy", "cargo", []string{"clippy"}, "cargo-clippy"},
+		// Python strategies
+		{"pytest", "pytest", []string{"-x"}, "pytest"},
+		{"python -m pytest", "python", []string{"-m", "pytest"}, "pytest"},
+		{"python3 -m pytest", "python3", []string{"-m", "pytest", "tests/"}, "pytest"},
+		// JavaScript strategies
+		{"npm test", "npm", []string{"test"}, "js-test"},
+		{"yarn test", "yarn", []string{"test"}, "js-test"},
+		{"npx jest", "npx", []string{"jest"}, "js-test"},
+		{"npx vitest run", "npx", []string{"vitest", "run"}, "js-test"},
+		{"bun test", "bun", []string{"test"}, "js-test"},
+		{"tsc", "tsc", []string{"--noEmit"}, "tsc"},
+		{"npx tsc", "npx", []string{"tsc", "--noEmit"}, "tsc"},
+		{"pnpm tsc", "pnpm", []string{"tsc"}, "tsc"},
+		{"npx eslint", "npx", []string{"eslint", "."}, "js-lint"},
+		{"biome check", "biome", []string{"check", "."}, "js-lint"},
+		{"prettier --check", "prettier", []string{"--check", "."}, "js-lint"},
+		{"prettier --write", "prettier", []string{"--write", "."}, "generic-error"},
+		// C/C++ strategies
+		{"make", "make", []string{"-j8"}, "c-build"},
+		{"ninja", "ninja", []string{"-C", "build"}, "c-build"},
+		{"cmake --build", "cmake", []string{"--build", "build"}, "c-build"},
+		{"g++", "g++", []string{"-c", "main.cpp"}, "c-build"},
+		// JVM strategies
+		{"mvn test", "mvn", []string{"test"}, "maven"},
+		{"mvnw verify", "./mvnw", []string{"-B", "verify"}, "maven"},
+		{"mvn dependency:tree", "mvn", []string{"dependency:tree"}, "generic-error"},
+		{"gradlew build", "./gradlew", []string{"build"}, "gradle"},
+		{"gradle :app:test", "gradle", []string{":app:test"}, "gradle"},
+		// Kubernetes strategies
+		{"kubectl get pods", "kubectl", []string{"get", "pods", "-A"}, "kubectl-get"},
+		{"kubectl -n get", "kubectl", []string{"-n", "prod", "get", "deploy"}, "kubectl-get"},
+		{"kubectl get -o yaml", "kubectl", []string{"get", "pod", "api", "-o", "yaml"}, "generic-error"},
+		{"kubectl describe", "kubectl", []string{"--context", "staging", "describe", "pod", "api"}, "kubectl-describe"},
+		{"kubectl logs", "kubectl", []string{"logs", "-n", "prod", "deploy/api"}, "kubectl-logs"},
+		{"kubectl apply", "kubectl", []string{"apply", "-f", "app.yaml"}, "generic-error"},
+		// Terraform strategies
+		{"terraform plan", "terraform", []string{"plan", "-var", "env=prod"}, "terraform"},
+		{"tofu apply", "tofu", []string{"apply", "-auto-approve"}, "terraform"},
+		{"terraform init", "terraform", []string{"init"}, "generic-error"},
+		// Rendered manifest strategies
+		{"helm template", "helm", []string{"template", "web", "./chart"}, "helm"},
+		{"helm upgrade", "helm", []string{"--kube-context", "prod", "upgrade", "--install", "web", "./chart"}, "helm"},
+		{"helm repo add", "helm", []string{"repo", "add", "bitnami", "https://charts.bitnami.com/bitnami"}, "generic-error"},
+		{"kustomize build", "kustomize", []string{"build", "overlays/prod"}, "kustomize"},
 		// Docker strategies
 		{"docker build", "docker", []string{"build", "."}, "docker-build"},
-		{"docker compose build", "docker", []string{"compose", "build"}, "docker-build"},
+		{"docker compose build", "docker", []string{"compose", "build"}, "docker-compose"},
+		{"docker compose up", "docker", []string{"compose", "up"}, "docker-compose"},
+		{"docker ps", "docker", []string{"ps", "-a"}, "docker-ps"},
+		{"docker ps -q", "docker", []string{"ps", "-q"}, "generic-error"},
+		{"docker images", "docker", []string{"images"}, "docker-images"},
+		{"docker logs", "docker", []string{"logs", "-n", "200", "api"}, "docker-logs"},
+		{"journalctl", "journalctl", []string{"-u", "api", "--since", "today"}, "log-cluster"},
 		// Grep/rg strategies
 		{"grep pattern", "grep", []string{"-rn", "pattern", "."}, "grep-group"},
 		{"rg pattern", "rg", []string{"pattern"}, "grep-group"},
@@ -177,7 +253,6 @@ func TestRegistryPriority(t *testing.T) {
 		{"pip install", "pip", []string{"install", "requests"}, "progress-strip"},
 		// Unknown commands should get generic-error (last registered strategy that matches anything)
 		{"unknown command", "unknown", nil, "generic-error"},
-		{"npm test", "npm", []string{"test"}, "generic-error"},
 		// Git subcommands without specific strategies should get generic-error
 		{"git commit", "git", []string{"commit"}, "generic-error"},
 		{"git push", "git", []string{"push"}, "generic-error"},
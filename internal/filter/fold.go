@@ -0,0 +1,276 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RepeatFolder is implemented by strategies whose output benefits from
+// folding repeated blocks (see the FoldRepeats function). The executor applies
+// the folding pass to the filtered stdout and stderr of strategies that opt in.
+type RepeatFolder interface {
+	FoldRepeats()
+}
+
+// ---------------------------------------------------------------------------
+// Repeated-block folding
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for repeated-block folding.
+var (
+	// foldPathRe matches paths ("src/a.c", "/usr/include/x.h") and bare file
+	// names with an extension ("main.go").
+	foldPathRe = regexp.MustCompile(`(?:[\w.@%+-]*/)+[\w.@%+-]+|\b[\w-]+\.[A-Za-z]\w{0,4}\b`)
+	// foldNumberRe matches numbers, including versions and durations.
+	foldNumberRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+	// foldWarningRe matches warning and deprecation lines, which are folded
+	// even when their repeats are not adjacent.
+	foldWarningRe = regexp.MustCompile(`(?i)\b(?:warn(?:ing)?|deprecat(?:ed|ion))\b`)
+)
+
+const (
+	// foldMaxBlock is the longest run of lines considered as a repeated block.
+	foldMaxBlock = 10
+	// foldMaxVariants caps the variants listed for a folded block.
+	foldMaxVariants = 5
+)
+
+// foldKey returns the form of a line used to find repeats: paths and numbers
+// are masked, indentation is kept.
+func foldKey(line string) string {
+	k := foldPathRe.ReplaceAllString(line, "<PATH>")
+	return foldNumberRe.ReplaceAllString(k, "<N>")
+}
+
+// FoldRepeats folds the repeated blocks of a filtered result and reports the
+// result as reduced if anything was folded.
+func FoldRepeats(r Result) Result {
+	hadTrailing := endsWithNewline(r.Filtered)
+	lines := strings.Split(strings.TrimSuffix(r.Filtered, "\n"), "\n")
+
+	folded, inFold, okRuns := foldRepeatedLines(lines)
+	folded, okScattered := foldScatteredWarnings(folded, inFold)
+	if !okRuns && !okScattered {
+		return r
+	}
+	filtered := ensureTrailingNewline(strings.Join(folded, "\n"), hadTrailing)
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// foldRepeatedLines replaces consecutive repeats of a block of up to
+// foldMaxBlock lines that differ only in paths and numbers with the first
+// copy followed by "(repeated N times; variants: …)". A single line is folded
+// when it repeats at least three times, a longer block when it repeats twice.
+// It also returns which output lines belong to a fold, and whether anything
+// was folded.
+func foldRepeatedLines(lines []string) ([]string, []bool, bool) {
+	keys := make([]string, len(lines))
+	for i, line := range lines {
+		keys[i] = foldKey(line)
+	}
+
+	var out []string
+	var inFold []bool
+	folded := false
+	for i := 0; i < len(lines); {
+		size, count := longestRepeat(keys, i)
+		if size == 0 {
+			out = append(out, lines[i])
+			inFold = append(inFold, false)
+			i++
+			continue
+		}
+
+		block := lines[i : i+size*count]
+		note := repeatNote(block, size, count)
+		if size == 1 {
+			out = append(out, lines[i]+"  "+note)
+		} else {
+			out = append(out, block[:size]...)
+			indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+			out = append(out, indent+note)
+		}
+		for len(inFold) < len(out) {
+			inFold = append(inFold, true)
+		}
+		folded = true
+		i += size * count
+	}
+	return out, inFold, folded
+}
+
+// foldScatteredWarnings folds warning lines that repeat apart from each other,
+// such as the same warning printed for every module with other output in
+// between. Later copies are dropped and the first gets the repeat note. Only
+// standalone warnings are folded: unindented lines, not already part of a
+// fold, not followed by indented detail (a location or snippet) that would
+// lose its headline. It reports whether anything was folded.
+func foldScatteredWarnings(lines []string, inFold []bool) ([]string, bool) {
+	var order []string
+	groups := map[string][]int{}
+	for i, line := range lines {
+		if inFold[i] || !foldWarningRe.MatchString(line) || !standaloneLine(lines, i) {
+			continue
+		}
+		k := foldKey(line)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	notes := map[int]string{}
+	drop := map[int]bool{}
+	for _, k := range order {
+		idx := groups[k]
+		if len(idx) < 2 {
+			continue
+		}
+		copies := make([]string, len(idx))
+		for j, i := range idx {
+			copies[j] = lines[i]
+			if j > 0 {
+				drop[i] = true
+			}
+		}
+		notes[idx[0]] = repeatNote(copies, 1, len(idx))
+	}
+	if len(notes) == 0 {
+		return lines, false
+	}
+
+	out := make([]string, 0, len(lines)-len(drop))
+	for i, line := range lines {
+		switch {
+		case drop[i]:
+			continue
+		case notes[i] != "":
+			out = append(out, line+"  "+notes[i])
+		default:
+			out = append(out, line)
+		}
+	}
+	return out, true
+}
+
+// standaloneLine reports whether line i starts at column 0 and is followed by
+// the end of the output, a blank line or another unindented line.
+func standaloneLine(lines []string, i int) bool {
+	if lines[i] == "" || lines[i][0] == ' ' || lines[i][0] == '\t' {
+		return false
+	}
+	if i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == "" {
+		return true
+	}
+	next := lines[i+1][0]
+	return next != ' ' && next != '\t'
+}
+
+// repeatNote returns the "(repeated N times; variants: …)" note for count
+// copies of a block of size lines.
+func repeatNote(block []string, size, count int) string {
+	note := fmt.Sprintf("(repeated %d times", count)
+	if variants := blockVariants(block, size); len(variants) > 0 {
+		note += "; variants: " + strings.Join(variants, ", ")
+	}
+	return note + ")"
+}
+
+// longestRepeat finds the block starting at i whose consecutive repeats cover
+// the most lines, preferring the shortest block. It returns the block size and
+// the number of copies, or 0, 0 if no block repeats often enough.
+func longestRepeat(keys []string, i int) (size, count int) {
+	best := 0
+	for n := 1; n <= foldMaxBlock && i+2*n <= len(keys); n++ {
+		if !hasContent(keys[i : i+n]) {
+			continue
+		}
+		c := 1
+		for i+(c+1)*n <= len(keys) && sameKeys(keys[i:i+n], keys[i+c*n:i+(c+1)*n]) {
+			c++
+		}
+		if c < 2 || (n == 1 && c < 3) {
+			continue
+		}
+		if c*n > best {
+			best, size, count = c*n, n, c
+		}
+	}
+	return size, count
+}
+
+func hasContent(keys []string) bool {
+	for _, k := range keys {
+		if strings.TrimSpace(k) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func sameKeys(a, b []string) bool {
+	for j := range a {
+		if a[j] != b[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// blockVariants describes how the copies of a repeated block differ: for each
+// copy, the words at the positions where any copy differs from the first. At
+// most foldMaxVariants distinct variants are listed, in order of appearance.
+// It returns nil for exact repeats.
+func blockVariants(block []string, size int) []string {
+	copies := len(block) / size
+	var variants []string
+	seen := map[string]bool{}
+	extra := 0
+	for c := 0; c < copies; c++ {
+		var parts []string
+		for j := 0; j < size; j++ {
+			parts = append(parts, lineVariant(block, size, j, c)...)
+		}
+		v := strings.Join(parts, " ")
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		if len(variants) == foldMaxVariants {
+			extra++
+			continue
+		}
+		variants = append(variants, v)
+	}
+	if extra > 0 {
+		variants = append(variants, fmt.Sprintf("+%d more", extra))
+	}
+	return variants
+}
+
+// lineVariant returns the words of line j of copy c that sit at positions where
+// any copy of line j differs. When the copies split into a different number of
+// words, the whole line is the variant.
+func lineVariant(block []string, size, j, c int) []string {
+	first := strings.Fields(block[j])
+	var differs []int
+	for k := j; k < len(block); k += size {
+		other := strings.Fields(block[k])
+		if len(other) != len(first) {
+			return []string{strings.TrimSpace(block[c*size+j])}
+		}
+		for p := range other {
+			if other[p] != first[p] && !slices.Contains(differs, p) {
+				differs = append(differs, p)
+			}
+		}
+	}
+	words := strings.Fields(block[c*size+j])
+	var out []string
+	for _, p := range differs {
+		out = append(out, words[p])
+	}
+	return out
+}
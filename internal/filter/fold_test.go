@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFoldRepeats_NearDuplicateLines(t *testing.T) {
+	input := `Compiling 3 files
+src/main/java/A.java:12: warning: [deprecation] getX() in Base has been deprecated
+src/main/java/B.java:40: warning: [deprecation] getX() in Base has been deprecated
+src/main/java/C.java:7: warning: [deprecation] getX() in Base has been deprecated
+BUILD SUCCESS
+`
+	want := `Compiling 3 files
+src/main/java/A.java:12: warning: [deprecation] getX() in Base has been deprecated  (repeated 3 times; variants: src/main/java/A.java:12:, src/main/java/B.java:40:, src/main/java/C.java:7:)
+BUILD SUCCESS
+`
+	result := FoldRepeats(Result{Filtered: input})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestFoldRepeats_Blocks(t *testing.T) {
+	input := "warning: unused import: `std::io`\n" +
+		" --> src/a.rs:1:5\n" +
+		"  |\n" +
+		"warning: unused import: `std::io`\n" +
+		" --> src/b.rs:1:5\n" +
+		"  |\n" +
+		"warning: `app` (lib) generated 2 warnings\n"
+	want := "warning: unused import: `std::io`\n" +
+		" --> src/a.rs:1:5\n" +
+		"  |\n" +
+		"(repeated 2 times; variants: src/a.rs:1:5, src/b.rs:1:5)\n" +
+		"warning: `app` (lib) generated 2 warnings\n"
+
+	result := FoldRepeats(Result{Filtered: input, WasReduced: true})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestFoldRepeats_ScatteredWarnings(t *testing.T) {
+	// The same plugin warning once per module, with other output in between.
+	input := `[INFO] Building core 1.0
+[WARNING] Parameter 'encoding' is unknown for plugin 'maven-resources-plugin:3.3.1'
+[INFO] Compiling 12 source files
+[INFO] Building api 1.0
+[WARNING] Parameter 'encoding' is unknown for plugin 'maven-resources-plugin:3.3.1'
+[INFO] Compiling 30 source files
+[INFO] Building web 1.0
+[WARNING] Parameter 'encoding' is unknown for plugin 'maven-resources-plugin:3.3.2'
+[INFO] BUILD SUCCESS
+`
+	want := `[INFO] Building core 1.0
+[WARNING] Parameter 'encoding' is unknown for plugin 'maven-resources-plugin:3.3.1'  (repeated 3 times; variants: 'maven-resources-plugin:3.3.1', 'maven-resources-plugin:3.3.2')
+[INFO] Compiling 12 source files
+[INFO] Building api 1.0
+[INFO] Compiling 30 source files
+[INFO] Building web 1.0
+[INFO] BUILD SUCCESS
+`
+	result := FoldRepeats(Result{Filtered: input})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestFoldRepeats_ScatteredWarningsKeepDetail(t *testing.T) {
+	// A warning followed by its location is not folded apart from it.
+	input := "warning: unused variable: `x`\n" +
+		" --> src/a.rs:2:9\n" +
+		"Compiling b v0.1.0\n" +
+		"warning: unused variable: `x`\n" +
+		" --> src/b.rs:4:9\n"
+	result := FoldRepeats(Result{Filtered: input})
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("expected output unchanged, got:\n%s", result.Filtered)
+	}
+}
+
+func TestFoldRepeats_ExactRepeatsAndVariantCap(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 4; i++ {
+		b.WriteString("npm warn config production Use `--omit=dev` instead.\n")
+	}
+	for i := 1; i <= 8; i++ {
+		fmt.Fprintf(&b, "retrying request %d of 8\n", i)
+	}
+	want := "npm warn config production Use `--omit=dev` instead.  (repeated 4 times)\n" +
+		"retrying request 1 of 8  (repeated 8 times; variants: 1, 2, 3, 4, 5, +3 more)\n"
+
+	result := FoldRepeats(Result{Filtered: b.String()})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestFoldRepeats_LeavesShortRepeatsAlone(t *testing.T) {
+	// Two copies of one line and runs of blank lines are not worth folding.
+	input := "ok\nok\n\n\n\ndone\n"
+	result := FoldRepeats(Result{Filtered: input})
+	if result.Filtered != input {
+		t.Errorf("expected output unchanged, got:\n%s", result.Filtered)
+	}
+	if result.WasReduced {
+		t.Error("expected WasReduced=false")
+	}
+}
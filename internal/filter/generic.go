@@ -49,6 +49,16 @@ func (s *GenericErrorStrategy) Filter(raw []byte, command string, args []string,
 	hadTrailing := endsWithNewline(cleaned)
 	lines := strings.Split(cleaned, "\n")
 
+	// A Go panic, goroutine dump, JVM stack trace or Python traceback is the
+	// error itself: condense it and keep the rest of the output around it
+	condensed, foundGo := condenseGoroutineDumps(lines)
+	condensed, foundJVM := condenseJVMTraces(condensed)
+	condensed, foundPython := condensePythonTracebacks(condensed)
+	if foundGo || foundJVM || foundPython {
+		filtered := ensureTrailingNewline(strings.Join(condensed, "\n"), hadTrailing)
+		return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+	}
+
 	// Find matching lines
 	matched := make([]bool, len(lines))
 	matchCount := 0
@@ -35,6 +35,10 @@ var (
 	goTestStandaloneFail = regexp.MustCompile(`^FAIL$`)
 )
 
+// ShortenPaths opts in to path shortening: test failures and panics name
+// source files.
+func (s *GoTestStrategy) ShortenPaths() {}
+
 func (s *GoTestStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
 	filterName := s.Name()
 	defer func() {
@@ -60,6 +64,11 @@ func (s *GoTestStrategy) Filter(raw []byte, command string, args []string, exitC
 		return Result{Filtered: cleaned, WasReduced: false}
 	}
 
+	// Panics and -timeout expiry dump every goroutine, and -race repeats
+	// reports for the same pair of accesses; condense both first
+	lines, _ = condenseGoroutineDumps(lines)
+	lines, _ = condenseRaceReports(lines)
+
 	// Parse test output
 	type testBlock struct {
 		name   string
@@ -170,11 +179,91 @@ func (s *GoTestStrategy) Filter(raw []byte, command string, args []string, exitC
 	return Result{Filtered: filtered, WasReduced: wasReduced}
 }
 
+// goTestResultRe matches per-test result lines, including indented subtests.
+var goTestResultRe = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+)`)
+
+// ParseOutcome extracts failing and passing tests and packages from `go test` output.
+// Test identifiers are "<package> <test>"; packages that fail without a failing
+// test (build failures, panics) are reported as failed on their own.
+func (s *GoTestStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	type pendingResult struct {
+		name   string
+		failed bool
+	}
+	var pending []pendingResult
+	found := false
+
+	flush := func(pkg string, pkgFailed bool) {
+		failedInPkg := false
+		for _, p := range pending {
+			id := p.name
+			if pkg != "" {
+				id = pkg + " " + p.name
+			}
+			if p.failed {
+				o.Failed = append(o.Failed, id)
+				failedInPkg = true
+			} else {
+				o.Passed = append(o.Passed, id)
+			}
+		}
+		pending = nil
+		if pkg == "" {
+			return
+		}
+		if pkgFailed {
+			if !failedInPkg {
+				o.Failed = append(o.Failed, pkg)
+			}
+		} else {
+			o.Passed = append(o.Passed, pkg)
+		}
+	}
+
+	for _, line := range strings.Split(StripANSIString(string(raw)), "\n") {
+		if m := goTestResultRe.FindStringSubmatch(line); len(m) > 2 {
+			if m[1] != "SKIP" {
+				pending = append(pending, pendingResult{name: m[2], failed: m[1] == "FAIL"})
+			}
+			found = true
+			continue
+		}
+		if after, ok := strings.CutPrefix(line, "ok  \t"); ok {
+			flush(goTestPackageField(after), false)
+			found = true
+			continue
+		}
+		if after, ok := strings.CutPrefix(line, "FAIL\t"); ok {
+			flush(goTestPackageField(after), true)
+			found = true
+			continue
+		}
+		if goBuildErrorRe.MatchString(line) {
+			o.Errors = append(o.Errors, line)
+			found = true
+		}
+	}
+	flush("", false)
+
+	return o, found
+}
+
+// goTestPackageField returns the package path from the remainder of a
+// "ok  \t<pkg>\t<time>" or "FAIL\t<pkg> [build failed]" summary line.
+func goTestPackageField(rest string) string {
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
 // ---------------------------------------------------------------------------
 // GoBuildStrategy
 // ---------------------------------------------------------------------------
 
-// GoBuildStrategy filters `go build`, `go vet`, and `go install` output.
+// GoBuildStrategy filters `go build` and `go install` output.
 type GoBuildStrategy struct{}
 
 func (s *GoBuildStrategy) Name() string { return "go-build" }
@@ -184,13 +273,15 @@ func (s *GoBuildStrategy) CanHandle(command string, args []string) bool {
 		return false
 	}
 	return isSubcommand(args, "build", goValueFlags) ||
-		isSubcommand(args, "vet", goValueFlags) ||
 		isSubcommand(args, "install", goValueFlags)
 }
 
 // goBuildErrorRe matches compiler-style error lines: file.go:line:col: message
 var goBuildErrorRe = regexp.MustCompile(`^\S+\.go:\d+:\d+:`)
 
+// ShortenPaths opts in to path shortening for compiler error locations.
+func (s *GoBuildStrategy) ShortenPaths() {}
+
 func (s *GoBuildStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
 	filterName := s.Name()
 	defer func() {
@@ -243,3 +334,122 @@ func (s *GoBuildStrategy) Filter(raw []byte, command string, args []string, exit
 
 	return Result{Filtered: filtered, WasReduced: true}
 }
+
+// ParseOutcome extracts compiler errors from `go build`/`go install` output.
+func (s *GoBuildStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	for _, line := range strings.Split(StripANSIString(string(raw)), "\n") {
+		if goBuildErrorRe.MatchString(line) {
+			o.Errors = append(o.Errors, line)
+		}
+	}
+	return o, true
+}
+
+// FilterStderr filters compiler errors, which `go build` writes to stderr.
+func (s *GoBuildStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ---------------------------------------------------------------------------
+// GoModStrategy
+// ---------------------------------------------------------------------------
+
+// GoModStrategy filters `go mod` and `go get` dependency output. The go
+// command reports downloads and module changes on stderr, so the work happens
+// in FilterStderr; stdout (e.g. `go mod graph`) passes through.
+type GoModStrategy struct{}
+
+func (s *GoModStrategy) Name() string { return "go-mod" }
+
+func (s *GoModStrategy) CanHandle(command string, args []string) bool {
+	return command == "go" && (isSubcommand(args, "mod", goValueFlags) || isSubcommand(args, "get", goValueFlags))
+}
+
+// Package-level compiled regexes for GoModStrategy.
+var (
+	// goModChangeRe matches "go: upgraded M v1 => v2", "go: added M v1" and friends.
+	goModChangeRe = regexp.MustCompile(`^go: (upgraded|downgraded|added|removed) (\S+) (.+)$`)
+	// goModDownloadRe matches "go: downloading M v1".
+	goModDownloadRe = regexp.MustCompile(`^go: downloading \S+ \S+$`)
+	// goModResolveRe matches package lookups printed while resolving imports.
+	goModResolveRe = regexp.MustCompile(`^go: (finding module for package|found) \S+`)
+)
+
+func (s *GoModStrategy) Filter(raw []byte, command string, args []string, exitCode int) Result {
+	return Result{Filtered: string(raw), WasReduced: false}
+}
+
+// FilterStderr collapses downloads and package lookups into counts and lists
+// module changes as a table. Anything else, such as checksum mismatches or
+// missing modules, is kept in full.
+func (s *GoModStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var kept []string
+	var changes [][3]string
+	downloads, resolved := 0, 0
+	for _, line := range lines {
+		switch {
+		case goModDownloadRe.MatchString(line):
+			downloads++
+		case goModResolveRe.MatchString(line):
+			resolved++
+		default:
+			if m := goModChangeRe.FindStringSubmatch(line); m != nil {
+				changes = append(changes, [3]string{m[1], m[2], m[3]})
+				continue
+			}
+			if strings.TrimSpace(line) != "" || len(kept) > 0 {
+				kept = append(kept, line)
+			}
+		}
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	out := kept
+	if len(changes) > 0 {
+		width := 0
+		for _, c := range changes {
+			width = max(width, len(c[1]))
+		}
+		for _, c := range changes {
+			out = append(out, fmt.Sprintf("%-10s  %-*s  %s", c[0], width, c[1], c[2]))
+		}
+	}
+
+	var counts []string
+	if downloads > 0 {
+		counts = append(counts, fmt.Sprintf("%d modules downloaded", downloads))
+	}
+	if resolved > 0 {
+		counts = append(counts, fmt.Sprintf("%d package lookups", resolved))
+	}
+	if len(changes) > 0 {
+		counts = append(counts, fmt.Sprintf("%d module changes", len(changes)))
+	}
+	if len(counts) > 0 {
+		out = append(out, "go: "+strings.Join(counts, ", "))
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
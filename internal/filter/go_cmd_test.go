@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -209,8 +210,7 @@ func TestGoBuildStrategy_CanHandle(t *testing.T) {
 	}{
 		{"go build bare", "go", []string{"build"}, true},
 		{"go build with flags", "go", []string{"build", "./..."}, true},
-		{"go vet bare", "go", []string{"vet"}, true},
-		{"go vet with flags", "go", []string{"vet", "./..."}, true},
+		{"go vet", "go", []string{"vet"}, false},
 		{"go install", "go", []string{"install"}, true},
 		{"go install with leading flags", "go", []string{"-v", "install"}, true},
 		{"go test", "go", []string{"test"}, false},
@@ -388,3 +388,157 @@ func TestGoTestStrategy_Filter_Subtests(t *testing.T) {
 		t.Error("passing subtest PASS line should be stripped on failure")
 	}
 }
+
+func TestGoTestStrategy_ParseOutcome(t *testing.T) {
+	s := &GoTestStrategy{}
+
+	input := "=== RUN   TestGood\n" +
+		"--- PASS: TestGood (0.00s)\n" +
+		"=== RUN   TestBroken\n" +
+		"=== RUN   TestBroken/sub\n" +
+		"    broken_test.go:42: expected 5, got 3\n" +
+		"    --- FAIL: TestBroken/sub (0.00s)\n" +
+		"--- FAIL: TestBroken (0.01s)\n" +
+		"FAIL\n" +
+		"FAIL\tgithub.com/example/failing\t0.234s\n" +
+		"ok  \tgithub.com/example/passing\t0.123s\n" +
+		"FAIL\tgithub.com/example/broken [build failed]\n" +
+		"./broken.go:3:2: undefined: x\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "go", []string{"test", "./..."}, 1)
+	if !ok {
+		t.Fatal("expected outcome to be parsed")
+	}
+
+	wantFailed := []string{
+		"github.com/example/failing TestBroken/sub",
+		"github.com/example/failing TestBroken",
+		"github.com/example/broken",
+	}
+	if strings.Join(o.Failed, ",") != strings.Join(wantFailed, ",") {
+		t.Errorf("Failed = %v, want %v", o.Failed, wantFailed)
+	}
+	wantPassed := []string{"github.com/example/failing TestGood", "github.com/example/passing"}
+	if strings.Join(o.Passed, ",") != strings.Join(wantPassed, ",") {
+		t.Errorf("Passed = %v, want %v", o.Passed, wantPassed)
+	}
+	if len(o.Errors) != 1 || o.Errors[0] != "./broken.go:3:2: undefined: x" {
+		t.Errorf("Errors = %v, want the compiler error", o.Errors)
+	}
+}
+
+func TestGoBuildStrategy_ParseOutcome(t *testing.T) {
+	s := &GoBuildStrategy{}
+
+	input := "# github.com/example/pkg\n" +
+		"./main.go:10:5: undefined: foo\n" +
+		"./main.go:20:1: missing return\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "go", []string{"build"}, 1)
+	if !ok {
+		t.Fatal("expected outcome to be parsed")
+	}
+	if len(o.Errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", o.Errors)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GoModStrategy
+// ---------------------------------------------------------------------------
+
+func TestGoModStrategy_CanHandle(t *testing.T) {
+	s := &GoModStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"go mod tidy", "go", []string{"mod", "tidy"}, true},
+		{"go mod download", "go", []string{"mod", "download"}, true},
+		{"go get", "go", []string{"get", "-u", "./..."}, true},
+		{"go get with -C", "go", []string{"-C", "sub", "get", "example.com/m"}, true},
+		{"go build", "go", []string{"build"}, false},
+		{"not go", "cargo", []string{"update"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoModStrategy_FilterStderr_GoGet(t *testing.T) {
+	s := &GoModStrategy{}
+
+	var b strings.Builder
+	for i := range 8 {
+		b.WriteString(fmt.Sprintf("go: downloading example.com/dep%d v1.%d.0\n", i, i))
+	}
+	b.WriteString("go: finding module for package example.com/x/pkg\n")
+	b.WriteString("go: found example.com/x/pkg in example.com/x v0.3.0\n")
+	b.WriteString("go: upgraded golang.org/x/mod v0.16.0 => v0.17.0\n")
+	b.WriteString("go: added example.com/x v0.3.0\n")
+	b.WriteString("go: removed example.com/old v1.0.0\n")
+
+	result := s.FilterStderr([]byte(b.String()), "go", []string{"get", "-u", "./..."}, 0)
+
+	want := "upgraded    golang.org/x/mod  v0.16.0 => v0.17.0\n" +
+		"added       example.com/x     v0.3.0\n" +
+		"removed     example.com/old   v1.0.0\n" +
+		"go: 8 modules downloaded, 2 package lookups, 3 module changes\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestGoModStrategy_FilterStderr_KeepsErrors(t *testing.T) {
+	s := &GoModStrategy{}
+
+	var b strings.Builder
+	for i := range 8 {
+		b.WriteString(fmt.Sprintf("go: downloading example.com/dep%d v1.0.0\n", i))
+	}
+	checksum := "verifying example.com/dep3@v1.0.0: checksum mismatch\n" +
+		"\tdownloaded: h1:AAAA\n" +
+		"\tgo.sum:     h1:BBBB\n" +
+		"\n" +
+		"SECURITY ERROR\n" +
+		"This download does NOT match an earlier download recorded in go.sum.\n"
+	b.WriteString(checksum)
+
+	result := s.FilterStderr([]byte(b.String()), "go", []string{"mod", "download"}, 1)
+
+	want := checksum + "go: 8 modules downloaded\n"
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestGoModStrategy_FilterStderr_SmallOutputPassesThrough(t *testing.T) {
+	s := &GoModStrategy{}
+	input := "go: downloading example.com/dep v1.0.0\ngo: added example.com/dep v1.0.0\n"
+	result := s.FilterStderr([]byte(input), "go", []string{"get", "example.com/dep"}, 0)
+	if result.WasReduced || result.Filtered != input {
+		t.Errorf("small output should pass through, got %q", result.Filtered)
+	}
+}
+
+func TestGoModStrategy_Filter_StdoutPassesThrough(t *testing.T) {
+	s := &GoModStrategy{}
+	input := "example.com/m golang.org/x/mod@v0.17.0\n"
+	result := s.Filter([]byte(input), "go", []string{"mod", "graph"}, 0)
+	if result.WasReduced || result.Filtered != input {
+		t.Errorf("stdout should pass through, got %q", result.Filtered)
+	}
+}
@@ -0,0 +1,408 @@
+package filter
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// GoLintStrategy
+// ---------------------------------------------------------------------------
+
+// GoLintStrategy filters Go linter output from `go vet`, `staticcheck` and
+// `golangci-lint run`. Diagnostics are grouped by linter and file, identical
+// messages are deduplicated, and a totals table closes the output.
+type GoLintStrategy struct{}
+
+func (s *GoLintStrategy) Name() string { return "go-lint" }
+
+func (s *GoLintStrategy) CanHandle(command string, args []string) bool {
+	switch command {
+	case "go":
+		return isSubcommand(args, "vet", goValueFlags)
+	case "staticcheck":
+		return true
+	case "golangci-lint":
+		return isSubcommand(args, "run", nil)
+	}
+	return false
+}
+
+// goLintDiag is a single linter diagnostic.
+type goLintDiag struct {
+	file    string
+	line    int
+	col     int
+	message string
+	linter  string
+}
+
+// location renders "file:line:col" (col omitted when unknown).
+func (d goLintDiag) location() string {
+	if d.col > 0 {
+		return fmt.Sprintf("%s:%d:%d", d.file, d.line, d.col)
+	}
+	return fmt.Sprintf("%s:%d", d.file, d.line)
+}
+
+// Package-level compiled regexes for GoLintStrategy.
+var (
+	// goLintTextRe matches "file.go:line:col: message (linter)"; col and linter are optional.
+	goLintTextRe = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?: (.*?)(?: \(([\w.-]+)\))?$`)
+	// goLintTabRe matches golangci-lint's tab format: "file.go:line:col  linter  message".
+	goLintTabRe = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?(?:\t+| {2,})([\w.-]+)(?:\t+| {2,})(.*)$`)
+	// goLintGitHubRe matches golangci-lint's github-actions format.
+	goLintGitHubRe = regexp.MustCompile(`^::(?:error|warning|notice) file=([^,]+),line=(\d+)(?:,col=(\d+))?::(.*?)(?: \(([\w.-]+)\))?$`)
+	// goLintSummaryRe matches golangci-lint's trailing "N issues:" / "* linter: N" summary.
+	goLintSummaryRe = regexp.MustCompile(`^\d+ issues?:?$|^\* [\w.-]+: \d+$`)
+	// goLintCaretRe matches the caret line golangci-lint prints under a snippet.
+	goLintCaretRe = regexp.MustCompile(`^\s*\^+\s*$`)
+)
+
+const (
+	// goLintMaxPerLinter caps the distinct messages listed per linter.
+	goLintMaxPerLinter = 8
+	// goLintMaxLocations caps the locations listed for one deduplicated message.
+	goLintMaxLocations = 3
+)
+
+// golangciJSONReport is golangci-lint's `--out-format json` document.
+type golangciJSONReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+// codeClimateIssue is one entry of golangci-lint's `--out-format code-climate` array.
+type codeClimateIssue struct {
+	Description string `json:"description"`
+	CheckName   string `json:"check_name"`
+	Location    struct {
+		Path  string `json:"path"`
+		Lines struct {
+			Begin int `json:"begin"`
+		} `json:"lines"`
+	} `json:"location"`
+}
+
+// checkstyleReport is golangci-lint's `--out-format checkstyle` document.
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line    int    `xml:"line,attr"`
+			Column  int    `xml:"column,attr"`
+			Message string `xml:"message,attr"`
+			Source  string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+// findGolangciJSON finds the golangci-lint JSON report line in output and
+// returns it decoded, with the other non-blank lines.
+func findGolangciJSON(output string) (golangciJSONReport, []string, bool) {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"Issues"`) {
+			continue
+		}
+		var report golangciJSONReport
+		if err := json.Unmarshal([]byte(line), &report); err != nil {
+			continue
+		}
+		var other []string
+		for j, l := range lines {
+			if j != i && strings.TrimSpace(l) != "" {
+				other = append(other, l)
+			}
+		}
+		return report, other, true
+	}
+	return golangciJSONReport{}, nil, false
+}
+
+// parseGoLintOutput extracts diagnostics from any supported format. It
+// returns the diagnostics, the lines it could not attribute to a diagnostic,
+// and whether the input was a structured (JSON/XML) document.
+func parseGoLintOutput(cleaned, defaultLinter string) ([]goLintDiag, []string, bool) {
+	trimmed := strings.TrimSpace(cleaned)
+
+	// golangci-lint prints its JSON report on one line, possibly after log
+	// lines (config warnings, deprecation notices), which are kept.
+	if report, other, ok := findGolangciJSON(trimmed); ok {
+		var diags []goLintDiag
+		for _, is := range report.Issues {
+			diags = append(diags, goLintDiag{
+				file: is.Pos.Filename, line: is.Pos.Line, col: is.Pos.Column,
+				message: is.Text, linter: is.FromLinter,
+			})
+		}
+		return diags, other, true
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		var issues []codeClimateIssue
+		if err := json.Unmarshal([]byte(trimmed), &issues); err == nil {
+			var diags []goLintDiag
+			for _, is := range issues {
+				diags = append(diags, goLintDiag{
+					file: is.Location.Path, line: is.Location.Lines.Begin,
+					message: is.Description, linter: is.CheckName,
+				})
+			}
+			return diags, nil, true
+		}
+	case strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<checkstyle"):
+		var report checkstyleReport
+		if err := xml.Unmarshal([]byte(trimmed), &report); err == nil {
+			var diags []goLintDiag
+			for _, f := range report.Files {
+				for _, e := range f.Errors {
+					diags = append(diags, goLintDiag{
+						file: f.Name, line: e.Line, col: e.Column,
+						message: e.Message, linter: e.Source,
+					})
+				}
+			}
+			return diags, nil, true
+		}
+	}
+
+	var diags []goLintDiag
+	var rest []string
+	lines := strings.Split(cleaned, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if d, ok := parseGoLintLine(line, defaultLinter); ok {
+			diags = append(diags, d)
+			i += goLintSnippetLen(lines[i+1:], defaultLinter)
+			continue
+		}
+		if strings.HasPrefix(line, "# ") || goLintSummaryRe.MatchString(line) {
+			continue
+		}
+		rest = append(rest, line)
+	}
+	return diags, rest, false
+}
+
+// goLintSnippetLen returns how many of the lines following a golangci-lint
+// issue are its source snippet: the offending line and, when the column is
+// known, a caret line beneath it.
+func goLintSnippetLen(next []string, defaultLinter string) int {
+	if defaultLinter != "golangci-lint" || len(next) == 0 {
+		return 0
+	}
+	if goLintCaretRe.MatchString(next[0]) {
+		return 1
+	}
+	if _, ok := parseGoLintLine(next[0], defaultLinter); ok || strings.TrimSpace(next[0]) == "" {
+		return 0
+	}
+	if len(next) > 1 && goLintCaretRe.MatchString(next[1]) {
+		return 2
+	}
+	return 0
+}
+
+// parseGoLintLine parses a single text-format diagnostic line.
+func parseGoLintLine(line, defaultLinter string) (goLintDiag, bool) {
+	var m []string
+	var linter, message string
+	if m = goLintGitHubRe.FindStringSubmatch(line); m != nil {
+		message, linter = m[4], m[5]
+	} else if m = goLintTabRe.FindStringSubmatch(line); m != nil {
+		linter, message = m[4], m[5]
+	} else if m = goLintTextRe.FindStringSubmatch(line); m != nil {
+		message, linter = m[4], m[5]
+		// go vet names no linter; a trailing parenthesis is part of its message
+		if defaultLinter == "vet" && linter != "" {
+			message, linter = message+" ("+linter+")", ""
+		}
+	} else {
+		return goLintDiag{}, false
+	}
+	if linter == "" {
+		linter = defaultLinter
+	}
+	lineNo, _ := strconv.Atoi(m[2])
+	col, _ := strconv.Atoi(m[3])
+	return goLintDiag{file: m[1], line: lineNo, col: col, message: message, linter: linter}, true
+}
+
+// goLintDefaultLinter names diagnostics that carry no linter of their own.
+func goLintDefaultLinter(command string) string {
+	switch command {
+	case "go":
+		return "vet"
+	case "staticcheck":
+		return "staticcheck"
+	}
+	return "golangci-lint"
+}
+
+// ShortenPaths opts in to path shortening for the files linters report.
+func (s *GoLintStrategy) ShortenPaths() {}
+
+func (s *GoLintStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	diags, rest, structured := parseGoLintOutput(cleaned, goLintDefaultLinter(command))
+
+	// Short plain-text output is already readable; structured documents never are
+	if !structured && (len(diags) == 0 || strings.Count(cleaned, "\n") < 10) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	if structured && len(diags) == 0 {
+		filtered := strings.Join(append([]string{"no issues"}, rest...), "\n") + "\n"
+		return Result{Filtered: filtered, WasReduced: len(cleaned) > len(filtered)}
+	}
+
+	out := renderGoLintGroups(diags)
+	out = append(out, rest...)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing || structured)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned) || structured}
+}
+
+// renderGoLintGroups groups diagnostics by linter, then file, then identical
+// message, and appends a totals table.
+func renderGoLintGroups(diags []goLintDiag) []string {
+	type msgGroup struct {
+		message   string
+		locations []string
+	}
+	type fileGroup struct {
+		name string
+		msgs []*msgGroup
+	}
+	type linterGroup struct {
+		name   string
+		count  int
+		files  []*fileGroup
+		byFile map[string]*fileGroup
+	}
+
+	var linters []*linterGroup
+	byLinter := map[string]*linterGroup{}
+	allFiles := map[string]bool{}
+	for _, d := range diags {
+		lg, ok := byLinter[d.linter]
+		if !ok {
+			lg = &linterGroup{name: d.linter, byFile: map[string]*fileGroup{}}
+			byLinter[d.linter] = lg
+			linters = append(linters, lg)
+		}
+		lg.count++
+		allFiles[d.file] = true
+		fg, ok := lg.byFile[d.file]
+		if !ok {
+			fg = &fileGroup{name: d.file}
+			lg.byFile[d.file] = fg
+			lg.files = append(lg.files, fg)
+		}
+		var mg *msgGroup
+		for _, existing := range fg.msgs {
+			if existing.message == d.message {
+				mg = existing
+				break
+			}
+		}
+		if mg == nil {
+			mg = &msgGroup{message: d.message}
+			fg.msgs = append(fg.msgs, mg)
+		}
+		pos := strconv.Itoa(d.line)
+		if d.col > 0 {
+			pos += ":" + strconv.Itoa(d.col)
+		}
+		mg.locations = append(mg.locations, pos)
+	}
+
+	var out []string
+	for _, lg := range linters {
+		out = append(out, fmt.Sprintf("%s (%d issues in %d files):", lg.name, lg.count, len(lg.files)))
+		shown, hiddenIssues := 0, 0
+		hiddenFiles := map[string]bool{}
+		for _, fg := range lg.files {
+			fileHeaderDone := false
+			for _, mg := range fg.msgs {
+				if shown >= goLintMaxPerLinter {
+					hiddenIssues += len(mg.locations)
+					hiddenFiles[fg.name] = true
+					continue
+				}
+				if !fileHeaderDone {
+					out = append(out, "  "+fg.name+":")
+					fileHeaderDone = true
+				}
+				locs := mg.locations
+				suffix := ""
+				if len(locs) > goLintMaxLocations {
+					suffix = fmt.Sprintf(" +%d more", len(locs)-goLintMaxLocations)
+					locs = locs[:goLintMaxLocations]
+				}
+				out = append(out, fmt.Sprintf("    %s: %s%s", strings.Join(locs, ", "), mg.message, suffix))
+				shown++
+			}
+		}
+		if hiddenIssues > 0 {
+			out = append(out, fmt.Sprintf("  ... and %d more %s issues in %d files", hiddenIssues, lg.name, len(hiddenFiles)))
+		}
+	}
+
+	// Totals table
+	width := len("linter")
+	for _, lg := range linters {
+		width = max(width, len(lg.name))
+	}
+	out = append(out, "")
+	out = append(out, fmt.Sprintf("%-*s  %6s  %5s", width, "linter", "issues", "files"))
+	for _, lg := range linters {
+		out = append(out, fmt.Sprintf("%-*s  %6d  %5d", width, lg.name, lg.count, len(lg.files)))
+	}
+	out = append(out, fmt.Sprintf("%-*s  %6d  %5d", width, "total", len(diags), len(allFiles)))
+	return out
+}
+
+// FilterStderr filters `go vet` diagnostics, which are written to stderr.
+func (s *GoLintStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ParseOutcome reports each diagnostic as "file:line:col: message (linter)"
+// so `--since-last` can show new and fixed lint issues.
+func (s *GoLintStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	diags, _, _ := parseGoLintOutput(StripANSIString(string(raw)), goLintDefaultLinter(command))
+	var o Outcome
+	for _, d := range diags {
+		o.Errors = append(o.Errors, fmt.Sprintf("%s: %s (%s)", d.location(), d.message, d.linter))
+	}
+	return o, true
+}
@@ -0,0 +1,231 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// GoLintStrategy
+// ---------------------------------------------------------------------------
+
+func TestGoLintStrategy_CanHandle(t *testing.T) {
+	s := &GoLintStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"go vet", "go", []string{"vet", "./..."}, true},
+		{"go vet with leading flags", "go", []string{"-C", "sub", "vet"}, true},
+		{"staticcheck", "staticcheck", []string{"./..."}, true},
+		{"staticcheck bare", "staticcheck", nil, true},
+		{"golangci-lint run", "golangci-lint", []string{"run", "./..."}, true},
+		{"golangci-lint linters", "golangci-lint", []string{"linters"}, false},
+		{"go build", "go", []string{"build"}, false},
+		{"go test", "go", []string{"test"}, false},
+		{"not a linter", "cargo", []string{"clippy"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoLintStrategy_Name(t *testing.T) {
+	if got := (&GoLintStrategy{}).Name(); got != "go-lint" {
+		t.Errorf("Name() = %q, want %q", got, "go-lint")
+	}
+}
+
+func TestGoLintStrategy_Filter_SmallOutputPassesThrough(t *testing.T) {
+	s := &GoLintStrategy{}
+	input := "# demo\n./main.go:5:2: fmt.Printf format %d has arg s of wrong type string\n"
+	result := s.Filter([]byte(input), "go", []string{"vet", "./..."}, 1)
+	if result.WasReduced || result.Filtered != input {
+		t.Errorf("small output should pass through, got %q", result.Filtered)
+	}
+}
+
+func TestGoLintStrategy_Filter_GolangciText(t *testing.T) {
+	s := &GoLintStrategy{}
+
+	var b strings.Builder
+	for _, line := range []int{10, 20, 30, 40} {
+		b.WriteString(fmt.Sprintf("internal/a.go:%d:2: Error return value of `f.Close` is not checked (errcheck)\n", line))
+		b.WriteString("\tf.Close()\n")
+		b.WriteString("\t^\n")
+	}
+	b.WriteString("internal/b.go:7:1: exported function Foo should have comment or be unexported (revive)\n")
+	b.WriteString("func Foo() {}\n")
+	b.WriteString("^\n")
+	b.WriteString("internal/b.go:9:6: S1021: should merge variable declaration with assignment on next line (gosimple)\n")
+	b.WriteString("5 issues:\n")
+	b.WriteString("* errcheck: 4\n")
+
+	result := s.Filter([]byte(b.String()), "golangci-lint", []string{"run"}, 1)
+
+	want := "errcheck (4 issues in 1 files):\n" +
+		"  internal/a.go:\n" +
+		"    10:2, 20:2, 30:2: Error return value of `f.Close` is not checked +1 more\n" +
+		"revive (1 issues in 1 files):\n" +
+		"  internal/b.go:\n" +
+		"    7:1: exported function Foo should have comment or be unexported\n" +
+		"gosimple (1 issues in 1 files):\n" +
+		"  internal/b.go:\n" +
+		"    9:6: S1021: should merge variable declaration with assignment on next line\n" +
+		"\n" +
+		"linter    issues  files\n" +
+		"errcheck       4      1\n" +
+		"revive         1      1\n" +
+		"gosimple       1      1\n" +
+		"total          6      2\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestGoLintStrategy_Filter_CapsPerLinter(t *testing.T) {
+	s := &GoLintStrategy{}
+
+	var b strings.Builder
+	for i := range 12 {
+		b.WriteString(fmt.Sprintf("pkg/f%d.go:%d:1: unused variable v%d (SA4006)\n", i%4, i+1, i))
+	}
+
+	result := s.Filter([]byte(b.String()), "staticcheck", []string{"./..."}, 1)
+
+	if !strings.Contains(result.Filtered, "SA4006 (12 issues in 4 files):") {
+		t.Errorf("expected linter headline, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "  ... and 4 more SA4006 issues in 2 files") {
+		t.Errorf("expected overflow line, got:\n%s", result.Filtered)
+	}
+}
+
+func TestGoLintStrategy_Filter_VetKeepsParentheses(t *testing.T) {
+	s := &GoLintStrategy{}
+
+	var b strings.Builder
+	b.WriteString("# example.com/demo\n")
+	for i := range 10 {
+		b.WriteString(fmt.Sprintf("./main.go:%d:2: result of fmt.Sprintf call not used (unusedresult)\n", i+1))
+	}
+
+	result := s.Filter([]byte(b.String()), "go", []string{"vet", "./..."}, 1)
+
+	if !strings.HasPrefix(result.Filtered, "vet (10 issues in 1 files):\n") {
+		t.Errorf("go vet diagnostics should be grouped under vet, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "result of fmt.Sprintf call not used (unusedresult)") {
+		t.Errorf("vet message should keep its parenthesis, got:\n%s", result.Filtered)
+	}
+}
+
+func TestGoLintStrategy_Filter_JSON(t *testing.T) {
+	s := &GoLintStrategy{}
+	input := `{"Issues":[` +
+		`{"FromLinter":"errcheck","Text":"Error return value is not checked","Pos":{"Filename":"a.go","Line":3,"Column":2}},` +
+		`{"FromLinter":"govet","Text":"printf: bad verb","Pos":{"Filename":"b.go","Line":8,"Column":4}}` +
+		`],"Report":{"Linters":[]}}` + "\n"
+
+	result := s.Filter([]byte(input), "golangci-lint", []string{"run", "--out-format", "json"}, 1)
+
+	want := "errcheck (1 issues in 1 files):\n" +
+		"  a.go:\n" +
+		"    3:2: Error return value is not checked\n" +
+		"govet (1 issues in 1 files):\n" +
+		"  b.go:\n" +
+		"    8:4: printf: bad verb\n" +
+		"\n" +
+		"linter    issues  files\n" +
+		"errcheck       1      1\n" +
+		"govet          1      1\n" +
+		"total          2      2\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestGoLintStrategy_Filter_JSONAfterLogLines(t *testing.T) {
+	s := &GoLintStrategy{}
+	input := "level=warning msg=\"[config_reader] The configuration option `run.skip-dirs` is deprecated\"\n" +
+		`{"Issues":[{"FromLinter":"errcheck","Text":"Error return value is not checked","Pos":{"Filename":"a.go","Line":3,"Column":2}}],"Report":{"Linters":[]}}` + "\n"
+
+	result := s.Filter([]byte(input), "golangci-lint", []string{"run", "--out-format", "json"}, 1)
+
+	if !strings.HasPrefix(result.Filtered, "errcheck (1 issues in 1 files):\n  a.go:\n    3:2: Error return value is not checked\n") {
+		t.Errorf("JSON report after a log line should be parsed, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "run.skip-dirs` is deprecated") {
+		t.Errorf("log line should be kept, got:\n%s", result.Filtered)
+	}
+	if strings.Contains(result.Filtered, `"Issues"`) {
+		t.Errorf("raw JSON should not be printed, got:\n%s", result.Filtered)
+	}
+}
+
+func TestGoLintStrategy_Filter_OtherFormats(t *testing.T) {
+	s := &GoLintStrategy{}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"code-climate", `[{"description":"errcheck: Error return value is not checked","check_name":"errcheck",` +
+			`"location":{"path":"a.go","lines":{"begin":3}}}]`},
+		{"checkstyle", `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<checkstyle version="5.0"><file name="a.go">` +
+			`<error column="2" line="3" message="Error return value is not checked" severity="error" source="errcheck"></error>` +
+			`</file></checkstyle>`},
+		{"tab", "a.go:3:2\terrcheck\tError return value is not checked\n"},
+		{"github-actions", "::error file=a.go,line=3,col=2::Error return value is not checked (errcheck)\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o, _ := s.ParseOutcome([]byte(tc.input), "golangci-lint", []string{"run"}, 1)
+			if len(o.Errors) != 1 {
+				t.Fatalf("Errors = %v, want one diagnostic", o.Errors)
+			}
+			if !strings.HasPrefix(o.Errors[0], "a.go:3") || !strings.HasSuffix(o.Errors[0], "(errcheck)") {
+				t.Errorf("Errors[0] = %q, want a.go:3 ... (errcheck)", o.Errors[0])
+			}
+		})
+	}
+}
+
+func TestGoLintStrategy_Filter_StructuredClean(t *testing.T) {
+	s := &GoLintStrategy{}
+	result := s.Filter([]byte(`{"Issues":[],"Report":{}}`+"\n"), "golangci-lint", []string{"run"}, 0)
+	if result.Filtered != "no issues\n" {
+		t.Errorf("Filtered = %q, want %q", result.Filtered, "no issues\n")
+	}
+}
+
+func TestGoLintStrategy_ParseOutcome(t *testing.T) {
+	s := &GoLintStrategy{}
+	input := "# demo\n./main.go:5:2: fmt.Printf format %d has arg s of wrong type string\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "go", []string{"vet"}, 1)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := "./main.go:5:2: fmt.Printf format %d has arg s of wrong type string (vet)"
+	if len(o.Errors) != 1 || o.Errors[0] != want {
+		t.Errorf("Errors = %v, want [%s]", o.Errors, want)
+	}
+}
@@ -0,0 +1,404 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// GoTestJSONStrategy
+// ---------------------------------------------------------------------------
+
+// GoTestJSONStrategy filters `go test -json` output by decoding the test2json
+// event stream. Unlike the text format, events carry their package and test,
+// so interleaved parallel output is attributed correctly.
+type GoTestJSONStrategy struct{}
+
+func (s *GoTestJSONStrategy) Name() string { return "go-test-json" }
+
+func (s *GoTestJSONStrategy) CanHandle(command string, args []string) bool {
+	return command == "go" && isSubcommand(args, "test", goValueFlags) && hasGoJSONFlag(args)
+}
+
+// hasGoJSONFlag reports whether args request test2json output.
+func hasGoJSONFlag(args []string) bool {
+	for _, a := range args {
+		if a == "-args" {
+			return false
+		}
+		if a == "-json" || a == "--json" || a == "-json=true" || a == "--json=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// goTestEvent is a single test2json event (see `go doc test2json`).
+type goTestEvent struct {
+	Action      string  `json:"Action"`
+	Package     string  `json:"Package"`
+	Test        string  `json:"Test"`
+	Elapsed     float64 `json:"Elapsed"`
+	Output      string  `json:"Output"`
+	ImportPath  string  `json:"ImportPath"`
+	FailedBuild string  `json:"FailedBuild"`
+}
+
+// goJSONLine is an output line tagged with its position in the stream.
+type goJSONLine struct {
+	seq  int
+	text string
+}
+
+// goJSONTest accumulates the events of one test.
+type goJSONTest struct {
+	pkg     string
+	name    string
+	output  []goJSONLine
+	action  string // final action: pass, fail or skip; empty if still running
+	elapsed float64
+}
+
+// goJSONPackage accumulates the events of one package.
+type goJSONPackage struct {
+	name        string
+	output      []string // package-level output (Test == "")
+	action      string   // final action: pass, fail or skip
+	elapsed     float64
+	failedBuild bool
+	timedOut    bool // the test binary panicked on -timeout expiry
+}
+
+// goJSONRun is the decoded form of a whole `go test -json` stream.
+type goJSONRun struct {
+	packages    []*goJSONPackage
+	tests       []*goJSONTest
+	buildOutput map[string][]string // import path → compiler output
+	buildOrder  []string
+	other       []string // lines that are not test2json events
+}
+
+// parseGoTestJSON decodes a test2json stream. Non-JSON lines are kept in other.
+func parseGoTestJSON(cleaned string) *goJSONRun {
+	run := &goJSONRun{buildOutput: map[string][]string{}}
+	pkgIndex := map[string]*goJSONPackage{}
+	testIndex := map[string]*goJSONTest{}
+
+	pkg := func(name string) *goJSONPackage {
+		p, ok := pkgIndex[name]
+		if !ok {
+			p = &goJSONPackage{name: name}
+			pkgIndex[name] = p
+			run.packages = append(run.packages, p)
+		}
+		return p
+	}
+
+	for seq, line := range strings.Split(cleaned, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var ev goTestEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &ev) != nil || ev.Action == "" {
+			run.other = append(run.other, line)
+			continue
+		}
+
+		switch ev.Action {
+		case "build-output":
+			if _, ok := run.buildOutput[ev.ImportPath]; !ok {
+				run.buildOrder = append(run.buildOrder, ev.ImportPath)
+			}
+			run.buildOutput[ev.ImportPath] = append(run.buildOutput[ev.ImportPath], strings.TrimSuffix(ev.Output, "\n"))
+			continue
+		case "build-fail":
+			continue
+		}
+
+		p := pkg(ev.Package)
+		if ev.Action == "output" && strings.HasPrefix(ev.Output, "panic: test timed out") {
+			p.timedOut = true
+		}
+		if ev.Test == "" {
+			switch ev.Action {
+			case "output":
+				p.output = append(p.output, strings.TrimSuffix(ev.Output, "\n"))
+			case "pass", "fail", "skip":
+				p.action = ev.Action
+				p.elapsed = ev.Elapsed
+				p.failedBuild = ev.FailedBuild != ""
+			}
+			continue
+		}
+
+		key := ev.Package + " " + ev.Test
+		t, ok := testIndex[key]
+		if !ok {
+			t = &goJSONTest{pkg: ev.Package, name: ev.Test}
+			testIndex[key] = t
+			run.tests = append(run.tests, t)
+		}
+		switch ev.Action {
+		case "output":
+			t.output = append(t.output, goJSONLine{seq: seq, text: strings.TrimSuffix(ev.Output, "\n")})
+		case "pass", "fail", "skip":
+			t.action = ev.Action
+			t.elapsed = ev.Elapsed
+		}
+	}
+	return run
+}
+
+// unfinished reports whether t never reported a result because its package
+// failed while it ran: it hung until -timeout expired, or another test's
+// panic killed the test binary.
+func (r *goJSONRun) unfinished(t *goJSONTest) bool {
+	if t.action != "" {
+		return false
+	}
+	for _, p := range r.packages {
+		if p.name == t.pkg {
+			return p.action == "fail"
+		}
+	}
+	return false
+}
+
+// timedOut reports whether package name was stopped by -timeout.
+func (r *goJSONRun) timedOut(name string) bool {
+	for _, p := range r.packages {
+		if p.name == name {
+			return p.timedOut
+		}
+	}
+	return false
+}
+
+// goJSONSlowestShown is how many of the slowest tests are listed in the summary.
+const goJSONSlowestShown = 3
+
+// goJSONSkipsShown is how many skipped test names are listed in the summary.
+const goJSONSkipsShown = 5
+
+// ShortenPaths opts in to path shortening: test failures and panics name
+// source files.
+func (s *GoTestJSONStrategy) ShortenPaths() {}
+
+func (s *GoTestJSONStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	run := parseGoTestJSON(cleaned)
+
+	// Not a test2json stream — nothing to decode
+	if len(run.packages) == 0 && len(run.buildOrder) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+
+	// Build failures first: they explain why whole packages have no results
+	for _, path := range run.buildOrder {
+		out = append(out, run.buildOutput[path]...)
+	}
+
+	// Failing and unfinished tests, each top-level test rebuilt from its own
+	// events (and those of its failing subtests) so parallel output is no
+	// longer interleaved
+	var passed, failed, timedOut, skipped int
+	var skippedNames []string
+	for _, t := range run.tests {
+		if run.unfinished(t) {
+			if run.timedOut(t.pkg) {
+				timedOut++
+			} else {
+				failed++
+			}
+			if !strings.Contains(t.name, "/") {
+				out = append(out, failingTestOutput(run.tests, t)...)
+			}
+			continue
+		}
+		switch t.action {
+		case "pass":
+			passed++
+		case "skip":
+			skipped++
+			skippedNames = append(skippedNames, t.name)
+		case "fail":
+			failed++
+			if !strings.Contains(t.name, "/") {
+				out = append(out, failingTestOutput(run.tests, t)...)
+			}
+		}
+	}
+
+	// Package-level output of failing packages (panics, timeouts, TestMain output),
+	// without the summary lines that are re-emitted below
+	for _, p := range run.packages {
+		if p.action != "fail" {
+			continue
+		}
+		for _, line := range p.output {
+			if isGoTestSummaryLine(line) {
+				continue
+			}
+			out = append(out, line)
+		}
+	}
+
+	out = append(out, run.other...)
+	out, _ = condenseGoroutineDumps(out)
+	out, _ = condenseRaceReports(out)
+
+	// Package summaries in the familiar text format
+	buildFailed := 0
+	for _, p := range run.packages {
+		switch {
+		case p.action == "fail" && p.failedBuild:
+			buildFailed++
+			out = append(out, fmt.Sprintf("FAIL\t%s [build failed]", p.name))
+		case p.action == "fail":
+			out = append(out, fmt.Sprintf("FAIL\t%s\t%.3fs", p.name, p.elapsed))
+		case p.action == "skip":
+			out = append(out, fmt.Sprintf("?   \t%s\t[no test files]", p.name))
+		case p.action == "pass":
+			out = append(out, fmt.Sprintf("ok  \t%s\t%.3fs", p.name, p.elapsed))
+		}
+	}
+
+	// Totals, skips and timings
+	summary := fmt.Sprintf("%d passed, %d failed, ", passed, failed)
+	if timedOut > 0 {
+		summary += fmt.Sprintf("%d timed out, ", timedOut)
+	}
+	summary += fmt.Sprintf("%d skipped in %d packages", skipped, len(run.packages))
+	if buildFailed > 0 {
+		summary += fmt.Sprintf(" (%d failed to build)", buildFailed)
+	}
+	out = append(out, summary)
+	if len(skippedNames) > 0 {
+		shown := skippedNames
+		if len(shown) > goJSONSkipsShown {
+			shown = shown[:goJSONSkipsShown]
+		}
+		line := "skipped: " + strings.Join(shown, ", ")
+		if extra := len(skippedNames) - len(shown); extra > 0 {
+			line += fmt.Sprintf(", ... %d more", extra)
+		}
+		out = append(out, line)
+	}
+	if slowest := slowestGoTests(run.tests, goJSONSlowestShown); len(slowest) > 0 {
+		out = append(out, "slowest: "+strings.Join(slowest, ", "))
+	}
+
+	filtered := strings.Join(out, "\n") + "\n"
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// failingTestOutput returns the output of top-level test top and its failing
+// or unfinished subtests, in original stream order.
+func failingTestOutput(tests []*goJSONTest, top *goJSONTest) []string {
+	var lines []goJSONLine
+	for _, t := range tests {
+		if t.pkg != top.pkg || (t.action != "fail" && t.action != "") {
+			continue
+		}
+		if t.name == top.name || strings.HasPrefix(t.name, top.name+"/") {
+			lines = append(lines, t.output...)
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].seq < lines[j].seq })
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.text
+	}
+	return out
+}
+
+// isGoTestSummaryLine reports whether line is one of the PASS/FAIL/ok/? lines
+// that go test prints at the end of each package.
+func isGoTestSummaryLine(line string) bool {
+	return line == "PASS" || line == "FAIL" ||
+		strings.HasPrefix(line, "ok  \t") || strings.HasPrefix(line, "FAIL\t") || strings.HasPrefix(line, "?   \t")
+}
+
+// slowestGoTests returns up to n top-level tests that took at least a second,
+// slowest first, formatted as "TestName (1.23s)".
+func slowestGoTests(tests []*goJSONTest, n int) []string {
+	var top []*goJSONTest
+	for _, t := range tests {
+		if !strings.Contains(t.name, "/") && t.elapsed >= 1 {
+			top = append(top, t)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].elapsed > top[j].elapsed })
+	if len(top) > n {
+		top = top[:n]
+	}
+	var out []string
+	for _, t := range top {
+		out = append(out, fmt.Sprintf("%s (%.2fs)", t.name, t.elapsed))
+	}
+	return out
+}
+
+// ParseOutcome extracts failing and passing tests and packages from the
+// test2json stream, using the same identifiers as GoTestStrategy.
+func (s *GoTestJSONStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	cleaned := StripANSIString(string(raw))
+	run := parseGoTestJSON(cleaned)
+
+	failedPkgs := map[string]bool{}
+	for _, t := range run.tests {
+		id := t.pkg + " " + t.name
+		if run.unfinished(t) {
+			o.Failed = append(o.Failed, id)
+			failedPkgs[t.pkg] = true
+			continue
+		}
+		switch t.action {
+		case "pass":
+			o.Passed = append(o.Passed, id)
+		case "fail":
+			o.Failed = append(o.Failed, id)
+			failedPkgs[t.pkg] = true
+		}
+	}
+	for _, p := range run.packages {
+		switch p.action {
+		case "pass":
+			o.Passed = append(o.Passed, p.name)
+		case "fail":
+			if !failedPkgs[p.name] {
+				o.Failed = append(o.Failed, p.name)
+			}
+		}
+	}
+	for _, path := range run.buildOrder {
+		for _, line := range run.buildOutput[path] {
+			if goBuildErrorRe.MatchString(line) {
+				o.Errors = append(o.Errors, line)
+			}
+		}
+	}
+	// Compiler errors from toolchains that print them outside the event stream
+	for _, line := range run.other {
+		if goBuildErrorRe.MatchString(line) && !slices.Contains(o.Errors, line) {
+			o.Errors = append(o.Errors, line)
+		}
+	}
+
+	return o, len(run.packages) > 0 || len(o.Errors) > 0
+}
@@ -0,0 +1,225 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// GoTestJSONStrategy
+// ---------------------------------------------------------------------------
+
+func TestGoTestJSONStrategy_CanHandle(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"go test -json", "go", []string{"test", "-json", "./..."}, true},
+		{"go test -json=true", "go", []string{"test", "-json=true"}, true},
+		{"go test text", "go", []string{"test", "./..."}, false},
+		{"json after -args", "go", []string{"test", "-args", "-json"}, false},
+		{"go build -json", "go", []string{"build", "-json"}, false},
+		{"not go", "gotest", []string{"test", "-json"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoTestJSONStrategy_Name(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	if got := s.Name(); got != "go-test-json" {
+		t.Errorf("Name() = %q, want %q", got, "go-test-json")
+	}
+}
+
+// goTestJSONFixture is a trimmed `go test -json ./...` stream with a parallel
+// failing subtest, a skip, a pass and a package that fails to build.
+const goTestJSONFixture = `{"Action":"start","Package":"demo"}
+{"Action":"run","Package":"demo","Test":"TestA"}
+{"Action":"output","Package":"demo","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"run","Package":"demo","Test":"TestA/ok"}
+{"Action":"output","Package":"demo","Test":"TestA/ok","Output":"=== RUN   TestA/ok\n"}
+{"Action":"run","Package":"demo","Test":"TestA/bad"}
+{"Action":"output","Package":"demo","Test":"TestA/bad","Output":"=== RUN   TestA/bad\n"}
+{"Action":"run","Package":"demo","Test":"TestSlow"}
+{"Action":"output","Package":"demo","Test":"TestSlow","Output":"=== RUN   TestSlow\n"}
+{"Action":"output","Package":"demo","Test":"TestA/ok","Output":"--- PASS: TestA/ok (0.00s)\n"}
+{"Action":"pass","Package":"demo","Test":"TestA/ok","Elapsed":0}
+{"Action":"output","Package":"demo","Test":"TestSlow","Output":"    slow_test.go:3: interleaved log\n"}
+{"Action":"output","Package":"demo","Test":"TestA/bad","Output":"    a_test.go:7: nope\n"}
+{"Action":"output","Package":"demo","Test":"TestA/bad","Output":"--- FAIL: TestA/bad (0.00s)\n"}
+{"Action":"fail","Package":"demo","Test":"TestA/bad","Elapsed":0}
+{"Action":"output","Package":"demo","Test":"TestA","Output":"--- FAIL: TestA (0.00s)\n"}
+{"Action":"fail","Package":"demo","Test":"TestA","Elapsed":0}
+{"Action":"output","Package":"demo","Test":"TestSlow","Output":"--- PASS: TestSlow (2.50s)\n"}
+{"Action":"pass","Package":"demo","Test":"TestSlow","Elapsed":2.5}
+{"Action":"run","Package":"demo","Test":"TestB"}
+{"Action":"output","Package":"demo","Test":"TestB","Output":"--- SKIP: TestB (0.00s)\n"}
+{"Action":"skip","Package":"demo","Test":"TestB","Elapsed":0}
+{"Action":"output","Package":"demo","Output":"FAIL\n"}
+{"Action":"output","Package":"demo","Output":"FAIL\tdemo\t2.504s\n"}
+{"Action":"fail","Package":"demo","Elapsed":2.504}
+{"ImportPath":"demo/broken [demo/broken.test]","Action":"build-output","Output":"# demo/broken [demo/broken.test]\n"}
+{"ImportPath":"demo/broken [demo/broken.test]","Action":"build-output","Output":"broken/b.go:3:23: undefined: x\n"}
+{"ImportPath":"demo/broken [demo/broken.test]","Action":"build-fail"}
+{"Action":"start","Package":"demo/broken"}
+{"Action":"output","Package":"demo/broken","Output":"FAIL\tdemo/broken [build failed]\n"}
+{"Action":"fail","Package":"demo/broken","Elapsed":0,"FailedBuild":"demo/broken [demo/broken.test]"}
+{"Action":"start","Package":"demo/empty"}
+{"Action":"output","Package":"demo/empty","Output":"?   \tdemo/empty\t[no test files]\n"}
+{"Action":"skip","Package":"demo/empty","Elapsed":0}
+`
+
+func TestGoTestJSONStrategy_Filter(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	result := s.Filter([]byte(goTestJSONFixture), "go", []string{"test", "-json", "./..."}, 1)
+
+	want := "# demo/broken [demo/broken.test]\n" +
+		"broken/b.go:3:23: undefined: x\n" +
+		"=== RUN   TestA\n" +
+		"=== RUN   TestA/bad\n" +
+		"    a_test.go:7: nope\n" +
+		"--- FAIL: TestA/bad (0.00s)\n" +
+		"--- FAIL: TestA (0.00s)\n" +
+		"FAIL\tdemo\t2.504s\n" +
+		"FAIL\tdemo/broken [build failed]\n" +
+		"?   \tdemo/empty\t[no test files]\n" +
+		"2 passed, 2 failed, 1 skipped in 3 packages (1 failed to build)\n" +
+		"skipped: TestB\n" +
+		"slowest: TestSlow (2.50s)\n"
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestGoTestJSONStrategy_Filter_NotJSON(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	input := "flag provided but not defined: -jsn\n"
+	result := s.Filter([]byte(input), "go", []string{"test", "-json"}, 2)
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("non-JSON output should pass through, got %q", result.Filtered)
+	}
+}
+
+func TestGoTestJSONStrategy_Filter_PackagePanic(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	input := `{"Action":"start","Package":"demo"}
+{"Action":"output","Package":"demo","Output":"panic: boom in TestMain\n"}
+{"Action":"output","Package":"demo","Output":"FAIL\tdemo\t0.010s\n"}
+{"Action":"fail","Package":"demo","Elapsed":0.01}
+`
+	result := s.Filter([]byte(input), "go", []string{"test", "-json"}, 1)
+	if !strings.Contains(result.Filtered, "panic: boom in TestMain") {
+		t.Errorf("package-level output of failing package should be kept, got:\n%s", result.Filtered)
+	}
+	if strings.Count(result.Filtered, "FAIL\tdemo") != 1 {
+		t.Errorf("package summary should appear once, got:\n%s", result.Filtered)
+	}
+}
+
+// goTestJSONTimeoutFixture is a trimmed `go test -json -timeout 1s` stream:
+// the hung test never reports a result, and the timeout panic and goroutine
+// dump are attributed to it.
+const goTestJSONTimeoutFixture = `{"Action":"start","Package":"hang"}
+{"Action":"run","Package":"hang","Test":"TestOK"}
+{"Action":"output","Package":"hang","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"output","Package":"hang","Test":"TestOK","Output":"--- PASS: TestOK (0.00s)\n"}
+{"Action":"pass","Package":"hang","Test":"TestOK","Elapsed":0}
+{"Action":"run","Package":"hang","Test":"TestHang"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"=== RUN   TestHang\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"waiting for server\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"panic: test timed out after 1s\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\trunning tests:\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\t\tTestHang (1s)\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"goroutine 8 [running]:\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"testing.(*M).startAlarm.func1()\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\t/usr/local/go/src/testing/testing.go:2959 +0x34a\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"goroutine 7 [sleep]:\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"time.Sleep(0x34630b8a000)\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\t/usr/local/go/src/runtime/time.go:368 +0x165\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"hang.TestHang(0x39e192cb4488?)\n"}
+{"Action":"output","Package":"hang","Test":"TestHang","Output":"\t/tmp/hang/h_test.go:4 +0x54\n"}
+{"Action":"output","Package":"hang","Output":"FAIL\thang\t1.006s\n"}
+{"Action":"fail","Package":"hang","Elapsed":1.006}
+`
+
+func TestGoTestJSONStrategy_Filter_Timeout(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	result := s.Filter([]byte(goTestJSONTimeoutFixture), "go", []string{"test", "-json", "-timeout", "1s"}, 1)
+
+	for _, want := range []string{
+		"waiting for server",
+		"panic: test timed out after 1s",
+		"\t\tTestHang (1s)",
+		"goroutine dump: 2 goroutines, 2 distinct stacks",
+		"1 passed, 0 failed, 1 timed out, 0 skipped in 1 packages",
+	} {
+		if !strings.Contains(result.Filtered, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, result.Filtered)
+		}
+	}
+
+	o, _ := s.ParseOutcome([]byte(goTestJSONTimeoutFixture), "go", []string{"test", "-json"}, 1)
+	if got := strings.Join(o.Failed, ","); got != "hang TestHang" {
+		t.Errorf("Failed = %s, want hang TestHang", got)
+	}
+}
+
+func TestGoTestJSONStrategy_ParseOutcome(t *testing.T) {
+	s := &GoTestJSONStrategy{}
+	o, ok := s.ParseOutcome([]byte(goTestJSONFixture), "go", []string{"test", "-json"}, 1)
+	if !ok {
+		t.Fatal("expected outcome to be parsed")
+	}
+	wantFailed := "demo TestA,demo TestA/bad,demo/broken"
+	if got := strings.Join(o.Failed, ","); got != wantFailed {
+		t.Errorf("Failed = %s, want %s", got, wantFailed)
+	}
+	if !o.HasPassed("demo TestSlow") || !o.HasPassed("demo TestA/ok") {
+		t.Errorf("expected passing tests in Passed, got %v", o.Passed)
+	}
+	if len(o.Errors) != 1 || o.Errors[0] != "broken/b.go:3:23: undefined: x" {
+		t.Errorf("Errors = %v", o.Errors)
+	}
+}
+
+func TestStructuredArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    []string
+	}{
+		{"go test", "go", []string{"test", "./..."}, []string{"test", "-json", "./..."}},
+		{"go test with -C", "go", []string{"-C", "dir", "test"}, []string{"-C", "dir", "test", "-json"}},
+		{"already json", "go", []string{"test", "-json"}, []string{"test", "-json"}},
+		{"go build untouched", "go", []string{"build"}, []string{"build"}},
+		{"other command untouched", "git", []string{"status"}, []string{"status"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := StructuredArgs(tc.command, tc.args)
+			if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("StructuredArgs(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package filter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ---------------------------------------------------------------------------
+// Go panic / goroutine dump condenser
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for goroutine dumps.
+var (
+	// goroutineHeaderRe matches "goroutine 7 [chan receive, 2 minutes]:".
+	goroutineHeaderRe = regexp.MustCompile(`^goroutine (\d+) \[([^\]]*)\]:$`)
+	// goroutineFileRe matches a frame's "\t/path/file.go:123 +0x1d" line.
+	goroutineFileRe = regexp.MustCompile(`^\t(\S+:\d+)(?: \+0x[0-9a-f]+)?$`)
+	// goroutineWaitRe matches the wait duration in a goroutine status.
+	goroutineWaitRe = regexp.MustCompile(`, \d+ minutes?`)
+	// goroutineCreatedInRe matches the " in goroutine N" suffix of "created by" lines.
+	goroutineCreatedInRe = regexp.MustCompile(` in goroutine \d+$`)
+)
+
+const (
+	// goroutineIDsShown caps the goroutine ids listed for a group.
+	goroutineIDsShown = 5
+	// goroutineElided is printed by the runtime for very deep stacks.
+	goroutineElided = "...additional frames elided..."
+)
+
+// goFrame is one call in a goroutine stack.
+type goFrame struct {
+	fn       string // function name without arguments, "created by " kept
+	location string // "file.go:123"
+}
+
+// goroutine is one goroutine of a dump.
+type goroutine struct {
+	id     string
+	status string // wait duration removed
+	frames []goFrame
+}
+
+// key identifies goroutines with identical stacks, as panicparse buckets them.
+func (g *goroutine) key() string {
+	var b strings.Builder
+	b.WriteString(g.status)
+	for _, f := range g.frames {
+		b.WriteString("\n" + f.fn + " " + f.location)
+	}
+	return b.String()
+}
+
+var (
+	goModuleOnce sync.Once
+	goModuleName string
+)
+
+// goModulePath returns the module path from the nearest go.mod above the
+// working directory, or "" outside a module. It is looked up once per process.
+func goModulePath() string {
+	goModuleOnce.Do(func() {
+		dir, err := os.Getwd()
+		if err != nil {
+			return
+		}
+		for {
+			if f, err := os.Open(filepath.Join(dir, "go.mod")); err == nil {
+				sc := bufio.NewScanner(f)
+				for sc.Scan() {
+					if after, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+						goModuleName = strings.Trim(strings.TrimSpace(after), `"`)
+						break
+					}
+				}
+				f.Close()
+				return
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return
+			}
+			dir = parent
+		}
+	})
+	return goModuleName
+}
+
+// isGoStdFrame reports whether fn belongs to the runtime or standard library,
+// whose import paths have no dot in their first element.
+func isGoStdFrame(fn string) bool {
+	fn = strings.TrimPrefix(fn, "created by ")
+	if first, _, ok := strings.Cut(fn, "/"); ok {
+		return !strings.Contains(first, ".")
+	}
+	// Single-element path: "runtime.gopark", "testing.tRunner"
+	return true
+}
+
+// isGoUserFrame reports whether fn is in module (or package main).
+func isGoUserFrame(fn, module string) bool {
+	fn = strings.TrimPrefix(fn, "created by ")
+	if strings.HasPrefix(fn, "main.") {
+		return true
+	}
+	return module != "" && (strings.HasPrefix(fn, module+".") || strings.HasPrefix(fn, module+"/"))
+}
+
+// goFuncName strips the argument list from a stack frame's function line.
+func goFuncName(line string) string {
+	line = goroutineCreatedInRe.ReplaceAllString(line, "")
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseGoroutine parses the goroutine block starting at lines[start] (its
+// header). It returns the goroutine and the index just past the block.
+func parseGoroutine(lines []string, start int) (*goroutine, int) {
+	m := goroutineHeaderRe.FindStringSubmatch(lines[start])
+	g := &goroutine{id: m[1], status: goroutineWaitRe.ReplaceAllString(m[2], "")}
+	i := start + 1
+	for i < len(lines) {
+		line := lines[i]
+		if line == goroutineElided {
+			g.frames = append(g.frames, goFrame{fn: goroutineElided})
+			i++
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "\t") || goroutineHeaderRe.MatchString(line) {
+			break
+		}
+		if i+1 >= len(lines) {
+			break
+		}
+		fm := goroutineFileRe.FindStringSubmatch(lines[i+1])
+		if fm == nil {
+			break
+		}
+		g.frames = append(g.frames, goFrame{fn: goFuncName(line), location: fm[1]})
+		i += 2
+	}
+	return g, i
+}
+
+// condenseGoroutineDumps replaces each goroutine dump in lines (as printed on
+// panics, `go test -timeout` expiry and SIGQUIT) with a condensed form:
+// goroutines with identical stacks are grouped, runtime and standard library
+// frames are hidden, frames in the current module are marked with ">", and the
+// first goroutine (the one that panicked) is shown first. It reports whether
+// any dump was found.
+func condenseGoroutineDumps(lines []string) ([]string, bool) {
+	var out []string
+	found := false
+	for i := 0; i < len(lines); {
+		if !goroutineHeaderRe.MatchString(lines[i]) {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+
+		// Collect consecutive goroutine blocks, which are separated by blank lines
+		var dump []*goroutine
+		for i < len(lines) && goroutineHeaderRe.MatchString(lines[i]) {
+			g, next := parseGoroutine(lines, i)
+			dump = append(dump, g)
+			i = next
+			j := i
+			for j < len(lines) && lines[j] == "" {
+				j++
+			}
+			if j < len(lines) && goroutineHeaderRe.MatchString(lines[j]) {
+				i = j
+			}
+		}
+		found = true
+		out = append(out, renderGoroutineDump(dump, goModulePath())...)
+	}
+	return out, found
+}
+
+// renderGoroutineDump renders a parsed dump: the first goroutine on its own,
+// then the others grouped by identical stack, largest group first.
+func renderGoroutineDump(dump []*goroutine, module string) []string {
+	type group struct {
+		first *goroutine
+		ids   []string
+	}
+	var groups []*group
+	index := map[string]*group{}
+	for _, g := range dump[1:] {
+		k := g.key()
+		gr, ok := index[k]
+		if !ok {
+			gr = &group{first: g}
+			index[k] = gr
+			groups = append(groups, gr)
+		}
+		gr.ids = append(gr.ids, g.id)
+	}
+	// Largest groups first: they are the likeliest culprits in deadlocks
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i].ids) > len(groups[j].ids) })
+
+	// The runtime prints the panicking (or timed-out) goroutine first
+	first := dump[0]
+	header := fmt.Sprintf("goroutine %s [%s]:", first.id, first.status)
+	if strings.HasPrefix(first.status, "running") {
+		header = fmt.Sprintf("goroutine %s [%s] (panicking):", first.id, first.status)
+	}
+	out := []string{header}
+	out = append(out, renderGoFrames(first.frames, module)...)
+	for _, gr := range groups {
+		ids := gr.ids
+		suffix := ""
+		if len(ids) > goroutineIDsShown {
+			suffix = fmt.Sprintf(", ... %d more", len(ids)-goroutineIDsShown)
+			ids = ids[:goroutineIDsShown]
+		}
+		if len(gr.ids) == 1 {
+			out = append(out, fmt.Sprintf("goroutine %s [%s]:", gr.ids[0], gr.first.status))
+		} else {
+			out = append(out, fmt.Sprintf("%d goroutines [%s]: %s%s", len(gr.ids), gr.first.status, strings.Join(ids, ", "), suffix))
+		}
+		out = append(out, renderGoFrames(gr.first.frames, module)...)
+	}
+	out = append(out, fmt.Sprintf("goroutine dump: %d goroutines, %d distinct stacks", len(dump), len(groups)+1))
+	return out
+}
+
+// renderGoFrames renders the non-standard-library frames of a stack, one per
+// line. If every frame is in the standard library, the innermost is kept so
+// the goroutine stays identifiable.
+func renderGoFrames(frames []goFrame, module string) []string {
+	var out []string
+	hidden := 0
+	for _, f := range frames {
+		if f.fn == goroutineElided {
+			out = append(out, "  "+goroutineElided)
+			continue
+		}
+		marker := "  "
+		switch {
+		case isGoUserFrame(f.fn, module):
+			marker = "> "
+		case isGoStdFrame(f.fn):
+			hidden++
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s%s  %s", marker, f.fn, f.location))
+	}
+	if len(out) == 0 && len(frames) > 0 {
+		out = append(out, fmt.Sprintf("  %s  %s", frames[0].fn, frames[0].location))
+		hidden--
+	}
+	if hidden > 0 {
+		out = append(out, fmt.Sprintf("  (%d runtime/stdlib frames hidden)", hidden))
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Goroutine dump condenser
+// ---------------------------------------------------------------------------
+
+// goroutineDumpFixture is a test panic followed by a dump with six identical
+// worker goroutines and the testing main goroutine.
+func goroutineDumpFixture() string {
+	var b strings.Builder
+	b.WriteString("panic: runtime error: index out of range [5] with length 3\n\n")
+	b.WriteString("goroutine 7 [running]:\n" +
+		"testing.tRunner.func1.2({0x5a5d20, 0xc000016138})\n" +
+		"\t/usr/local/go/src/testing/testing.go:1632 +0x230\n" +
+		"panic({0x5a5d20?, 0xc000016138?})\n" +
+		"\t/usr/local/go/src/runtime/panic.go:785 +0x132\n" +
+		"example.com/demo/store.(*Cache).Get(...)\n" +
+		"\t/work/demo/store/cache.go:42\n" +
+		"example.com/demo/store.TestGet(0xc0000a0b60)\n" +
+		"\t/work/demo/store/cache_test.go:17 +0x1d\n" +
+		"testing.tRunner(0xc0000a0b60, 0x5d2f38)\n" +
+		"\t/usr/local/go/src/testing/testing.go:1690 +0xf4\n" +
+		"created by testing.(*T).Run in goroutine 1\n" +
+		"\t/usr/local/go/src/testing/testing.go:1743 +0x390\n\n")
+	for _, id := range []int{20, 21, 22, 23, 24, 25} {
+		fmt.Fprintf(&b, "goroutine %d [chan receive, 2 minutes]:\n"+
+			"example.com/demo/store.(*Cache).worker(0xc0000b4000)\n"+
+			"\t/work/demo/store/cache.go:88 +0x45\n"+
+			"github.com/acme/pool.(*Pool).run(0xc0000b6000)\n"+
+			"\t/root/go/pkg/mod/github.com/acme/pool@v1.2.0/pool.go:31 +0x2a\n"+
+			"created by github.com/acme/pool.New in goroutine 7\n"+
+			"\t/root/go/pkg/mod/github.com/acme/pool@v1.2.0/pool.go:12 +0x7c\n\n", id)
+	}
+	b.WriteString("goroutine 1 [chan receive]:\n" +
+		"testing.(*T).Run(0xc0000a09c0, {0x5b1d0e?, 0x0?}, 0x5d2f38)\n" +
+		"\t/usr/local/go/src/testing/testing.go:1751 +0x3ab\n" +
+		"testing.runTests.func1(0xc0000a09c0)\n" +
+		"\t/usr/local/go/src/testing/testing.go:2168 +0x37\n" +
+		"main.main()\n" +
+		"\t_testmain.go:45 +0x9b\n")
+	b.WriteString("exit status 2\n")
+	return b.String()
+}
+
+func TestCondenseGoroutineDumps(t *testing.T) {
+	lines := strings.Split(goroutineDumpFixture(), "\n")
+
+	out, found := condenseGoroutineDumps(lines)
+	if !found {
+		t.Fatal("expected a goroutine dump to be found")
+	}
+	text := strings.Join(out, "\n")
+
+	// Module lookup depends on the working directory; check module-independent parts here
+	for _, want := range []string{
+		"panic: runtime error: index out of range [5] with length 3\n\ngoroutine 7 [running] (panicking):\n",
+		"example.com/demo/store.(*Cache).Get  /work/demo/store/cache.go:42\n",
+		"(4 runtime/stdlib frames hidden)",
+		"6 goroutines [chan receive]: 20, 21, 22, 23, 24, ... 1 more\n",
+		"goroutine 1 [chan receive]:\n> main.main  _testmain.go:45\n  (2 runtime/stdlib frames hidden)\n",
+		"goroutine dump: 8 goroutines, 3 distinct stacks\nexit status 2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("condensed dump missing %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "testing.tRunner") || strings.Contains(text, "+0x") {
+		t.Errorf("stdlib frames and offsets should be hidden, got:\n%s", text)
+	}
+}
+
+func TestRenderGoroutineDump_MarksModuleFrames(t *testing.T) {
+	lines := strings.Split(goroutineDumpFixture(), "\n")
+	var dump []*goroutine
+	for i := 0; i < len(lines); i++ {
+		if goroutineHeaderRe.MatchString(lines[i]) {
+			g, next := parseGoroutine(lines, i)
+			dump = append(dump, g)
+			i = next - 1
+		}
+	}
+
+	out := strings.Join(renderGoroutineDump(dump, "example.com/demo"), "\n")
+
+	want := "goroutine 7 [running] (panicking):\n" +
+		"> example.com/demo/store.(*Cache).Get  /work/demo/store/cache.go:42\n" +
+		"> example.com/demo/store.TestGet  /work/demo/store/cache_test.go:17\n" +
+		"  (4 runtime/stdlib frames hidden)\n" +
+		"6 goroutines [chan receive]: 20, 21, 22, 23, 24, ... 1 more\n" +
+		"> example.com/demo/store.(*Cache).worker  /work/demo/store/cache.go:88\n" +
+		"  github.com/acme/pool.(*Pool).run  /root/go/pkg/mod/github.com/acme/pool@v1.2.0/pool.go:31\n" +
+		"  created by github.com/acme/pool.New  /root/go/pkg/mod/github.com/acme/pool@v1.2.0/pool.go:12\n" +
+		"goroutine 1 [chan receive]:\n" +
+		"> main.main  _testmain.go:45\n" +
+		"  (2 runtime/stdlib frames hidden)\n" +
+		"goroutine dump: 8 goroutines, 3 distinct stacks"
+
+	if out != want {
+		t.Errorf("render mismatch.\ngot:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestCondenseGoroutineDumps_NoDump(t *testing.T) {
+	lines := []string{"ok  \texample.com/demo\t0.01s", ""}
+	out, found := condenseGoroutineDumps(lines)
+	if found {
+		t.Error("expected no dump")
+	}
+	if strings.Join(out, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("lines should be unchanged, got %q", out)
+	}
+}
+
+func TestGoTestStrategy_Filter_CondensesPanic(t *testing.T) {
+	s := &GoTestStrategy{}
+	input := "=== RUN   TestGet\n--- FAIL: TestGet (0.00s)\n" + goroutineDumpFixture() +
+		"FAIL\texample.com/demo/store\t0.012s\nFAIL\n"
+
+	result := s.Filter([]byte(input), "go", []string{"test", "./..."}, 1)
+
+	if !strings.Contains(result.Filtered, "6 goroutines [chan receive]") {
+		t.Errorf("expected grouped goroutines, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "panic: runtime error: index out of range") {
+		t.Errorf("panic message should be kept, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "FAIL\texample.com/demo/store\t0.012s") {
+		t.Errorf("package summary should be kept, got:\n%s", result.Filtered)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestGenericErrorStrategy_Filter_CondensesPanic(t *testing.T) {
+	s := &GenericErrorStrategy{}
+	input := "starting server\n" + goroutineDumpFixture()
+
+	result := s.Filter([]byte(input), "./server", nil, 2)
+
+	if !strings.HasPrefix(result.Filtered, "starting server\npanic: runtime error") {
+		t.Errorf("output around the dump should be kept, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "goroutine dump: 8 goroutines, 3 distinct stacks") {
+		t.Errorf("expected condensed dump, got:\n%s", result.Filtered)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
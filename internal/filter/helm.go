@@ -0,0 +1,258 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// helmValueFlags are helm global flags that consume the next argument as a value.
+var helmValueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "--kube-context": true, "--kubeconfig": true,
+	"--kube-apiserver": true, "--kube-token": true, "--kube-as-user": true,
+	"--registry-config": true, "--repository-cache": true, "--repository-config": true,
+	"--burst-limit": true,
+}
+
+// ---------------------------------------------------------------------------
+// Rendered manifest inventory
+// ---------------------------------------------------------------------------
+
+// manifestResource identifies one document of a rendered YAML stream.
+type manifestResource struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+// yamlScalar returns the plain value of a "key: value" line's value part.
+func yamlScalar(v string) string {
+	return strings.Trim(strings.TrimSpace(v), `"'`)
+}
+
+// parseManifests reads the kind, metadata.name and metadata.namespace of each
+// document in a "---"-separated YAML stream. Documents without a kind (empty
+// templates) are skipped.
+func parseManifests(lines []string) []manifestResource {
+	var res []manifestResource
+	var cur manifestResource
+	inMeta := false
+	flush := func() {
+		if cur.kind != "" {
+			res = append(res, cur)
+		}
+		cur, inMeta = manifestResource{}, false
+	}
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "---"):
+			flush()
+		case line == "" || strings.HasPrefix(line, "#"):
+		case !strings.HasPrefix(line, " "):
+			key, val, _ := strings.Cut(line, ":")
+			inMeta = key == "metadata"
+			if key == "kind" {
+				cur.kind = yamlScalar(val)
+			}
+		case inMeta && !strings.HasPrefix(line, "   "):
+			// Direct children of metadata; labels and annotations are deeper
+			key, val, _ := strings.Cut(strings.TrimSpace(line), ":")
+			switch key {
+			case "name":
+				cur.name = yamlScalar(val)
+			case "namespace":
+				cur.namespace = yamlScalar(val)
+			}
+		}
+	}
+	flush()
+	return res
+}
+
+// nonBlankLines counts the lines of lines that are not blank.
+func nonBlankLines(lines []string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// renderManifestInventory lists resources grouped by kind, in order of first
+// appearance: "  Deployment (2): prod/api, prod/worker".
+func renderManifestInventory(res []manifestResource, yamlLines int) []string {
+	var kinds []string
+	names := map[string][]string{}
+	for _, r := range res {
+		if _, ok := names[r.kind]; !ok {
+			kinds = append(kinds, r.kind)
+		}
+		name := r.name
+		if r.namespace != "" {
+			name = r.namespace + "/" + name
+		}
+		names[r.kind] = append(names[r.kind], name)
+	}
+
+	out := []string{fmt.Sprintf("Rendered %d resources (%d lines of YAML):", len(res), yamlLines)}
+	for _, k := range kinds {
+		out = append(out, fmt.Sprintf("  %s (%d): %s", k, len(names[k]), strings.Join(names[k], ", ")))
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// HelmStrategy
+// ---------------------------------------------------------------------------
+
+// HelmStrategy filters `helm template`, `helm install` and `helm upgrade`
+// (including --dry-run) and `helm lint`. Rendered manifests become an
+// inventory of resources by kind, release status output keeps the release
+// fields without the chart's NOTES, and lint output keeps errors and warnings.
+type HelmStrategy struct{}
+
+func (s *HelmStrategy) Name() string { return "helm" }
+
+func (s *HelmStrategy) CanHandle(command string, args []string) bool {
+	if filepath.Base(command) != "helm" {
+		return false
+	}
+	for _, sub := range []string{"template", "install", "upgrade", "lint"} {
+		if isSubcommand(args, sub, helmValueFlags) {
+			return true
+		}
+	}
+	return false
+}
+
+// Package-level compiled regexes for HelmStrategy.
+var (
+	// helmSectionRe matches the section headings of release output.
+	helmSectionRe = regexp.MustCompile(`^(HOOKS|MANIFEST|NOTES|USER-SUPPLIED VALUES|COMPUTED VALUES):$`)
+	// helmLintKeepRe matches the lint lines worth keeping.
+	helmLintKeepRe = regexp.MustCompile(`^(==> |\[ERROR\]|\[WARNING\]|Error: |\d+ chart\(s\) linted)`)
+)
+
+func (s *HelmStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	if isSubcommand(args, "lint", helmValueFlags) {
+		for _, line := range lines {
+			if helmLintKeepRe.MatchString(line) {
+				out = append(out, line)
+			}
+		}
+	} else {
+		out = filterHelmRelease(lines)
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(out) == 0 || len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// filterHelmRelease keeps the release fields (NAME, NAMESPACE, STATUS, ...)
+// and errors, replaces the rendered manifests (`helm template`, or HOOKS and
+// MANIFEST with --dry-run) by their inventory and counts the NOTES lines.
+func filterHelmRelease(lines []string) []string {
+	var out, manifest []string
+	section := ""
+	notes := 0
+	for _, line := range lines {
+		if m := helmSectionRe.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "Error: "):
+			out = append(out, line)
+		case section == "HOOKS" || section == "MANIFEST",
+			section == "" && (strings.HasPrefix(line, "---") || len(manifest) > 0):
+			// `helm template` prints the stream without a MANIFEST heading
+			manifest = append(manifest, line)
+		case section == "NOTES":
+			if strings.TrimSpace(line) != "" {
+				notes++
+			}
+		case section == "" && strings.TrimSpace(line) != "":
+			out = append(out, line)
+		}
+	}
+
+	if res := parseManifests(manifest); len(res) > 0 {
+		out = append(out, renderManifestInventory(res, nonBlankLines(manifest))...)
+	}
+	if notes > 0 {
+		out = append(out, fmt.Sprintf("NOTES: %d lines omitted", notes))
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// KustomizeStrategy
+// ---------------------------------------------------------------------------
+
+// KustomizeStrategy turns `kustomize build` output into an inventory of the
+// rendered resources by kind.
+type KustomizeStrategy struct{}
+
+func (s *KustomizeStrategy) Name() string { return "kustomize" }
+
+func (s *KustomizeStrategy) CanHandle(command string, args []string) bool {
+	return filepath.Base(command) == "kustomize" && isSubcommand(args, "build", nil)
+}
+
+func (s *KustomizeStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	res := parseManifests(lines)
+	if len(res) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	filtered := strings.Join(renderManifestInventory(res, nonBlankLines(lines)), "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
@@ -0,0 +1,192 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelmStrategies_CanHandle(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   string
+		args      []string
+		helm      bool
+		kustomize bool
+	}{
+		{"template", "helm", []string{"template", "web", "./chart"}, true, false},
+		{"install dry-run", "helm", []string{"install", "web", "./chart", "--dry-run"}, true, false},
+		{"upgrade with namespace first", "helm", []string{"-n", "lint", "upgrade", "--install", "web", "./chart"}, true, false},
+		{"lint", "helm", []string{"lint", "./chart"}, true, false},
+		{"repo update", "helm", []string{"repo", "update"}, false, false},
+		{"list", "helm", []string{"list", "-A"}, false, false},
+		{"kustomize build", "kustomize", []string{"build", "overlays/prod"}, false, true},
+		{"kustomize edit", "kustomize", []string{"edit", "set", "image", "api=api:1.2"}, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (&HelmStrategy{}).CanHandle(tc.command, tc.args); got != tc.helm {
+				t.Errorf("helm CanHandle = %v, want %v", got, tc.helm)
+			}
+			if got := (&KustomizeStrategy{}).CanHandle(tc.command, tc.args); got != tc.kustomize {
+				t.Errorf("kustomize CanHandle = %v, want %v", got, tc.kustomize)
+			}
+		})
+	}
+}
+
+const helmTemplateOutput = `---
+# Source: web/templates/serviceaccount.yaml
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+    name: not-this-one
+---
+# Source: web/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "web-config"
+  namespace: prod
+data:
+  name: also-not-this-one
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  ports:
+    - port: 80
+---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  replicas: 2
+---
+# Source: web/templates/worker.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  namespace: prod
+  name: worker
+---
+# Source: web/templates/hpa.yaml
+`
+
+func TestHelmStrategy_Filter_Template(t *testing.T) {
+	s := &HelmStrategy{}
+	result := s.Filter([]byte(helmTemplateOutput), "helm", []string{"template", "web", "./chart"}, 0)
+
+	want := `Rendered 5 resources (46 lines of YAML):
+  ServiceAccount (1): web
+  ConfigMap (1): prod/web-config
+  Service (1): prod/web
+  Deployment (2): prod/web, prod/worker
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestHelmStrategy_Filter_DryRunInstall(t *testing.T) {
+	s := &HelmStrategy{}
+	input := `NAME: web
+LAST DEPLOYED: Thu Feb 12 14:30:22 2026
+NAMESPACE: prod
+STATUS: pending-install
+REVISION: 1
+HOOKS:
+---
+# Source: web/templates/tests/test-connection.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: "web-test-connection"
+  annotations:
+    "helm.sh/hook": test
+MANIFEST:
+` + helmTemplateOutput + `
+NOTES:
+1. Get the application URL by running these commands:
+  export POD_NAME=$(kubectl get pods --namespace prod -l "app=web" -o jsonpath="{.items[0].metadata.name}")
+  kubectl --namespace prod port-forward $POD_NAME 8080:80
+`
+	result := s.Filter([]byte(input), "helm", []string{"install", "web", "./chart", "--dry-run"}, 0)
+
+	want := `NAME: web
+LAST DEPLOYED: Thu Feb 12 14:30:22 2026
+NAMESPACE: prod
+STATUS: pending-install
+REVISION: 1
+Rendered 6 resources (54 lines of YAML):
+  Pod (1): web-test-connection
+  ServiceAccount (1): web
+  ConfigMap (1): prod/web-config
+  Service (1): prod/web
+  Deployment (2): prod/web, prod/worker
+NOTES: 3 lines omitted
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestHelmStrategy_Filter_Lint(t *testing.T) {
+	s := &HelmStrategy{}
+	input := `==> Linting ./charts/web
+[INFO] Chart.yaml: icon is recommended
+[INFO] values.yaml: file does not exist
+[WARNING] templates/ingress.yaml: networking.k8s.io/v1beta1 Ingress is deprecated
+[ERROR] templates/: template: web/templates/service.yaml:8:18: executing "web/templates/service.yaml" at <.Values.service.port>: nil pointer evaluating interface {}.port
+
+==> Linting ./charts/worker
+[INFO] Chart.yaml: icon is recommended
+
+Error: 2 chart(s) linted, 1 chart(s) failed
+`
+	result := s.Filter([]byte(input), "helm", []string{"lint", "./charts/web", "./charts/worker"}, 1)
+
+	want := `==> Linting ./charts/web
+[WARNING] templates/ingress.yaml: networking.k8s.io/v1beta1 Ingress is deprecated
+[ERROR] templates/: template: web/templates/service.yaml:8:18: executing "web/templates/service.yaml" at <.Values.service.port>: nil pointer evaluating interface {}.port
+==> Linting ./charts/worker
+Error: 2 chart(s) linted, 1 chart(s) failed
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestKustomizeStrategy_Filter(t *testing.T) {
+	s := &KustomizeStrategy{}
+	input := strings.ReplaceAll(helmTemplateOutput, "# Source: ", "# ")
+	result := s.Filter([]byte(input), "kustomize", []string{"build", "overlays/prod"}, 0)
+
+	if !strings.HasPrefix(result.Filtered, "Rendered 5 resources (46 lines of YAML):\n") {
+		t.Errorf("expected inventory, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "  Deployment (2): prod/web, prod/worker\n") {
+		t.Errorf("expected deployments grouped, got:\n%s", result.Filtered)
+	}
+}
+
+func TestKustomizeStrategy_Filter_NotYAML(t *testing.T) {
+	s := &KustomizeStrategy{}
+	input := strings.Repeat("Error: accumulating resources: missing kustomization.yaml\n", 10)
+	result := s.Filter([]byte(input), "kustomize", []string{"build", "."}, 1)
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("non-manifest output should pass through, got:\n%s", result.Filtered)
+	}
+}
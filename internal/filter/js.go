@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// jsValueFlags maps JavaScript package managers and runners to flags that
+// consume a following value argument.
+var jsValueFlags = map[string]map[string]bool{
+	"npm":  {"--prefix": true, "--registry": true, "--cache": true, "-w": true, "--workspace": true},
+	"npx":  {"-p": true, "--package": true, "-c": true, "--call": true},
+	"yarn": {"--cwd": true, "--modules-folder": true, "--cache-folder": true},
+	"pnpm": {"-C": true, "--dir": true, "-F": true, "--filter": true},
+	"bun":  {"--cwd": true, "-c": true, "--config": true},
+	"bunx": {"-p": true, "--package": true},
+}
+
+// jsPositional returns the index of the first positional argument in args,
+// skipping flags and their values. A "--" ends flag parsing. It returns -1
+// when there is none.
+func jsPositional(args []string, valueFlags map[string]bool) int {
+	skip := false
+	for i, a := range args {
+		if skip {
+			skip = false
+			continue
+		}
+		if a == "--" {
+			if i+1 < len(args) {
+				return i + 1
+			}
+			return -1
+		}
+		if valueFlags[a] {
+			skip = true
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// jsTool resolves the tool or package script a JavaScript command runs, and
+// the arguments passed to it. Runners and package managers are seen through:
+// `npx jest`, `pnpm exec tsc`, `yarn dlx eslint`, `bun x vitest` and a plain
+// `node_modules/.bin/jest` all resolve to the tool itself, while `npm test`,
+// `npm run lint`, `yarn test` and `pnpm test` resolve to the script name.
+// Any other command resolves to its own base name.
+func jsTool(command string, args []string) (string, []string) {
+	name := filepath.Base(command)
+	vf := jsValueFlags[name]
+	switch name {
+	case "npx", "bunx":
+		return jsToolAt(args, jsPositional(args, vf))
+	case "npm", "yarn", "pnpm", "bun":
+		i := jsPositional(args, vf)
+		if i < 0 {
+			return "", nil
+		}
+		switch args[i] {
+		case "t", "tst":
+			if name == "npm" {
+				return "test", args[i+1:]
+			}
+		case "run", "run-script", "exec", "x", "dlx":
+			rest := args[i+1:]
+			return jsToolAt(rest, jsPositional(rest, nil))
+		}
+		return jsToolAt(args, i)
+	}
+	return name, args
+}
+
+// jsToolAt returns args[i] (by base name) and the arguments after it.
+func jsToolAt(args []string, i int) (string, []string) {
+	if i < 0 {
+		return "", nil
+	}
+	return filepath.Base(args[i]), args[i+1:]
+}
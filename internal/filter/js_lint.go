@@ -0,0 +1,357 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// JSLintStrategy
+// ---------------------------------------------------------------------------
+
+// JSLintStrategy groups JavaScript/TypeScript linter findings: eslint (stylish
+// and unix formats), `biome check`/`biome lint`/`biome ci` and
+// `prettier --check`. Findings are grouped by rule and then by file, errors
+// before warnings, and each rule shows a limited number of entries.
+// Prettier's list of unformatted files is folded into a count plus the first
+// few files.
+type JSLintStrategy struct{}
+
+func (s *JSLintStrategy) Name() string { return "js-lint" }
+
+func (s *JSLintStrategy) CanHandle(command string, args []string) bool {
+	tool, rest := jsTool(command, args)
+	switch tool {
+	case "eslint":
+		return true
+	case "biome":
+		i := jsPositional(rest, nil)
+		return i >= 0 && (rest[i] == "check" || rest[i] == "lint" || rest[i] == "ci")
+	case "prettier":
+		// Without --check/--list-different prettier prints formatted code
+		for _, a := range rest {
+			if a == "--check" || a == "-c" || a == "--list-different" || a == "-l" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Package-level compiled regexes for JSLintStrategy.
+var (
+	// eslintStylishRe matches a stylish finding: "  1:10  error  'x' is unused  no-unused-vars".
+	eslintStylishRe = regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.*?)(?:\s{2,}(\S+))?$`)
+	// eslintUnixRe matches a unix finding: "src/a.js:1:10: 'x' is unused [Error/no-unused-vars]".
+	eslintUnixRe = regexp.MustCompile(`^(\S.*?):(\d+):(\d+): (.*) \[(Error|Warning)(?:/(\S+))?\]$`)
+	// biomeHeaderRe matches a diagnostic header: "src/a.ts:3:7 lint/style/useConst  FIXABLE  ━━━━".
+	biomeHeaderRe = regexp.MustCompile(`^(\S+?)(?::(\d+):(\d+))? (\S+)(?:\s+FIXABLE)?\s+━{3,}$`)
+	// biomeMessageRe matches a diagnostic's severity marker and message.
+	biomeMessageRe = regexp.MustCompile(`^\s+([✖×⚠!ℹi]) (.+)$`)
+	// biomeRuleRe matches the decoration line of biome's closing summary block.
+	biomeRuleRe = regexp.MustCompile(`^\S+ ━{3,}$`)
+	// prettierLineRe matches prettier's "[warn] file" and "[error] msg" lines.
+	prettierLineRe = regexp.MustCompile(`^\[(warn|error)\] (.+)$`)
+	// jsLintSummaryRe matches the tools' own summary lines, kept at the end.
+	jsLintSummaryRe = regexp.MustCompile(`^✖ \d+ problems?|^\s*\d+ errors? and \d+ warnings? potentially fixable|^\d+ problems?$|` +
+		`^Checked \d+ files?|^Found \d+ (errors?|warnings?|infos?)|^(Fixed|Skipped) \d+ files?|` +
+		`Code style issues found|^All matched files use Prettier|Some errors were emitted`)
+)
+
+const (
+	// jsLintMaxPerRule caps the entries shown per rule.
+	jsLintMaxPerRule = 8
+	// jsLintMaxLocations caps the positions listed for one merged entry.
+	jsLintMaxLocations = 6
+	// prettierShownFiles caps the unformatted files listed by name.
+	prettierShownFiles = 5
+)
+
+// jsLintDiag is one linter finding.
+type jsLintDiag struct {
+	file     string
+	line     int
+	col      int
+	severity string // "error", "warning" or "info" (biome)
+	message  string
+	rule     string
+}
+
+// parseJSLint parses eslint, biome and prettier output. It returns findings,
+// files prettier reports as unformatted, the tools' summary lines and any
+// other lines worth keeping.
+func parseJSLint(lines []string, listDifferent bool) (diags []jsLintDiag, unformatted, summary, other []string) {
+	file := ""        // current eslint stylish file
+	fileUsed := false // whether file has findings
+	biome := -1       // index of the current biome diagnostic
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", trimmed == "Checking formatting...":
+			continue
+		case jsLintSummaryRe.MatchString(line):
+			summary = append(summary, strings.TrimPrefix(trimmed, "[warn] "))
+			biome = -1
+			continue
+		}
+
+		if m := eslintStylishRe.FindStringSubmatch(line); m != nil && file != "" && biome < 0 {
+			d := jsLintDiag{file: file, severity: m[3], message: m[4], rule: m[5]}
+			d.line, _ = strconv.Atoi(m[1])
+			d.col, _ = strconv.Atoi(m[2])
+			diags = append(diags, d)
+			fileUsed = true
+			continue
+		}
+		if m := eslintUnixRe.FindStringSubmatch(line); m != nil {
+			d := jsLintDiag{file: m[1], severity: strings.ToLower(m[5]), message: m[4], rule: m[6]}
+			d.line, _ = strconv.Atoi(m[2])
+			d.col, _ = strconv.Atoi(m[3])
+			diags = append(diags, d)
+			continue
+		}
+		if m := biomeHeaderRe.FindStringSubmatch(line); m != nil {
+			d := jsLintDiag{file: m[1], rule: m[4], severity: "error"}
+			d.line, _ = strconv.Atoi(m[2])
+			d.col, _ = strconv.Atoi(m[3])
+			biome = len(diags)
+			diags = append(diags, d)
+			continue
+		}
+		if biome >= 0 {
+			// The first marker line is the message; notes, code frames and
+			// formatter diffs that follow stay in the log
+			if m := biomeMessageRe.FindStringSubmatch(line); m != nil && diags[biome].message == "" {
+				diags[biome].message = m[2]
+				switch m[1] {
+				case "⚠", "!":
+					diags[biome].severity = "warning"
+				case "ℹ", "i":
+					diags[biome].severity = "info"
+				}
+			}
+			continue
+		}
+		if biomeRuleRe.MatchString(line) {
+			continue
+		}
+		if m := prettierLineRe.FindStringSubmatch(line); m != nil {
+			if m[1] == "warn" {
+				unformatted = append(unformatted, m[2])
+			} else {
+				other = append(other, line)
+			}
+			continue
+		}
+		if listDifferent && !strings.HasPrefix(line, " ") {
+			unformatted = append(unformatted, trimmed)
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			// A new eslint stylish file header; a previous one without
+			// findings was something else
+			if file != "" && !fileUsed {
+				other = append(other, file)
+			}
+			file, fileUsed = trimmed, false
+			continue
+		}
+		other = append(other, line)
+	}
+	if file != "" && !fileUsed {
+		other = append(other, file)
+	}
+	return diags, unformatted, summary, other
+}
+
+// ShortenPaths opts in to path shortening: eslint prints absolute file
+// headers.
+func (s *JSLintStrategy) ShortenPaths() {}
+
+func (s *JSLintStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	diags, unformatted, summary, other := parseJSLint(lines, prettierListsDifferent(command, args))
+	if len(diags) == 0 && len(unformatted) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	out = append(out, other...)
+	out = append(out, renderJSLintGroups(diags)...)
+	if len(unformatted) > 0 {
+		out = append(out, fmt.Sprintf("prettier: %d files need formatting", len(unformatted)))
+		for i, f := range unformatted {
+			if i == prettierShownFiles {
+				out = append(out, fmt.Sprintf("  ... and %d more", len(unformatted)-prettierShownFiles))
+				break
+			}
+			out = append(out, "  "+f)
+		}
+	}
+	if len(summary) > 0 {
+		out = append(out, "")
+		out = append(out, summary...)
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// FilterStderr filters stderr like stdout: prettier reports unformatted files
+// there.
+func (s *JSLintStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// prettierListsDifferent reports whether the command is prettier printing
+// bare file names (--list-different).
+func prettierListsDifferent(command string, args []string) bool {
+	tool, rest := jsTool(command, args)
+	if tool != "prettier" {
+		return false
+	}
+	for _, a := range rest {
+		if a == "--list-different" || a == "-l" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderJSLintGroups renders findings grouped by severity and rule, then by
+// file, with identical messages in a file merged into one line listing their
+// positions. Rules with errors come first; each rule shows at most
+// jsLintMaxPerRule entries.
+func renderJSLintGroups(diags []jsLintDiag) []string {
+	type msgGroup struct {
+		message   string
+		positions []string
+	}
+	type fileGroup struct {
+		name string
+		msgs []*msgGroup
+	}
+	type ruleGroup struct {
+		rule, severity string
+		count          int
+		files          []*fileGroup
+		byFile         map[string]*fileGroup
+	}
+
+	var rules []*ruleGroup
+	byRule := map[string]*ruleGroup{}
+	for _, d := range diags {
+		rule := d.rule
+		if rule == "" {
+			rule = "(no rule)"
+		}
+		key := d.severity + " " + rule
+		rg, ok := byRule[key]
+		if !ok {
+			rg = &ruleGroup{rule: rule, severity: d.severity, byFile: map[string]*fileGroup{}}
+			byRule[key] = rg
+			rules = append(rules, rg)
+		}
+		rg.count++
+		fg, ok := rg.byFile[d.file]
+		if !ok {
+			fg = &fileGroup{name: d.file}
+			rg.byFile[d.file] = fg
+			rg.files = append(rg.files, fg)
+		}
+		var mg *msgGroup
+		for _, existing := range fg.msgs {
+			if existing.message == d.message {
+				mg = existing
+				break
+			}
+		}
+		if mg == nil {
+			mg = &msgGroup{message: d.message}
+			fg.msgs = append(fg.msgs, mg)
+		}
+		if d.line > 0 {
+			mg.positions = append(mg.positions, fmt.Sprintf("%d:%d", d.line, d.col))
+		}
+	}
+
+	var out []string
+	for _, severity := range []string{"error", "warning", "info"} {
+		for _, rg := range rules {
+			if rg.severity != severity {
+				continue
+			}
+			out = append(out, fmt.Sprintf("%s %s (%d in %d files):", rg.rule, severity, rg.count, len(rg.files)))
+			shown, hidden := 0, 0
+			hiddenFiles := map[string]bool{}
+			for _, fg := range rg.files {
+				for _, mg := range fg.msgs {
+					if shown >= jsLintMaxPerRule {
+						hidden += max(len(mg.positions), 1)
+						hiddenFiles[fg.name] = true
+						continue
+					}
+					shown++
+					pos := mg.positions
+					suffix := ""
+					if len(pos) > jsLintMaxLocations {
+						suffix = fmt.Sprintf(" +%d more", len(pos)-jsLintMaxLocations)
+						pos = pos[:jsLintMaxLocations]
+					}
+					entry := "  " + fg.name
+					if len(pos) > 0 {
+						entry += ":" + strings.Join(pos, ", ")
+					}
+					if mg.message != "" {
+						entry += ": " + mg.message
+					}
+					out = append(out, entry+suffix)
+				}
+			}
+			if hidden > 0 {
+				out = append(out, fmt.Sprintf("  ... and %d more %s in %d files", hidden, rg.rule, len(hiddenFiles)))
+			}
+		}
+	}
+	return out
+}
+
+// ParseOutcome reports each finding as "file:line:col: message (rule)" and
+// each unformatted file as "file: needs formatting (prettier)", so
+// `--since-last` can show new and fixed lint issues.
+func (s *JSLintStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	diags, unformatted, _, _ := parseJSLint(lines, prettierListsDifferent(command, args))
+	var o Outcome
+	for _, d := range diags {
+		loc := d.file
+		if d.line > 0 {
+			loc = fmt.Sprintf("%s:%d:%d", d.file, d.line, d.col)
+		}
+		o.Errors = append(o.Errors, fmt.Sprintf("%s: %s (%s)", loc, d.message, d.rule))
+	}
+	for _, f := range unformatted {
+		o.Errors = append(o.Errors, f+": needs formatting (prettier)")
+	}
+	return o, true
+}
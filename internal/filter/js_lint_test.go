@@ -0,0 +1,220 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// JSLintStrategy
+// ---------------------------------------------------------------------------
+
+func TestJSLintStrategy_CanHandle(t *testing.T) {
+	s := &JSLintStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"eslint", "eslint", []string{"."}, true},
+		{"npx eslint", "npx", []string{"eslint", "src"}, true},
+		{"pnpm exec eslint", "pnpm", []string{"exec", "eslint", "."}, true},
+		{"npx @biomejs/biome check", "npx", []string{"@biomejs/biome", "check", "."}, true},
+		{"biome check bin", "biome", []string{"check", "."}, true},
+		{"npx biome lint", "npx", []string{"biome", "lint"}, true},
+		{"biome format", "biome", []string{"format", "--write"}, false},
+		{"prettier --check", "npx", []string{"prettier", "--check", "."}, true},
+		{"prettier -l", "prettier", []string{"-l", "src"}, true},
+		{"prettier write", "prettier", []string{"--write", "."}, false},
+		{"prettier stdout", "prettier", []string{"src/a.js"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSLintStrategy_Name(t *testing.T) {
+	if got := (&JSLintStrategy{}).Name(); got != "js-lint" {
+		t.Errorf("Name() = %q, want %q", got, "js-lint")
+	}
+}
+
+func TestJSLintStrategy_Filter_ESLintStylish(t *testing.T) {
+	s := &JSLintStrategy{}
+	input := `
+/work/proj/src/a.js
+   1:10  warning  Unexpected console statement         no-console
+   3:1   error    'foo' is defined but never used      no-unused-vars
+   7:1   error    'bar' is defined but never used      no-unused-vars
+   9:3   warning  Unexpected console statement         no-console
+
+/work/proj/src/b.js
+  12:5   error    Missing semicolon                    semi
+  14:1   warning  Unexpected console statement         no-console
+  20:1   error    Parsing error: Unexpected token
+
+✖ 7 problems (4 errors, 3 warnings)
+  1 error and 0 warnings potentially fixable with the ` + "`--fix`" + ` option.
+`
+	result := s.Filter([]byte(input), "npx", []string{"eslint", "."}, 1)
+
+	want := `no-unused-vars error (2 in 1 files):
+  /work/proj/src/a.js:3:1: 'foo' is defined but never used
+  /work/proj/src/a.js:7:1: 'bar' is defined but never used
+semi error (1 in 1 files):
+  /work/proj/src/b.js:12:5: Missing semicolon
+(no rule) error (1 in 1 files):
+  /work/proj/src/b.js:20:1: Parsing error: Unexpected token
+no-console warning (3 in 2 files):
+  /work/proj/src/a.js:1:10, 9:3: Unexpected console statement
+  /work/proj/src/b.js:14:1: Unexpected console statement
+
+✖ 7 problems (4 errors, 3 warnings)
+1 error and 0 warnings potentially fixable with the ` + "`--fix`" + ` option.
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestJSLintStrategy_Filter_CapsRepetitiveRules(t *testing.T) {
+	s := &JSLintStrategy{}
+	var b strings.Builder
+	for i := range 20 {
+		fmt.Fprintf(&b, "src/f%02d.ts:%d:1: Unexpected any. Specify a different type. [Warning/@typescript-eslint/no-explicit-any]\n", i, i+1)
+	}
+	b.WriteString("src/main.ts:3:7: 'x' is never reassigned. Use 'const' instead. [Error/prefer-const]\n")
+	b.WriteString("\n21 problems\n")
+
+	result := s.Filter([]byte(b.String()), "eslint", []string{"-f", "unix", "."}, 1)
+
+	if !strings.HasPrefix(result.Filtered, "prefer-const error (1 in 1 files):\n  src/main.ts:3:7: ") {
+		t.Errorf("errors should come first, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "  ... and 12 more @typescript-eslint/no-explicit-any in 12 files\n") {
+		t.Errorf("expected capped rule, got:\n%s", result.Filtered)
+	}
+	if strings.Contains(result.Filtered, "src/f19.ts") {
+		t.Errorf("capped entries should be hidden, got:\n%s", result.Filtered)
+	}
+	if !strings.HasSuffix(result.Filtered, "\n\n21 problems\n") {
+		t.Errorf("summary should be kept, got:\n%s", result.Filtered)
+	}
+}
+
+func TestJSLintStrategy_Filter_Biome(t *testing.T) {
+	s := &JSLintStrategy{}
+	input := `src/a.ts:3:7 lint/style/useConst  FIXABLE  ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+  ✖ This let declares a variable that is only assigned once.
+
+    1 │ import x from "y";
+  > 3 │ let a = 1;
+      │     ^
+
+  ℹ 'a' is never reassigned.
+
+src/b.ts:9:1 lint/suspicious/noExplicitAny ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+  ⚠ Unexpected any. Specify a different type.
+
+src/b.ts format ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+  ✖ Formatter would have printed the following content:
+
+    1 1 │   const a = 1;
+    2   │ - let·b=2
+      2 │ + let b = 2;
+
+check ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+  ✖ Some errors were emitted while running checks.
+
+Checked 12 files in 5ms. No fixes applied.
+Found 2 errors.
+Found 1 warning.
+`
+	result := s.Filter([]byte(input), "biome", []string{"check", "."}, 1)
+
+	want := `lint/style/useConst error (1 in 1 files):
+  src/a.ts:3:7: This let declares a variable that is only assigned once.
+format error (1 in 1 files):
+  src/b.ts: Formatter would have printed the following content:
+lint/suspicious/noExplicitAny warning (1 in 1 files):
+  src/b.ts:9:1: Unexpected any. Specify a different type.
+
+✖ Some errors were emitted while running checks.
+Checked 12 files in 5ms. No fixes applied.
+Found 2 errors.
+Found 1 warning.
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestJSLintStrategy_Filter_PrettierCheck(t *testing.T) {
+	s := &JSLintStrategy{}
+	var b strings.Builder
+	b.WriteString("Checking formatting...\n")
+	for i := range 12 {
+		fmt.Fprintf(&b, "[warn] src/components/c%02d.tsx\n", i)
+	}
+	b.WriteString("[error] src/broken.js: SyntaxError: Unexpected token (3:5)\n")
+	b.WriteString("[warn] Code style issues found in 12 files. Run Prettier with --write to fix.\n")
+
+	result := s.FilterStderr([]byte(b.String()), "npx", []string{"prettier", "--check", "."}, 2)
+
+	want := `[error] src/broken.js: SyntaxError: Unexpected token (3:5)
+prettier: 12 files need formatting
+  src/components/c00.tsx
+  src/components/c01.tsx
+  src/components/c02.tsx
+  src/components/c03.tsx
+  src/components/c04.tsx
+  ... and 7 more
+
+Code style issues found in 12 files. Run Prettier with --write to fix.
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestJSLintStrategy_Filter_PrettierListDifferent(t *testing.T) {
+	s := &JSLintStrategy{}
+	var b strings.Builder
+	for i := range 10 {
+		fmt.Fprintf(&b, "src/f%d.js\n", i)
+	}
+
+	result := s.Filter([]byte(b.String()), "prettier", []string{"-l", "src"}, 1)
+
+	if !strings.HasPrefix(result.Filtered, "prettier: 10 files need formatting\n  src/f0.js\n") {
+		t.Errorf("expected folded file list, got:\n%s", result.Filtered)
+	}
+}
+
+func TestJSLintStrategy_ParseOutcome(t *testing.T) {
+	s := &JSLintStrategy{}
+	input := "src/a.js:3:1: 'foo' is defined but never used [Error/no-unused-vars]\n" +
+		"[warn] src/b.js\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "eslint", nil, 1)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := "src/a.js:3:1: 'foo' is defined but never used (no-unused-vars)\nsrc/b.js: needs formatting (prettier)"
+	if got := strings.Join(o.Errors, "\n"); got != want {
+		t.Errorf("Errors = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		args     []string
+		wantTool string
+		wantArgs string
+	}{
+		{"direct", "jest", []string{"--ci"}, "jest", "--ci"},
+		{"bin path", "node_modules/.bin/vitest", []string{"run"}, "vitest", "run"},
+		{"npx", "npx", []string{"jest", "src"}, "jest", "src"},
+		{"npx with package flag", "npx", []string{"-p", "typescript", "tsc", "--noEmit"}, "tsc", "--noEmit"},
+		{"npx --yes", "npx", []string{"--yes", "vitest", "run"}, "vitest", "run"},
+		{"bunx", "bunx", []string{"tsc"}, "tsc", ""},
+		{"npm test", "npm", []string{"test"}, "test", ""},
+		{"npm t", "npm", []string{"t", "--", "--watch=false"}, "test", "-- --watch=false"},
+		{"npm run", "npm", []string{"run", "lint"}, "lint", ""},
+		{"npm prefix run", "npm", []string{"--prefix", "web", "run", "test"}, "test", ""},
+		{"npm exec", "npm", []string{"exec", "--", "eslint", "."}, "eslint", "."},
+		{"yarn bin", "yarn", []string{"jest"}, "jest", ""},
+		{"yarn dlx", "yarn", []string{"dlx", "prettier", "--check", "."}, "prettier", "--check ."},
+		{"pnpm filter test", "pnpm", []string{"--filter", "web", "test"}, "test", ""},
+		{"pnpm tsc", "pnpm", []string{"tsc", "--noEmit"}, "tsc", "--noEmit"},
+		{"pnpm exec", "pnpm", []string{"exec", "tsc"}, "tsc", ""},
+		{"bun test", "bun", []string{"test"}, "test", ""},
+		{"bun x", "bun", []string{"x", "vitest"}, "vitest", ""},
+		{"npm alone", "npm", nil, "", ""},
+		{"other", "make", []string{"test"}, "make", "test"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tool, args := jsTool(tc.command, tc.args)
+			if tool != tc.wantTool || strings.Join(args, " ") != tc.wantArgs {
+				t.Errorf("jsTool(%q, %v) = %q, %q; want %q, %q", tc.command, tc.args, tool, args, tc.wantTool, tc.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// JSTestStrategy
+// ---------------------------------------------------------------------------
+
+// JSTestStrategy filters Jest, Vitest and `bun test` output down to failing
+// suites and tests, their failure details and the final totals. It handles the
+// runners directly, through npx/pnpm/yarn/bun, and through a package's test
+// script (`npm test`, `yarn test`, `pnpm test`, `bun test`). Output that turns
+// out not to come from a known runner is handled like GenericErrorStrategy.
+type JSTestStrategy struct{}
+
+func (s *JSTestStrategy) Name() string { return "js-test" }
+
+func (s *JSTestStrategy) CanHandle(command string, args []string) bool {
+	tool, _ := jsTool(command, args)
+	switch tool {
+	case "jest", "vitest":
+		return true
+	case "test":
+		// Package scripts, and bun's built-in runner
+		switch command {
+		case "npm", "yarn", "pnpm", "bun":
+			return true
+		}
+	}
+	return false
+}
+
+// Package-level compiled regexes for JSTestStrategy.
+var (
+	// jestSuiteRe matches Jest suite headers: " PASS  src/a.test.js (1.2 s)".
+	jestSuiteRe = regexp.MustCompile(`^\s*(PASS|FAIL)\s+(\S+)(?:\s+\(.*\))?$`)
+	// jestFailureRe matches a failure block header: "  ● Suite › test".
+	jestFailureRe = regexp.MustCompile(`^\s*● (.+)$`)
+	// jestTotalsRe matches the final totals and snapshot summary lines.
+	jestTotalsRe = regexp.MustCompile(`^(Test Suites|Tests|Snapshots|Time):\s|^Snapshot Summary$`)
+	// jestPassedTestRe matches passing and skipped tests in a --verbose tree.
+	jestPassedTestRe = regexp.MustCompile(`^\s+[✓√○✎] `)
+	// jsLibraryFrameRe matches stack frames in dependencies, node internals or
+	// anonymous native code.
+	jsLibraryFrameRe = regexp.MustCompile(`^\s+(at .*(node_modules[/\\]|node:internal|\(internal/|\(<anonymous>\))|❯ \S*node_modules/)`)
+	// jsScriptEchoRe matches the lines a package manager prints before running a script.
+	jsScriptEchoRe = regexp.MustCompile(`^(> |\$ |yarn run v\d)`)
+
+	// vitestRunRe matches Vitest's " RUN  v1.6.0 /path" banner.
+	vitestRunRe = regexp.MustCompile(`^\s*RUN\s+v\d`)
+	// vitestFileRe matches a per-file result: " ✓ src/a.test.ts (3 tests) 5ms".
+	vitestFileRe = regexp.MustCompile(`^ ([✓❯×↓]) (\S+) \(\d+ tests?`)
+	// vitestNestedRe matches per-test lines under a file result.
+	vitestNestedRe = regexp.MustCompile(`^\s{3,}([✓↓]) `)
+	// vitestFailRe matches a failed test header: " FAIL  src/a.test.ts > suite > test".
+	vitestFailRe = regexp.MustCompile(`^\s*FAIL\s+(\S+) > (.+)$`)
+	// vitestSectionRe matches "⎯⎯⎯ Failed Tests 2 ⎯⎯⎯" section banners.
+	vitestSectionRe = regexp.MustCompile(`^⎯+ (.+?) ⎯+$`)
+	// vitestSeparatorRe matches the plain and "⎯⎯[1/2]⎯" separators between failures.
+	vitestSeparatorRe = regexp.MustCompile(`^⎯+(\[\d+/\d+\]⎯)?$`)
+	// vitestTotalsRe matches the final totals block.
+	vitestTotalsRe = regexp.MustCompile(`^\s*(Test Files|Tests|Snapshots|Errors|Type Errors|Start at|Duration)  `)
+	// vitestConsoleRe matches a console output block header: "stdout | src/a.test.ts > test".
+	vitestConsoleRe = regexp.MustCompile(`^(stdout|stderr) \| (\S+)`)
+
+	// bunBannerRe matches bun's "bun test v1.1.0 (hash)" banner.
+	bunBannerRe = regexp.MustCompile(`^bun test v\d`)
+	// bunPassRe matches bun's passing and skipped test lines.
+	bunPassRe = regexp.MustCompile(`^(✓|\(pass\)|\(skip\)|» ) `)
+	// bunFailRe matches bun's "(fail) suite > test [0.20ms]" lines.
+	bunFailRe = regexp.MustCompile(`^\(fail\) (.+?)(?: \[[\d.]+m?s\])?$`)
+	// bunFileRe matches the per-file headers bun prints before its tests.
+	bunFileRe = regexp.MustCompile(`^(\S+\.(?:test|spec|_test)\.[cm]?[jt]sx?):$`)
+	// bunTotalsRe matches bun's totals: " 3 pass", " 1 fail", "Ran 4 tests across 2 files.".
+	bunTotalsRe = regexp.MustCompile(`^\s*\d+ (pass|fail|skip|todo|snapshots?|expect\(\) calls)\b|^Ran \d+ tests? across`)
+)
+
+// jsFailureShown caps the lines kept from each failure block; long snapshot
+// diffs are the usual culprit.
+const jsFailureShown = 60
+
+// ShortenPaths opts in to path shortening for failure stack frames.
+func (s *JSTestStrategy) ShortenPaths() {}
+
+func (s *JSTestStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	// A test script may run anything: without a known runner's totals, fall
+	// back to plain error highlighting
+	if !hasJSTestTotals(lines) {
+		return (&GenericErrorStrategy{}).Filter(raw, command, args, exitCode)
+	}
+
+	// Vitest prints console output before the file results; keep it only for
+	// files with failures
+	failingFiles := map[string]bool{}
+	for _, line := range lines {
+		if m := vitestFileRe.FindStringSubmatch(line); m != nil && (m[1] == "❯" || m[1] == "×") {
+			failingFiles[m[2]] = true
+		} else if m := vitestFailRe.FindStringSubmatch(line); m != nil {
+			failingFiles[m[1]] = true
+		}
+	}
+
+	const (
+		modeNone    = iota
+		modeSkip    // passing suite, console block, repeated summary
+		modeFailure // failing suite or failure section
+		modeTotals
+	)
+	mode := modeNone
+	started, inSummary := false, false
+	var out []string
+	blockLines, blockDropped := 0, 0
+	hidden := 0
+	hiddenIndent := ""
+
+	flushHidden := func() {
+		if hidden > 0 {
+			out = append(out, fmt.Sprintf("%s(%d library frames hidden)", hiddenIndent, hidden))
+			hidden = 0
+		}
+	}
+	flushBlock := func() {
+		flushHidden()
+		if blockDropped > 0 {
+			out = append(out, fmt.Sprintf("    ... %d more lines", blockDropped))
+		}
+		blockLines, blockDropped = 0, 0
+	}
+	emit := func(line string) {
+		if strings.TrimSpace(line) == "" {
+			// Collapse runs of blank lines; none at the start
+			if len(out) == 0 || strings.TrimSpace(out[len(out)-1]) == "" {
+				return
+			}
+		}
+		out = append(out, line)
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Package manager preamble before the runner starts
+		if !started {
+			if jsScriptEchoRe.MatchString(line) || trimmed == "" {
+				continue
+			}
+			started = true
+		}
+
+		switch {
+		case vitestRunRe.MatchString(line), bunBannerRe.MatchString(line),
+			strings.HasPrefix(trimmed, "Ran all test suites"):
+			continue
+		case vitestFailRe.MatchString(line):
+			flushBlock()
+			mode = modeFailure
+			emit(line)
+			continue
+		case jestSuiteRe.MatchString(line):
+			flushBlock()
+			if inSummary || jestSuiteRe.FindStringSubmatch(line)[1] == "PASS" {
+				mode = modeSkip
+				continue
+			}
+			mode = modeFailure
+			emit("")
+			emit(line)
+			continue
+		case trimmed == "Summary of all failing tests":
+			// Jest repeats every failure here when several suites fail
+			flushBlock()
+			mode = modeSkip
+			inSummary = true
+			continue
+		case vitestFileRe.MatchString(line):
+			flushBlock()
+			if m := vitestFileRe.FindStringSubmatch(line); m[1] == "✓" || m[1] == "↓" {
+				mode = modeSkip
+				continue
+			}
+			mode = modeFailure
+			emit(line)
+			continue
+		case vitestConsoleRe.MatchString(line):
+			flushBlock()
+			mode = modeSkip
+			if failingFiles[vitestConsoleRe.FindStringSubmatch(line)[2]] {
+				mode = modeFailure
+				emit(line)
+			}
+			continue
+		case vitestSectionRe.MatchString(line):
+			flushBlock()
+			mode = modeFailure
+			emit("")
+			emit(line)
+			continue
+		case vitestSeparatorRe.MatchString(line):
+			flushBlock()
+			continue
+		case jestTotalsRe.MatchString(line), vitestTotalsRe.MatchString(line), bunTotalsRe.MatchString(line):
+			if mode != modeTotals {
+				flushBlock()
+				emit("")
+				mode = modeTotals
+			}
+			if !strings.HasPrefix(trimmed, "Start at") {
+				emit(line)
+			}
+			continue
+		}
+
+		switch mode {
+		case modeSkip:
+			// Verbose trees and console output of passing suites, and the
+			// repeated failure summary, run until the next header
+			continue
+		case modeTotals:
+			if trimmed != "" {
+				emit(line)
+			}
+			continue
+		}
+
+		// Passing tests: Jest --verbose trees, Vitest per-test lines, bun
+		if jestPassedTestRe.MatchString(line) || vitestNestedRe.MatchString(line) || bunPassRe.MatchString(line) {
+			continue
+		}
+		if jestFailureRe.MatchString(line) || bunFailRe.MatchString(line) {
+			flushBlock()
+		}
+		if jsLibraryFrameRe.MatchString(line) {
+			if hidden == 0 {
+				hiddenIndent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			}
+			hidden++
+			continue
+		}
+		flushHidden()
+		if mode == modeFailure && blockLines >= jsFailureShown {
+			if trimmed != "" {
+				blockDropped++
+			}
+			continue
+		}
+		blockLines++
+		emit(line)
+	}
+	flushBlock()
+
+	// Trailing blank line from the last section
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// hasJSTestTotals reports whether lines contain a Jest, Vitest or bun totals line.
+func hasJSTestTotals(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Tests:") || strings.HasPrefix(line, "Test Suites:") ||
+			strings.HasPrefix(strings.TrimSpace(line), "Test Files  ") ||
+			strings.HasPrefix(line, "Ran ") && bunTotalsRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterStderr filters stderr like stdout: Jest and bun test write their
+// reporter output, including failures and the summary, to stderr.
+func (s *JSTestStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// ParseOutcome extracts failing tests and passing test files. Test ids are the
+// test file followed by the test's full name, e.g. "src/a.test.ts math > adds"
+// (Vitest, bun) or "src/a.test.js math › adds" (Jest); passing files are
+// recorded by path, so a fixed test is recognized through its file.
+func (s *JSTestStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	if !hasJSTestTotals(lines) {
+		return o, false
+	}
+
+	seen := map[string]bool{}
+	addFailed := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			o.Failed = append(o.Failed, id)
+		}
+	}
+
+	suite := ""
+	inSummary := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "Summary of all failing tests" {
+			inSummary = true
+			continue
+		}
+		if m := vitestFailRe.FindStringSubmatch(line); m != nil {
+			addFailed(m[1] + " " + strings.TrimSpace(m[2]))
+			continue
+		}
+		if m := jestSuiteRe.FindStringSubmatch(line); m != nil {
+			suite = m[2]
+			if m[1] == "PASS" && !inSummary {
+				o.Passed = append(o.Passed, suite)
+			}
+			continue
+		}
+		if m := vitestFileRe.FindStringSubmatch(line); m != nil {
+			if m[1] == "✓" {
+				o.Passed = append(o.Passed, m[2])
+			}
+			continue
+		}
+		if m := bunFileRe.FindStringSubmatch(line); m != nil {
+			suite = m[1]
+			continue
+		}
+		if m := bunFailRe.FindStringSubmatch(line); m != nil {
+			addFailed(strings.TrimSpace(suite + " " + m[1]))
+			continue
+		}
+		if m := jestFailureRe.FindStringSubmatch(line); m != nil && suite != "" {
+			name := strings.TrimSpace(m[1])
+			if name == "Console" {
+				continue
+			}
+			if name == "Test suite failed to run" {
+				addFailed(suite)
+				continue
+			}
+			addFailed(suite + " " + name)
+		}
+	}
+	return o, true
+}
@@ -0,0 +1,344 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// JSTestStrategy
+// ---------------------------------------------------------------------------
+
+func TestJSTestStrategy_CanHandle(t *testing.T) {
+	s := &JSTestStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"jest", "jest", nil, true},
+		{"npx jest", "npx", []string{"jest", "--ci"}, true},
+		{"npx vitest run", "npx", []string{"vitest", "run"}, true},
+		{"pnpm vitest", "pnpm", []string{"vitest"}, true},
+		{"npm test", "npm", []string{"test"}, true},
+		{"npm run test", "npm", []string{"run", "test"}, true},
+		{"yarn test", "yarn", []string{"test"}, true},
+		{"pnpm test", "pnpm", []string{"test"}, true},
+		{"bun test", "bun", []string{"test"}, true},
+		{"npm install", "npm", []string{"install"}, false},
+		{"npm run build", "npm", []string{"run", "build"}, false},
+		{"npx test", "npx", []string{"test"}, false},
+		{"make test", "make", []string{"test"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSTestStrategy_Name(t *testing.T) {
+	if got := (&JSTestStrategy{}).Name(); got != "js-test" {
+		t.Errorf("Name() = %q, want %q", got, "js-test")
+	}
+}
+
+// jestRun is `npm test` running Jest with two failing suites, so Jest also
+// prints its summary of all failing tests.
+const jestRun = `
+> proj@1.0.0 test
+> jest
+
+ PASS  src/util.test.js
+  console.log
+    loading fixtures
+
+      at Object.log (src/util.test.js:3:11)
+
+ FAIL  src/math.test.js
+  ● math › adds
+
+    expect(received).toBe(expected) // Object.is equality
+
+    Expected: 2
+    Received: 3
+
+      4 | test('adds', () => {
+    > 5 |   expect(add(1, 2)).toBe(2);
+        |                     ^
+      6 | });
+
+      at Object.toBe (src/math.test.js:5:21)
+      at Promise.then.completed (node_modules/jest-circus/build/utils.js:298:28)
+      at new Promise (<anonymous>)
+      at callAsyncCircusFn (node_modules/jest-circus/build/utils.js:231:10)
+
+ PASS  src/str.test.js (1.2 s)
+ FAIL  src/api.test.js
+  ● Test suite failed to run
+
+    Cannot find module './client' from 'src/api.test.js'
+
+Summary of all failing tests
+ FAIL  src/math.test.js
+  ● math › adds
+
+    expect(received).toBe(expected) // Object.is equality
+
+ FAIL  src/api.test.js
+  ● Test suite failed to run
+
+    Cannot find module './client' from 'src/api.test.js'
+
+Test Suites: 2 failed, 2 passed, 4 total
+Tests:       1 failed, 11 passed, 12 total
+Snapshots:   0 total
+Time:        2.345 s
+Ran all test suites.
+`
+
+func TestJSTestStrategy_Filter_Jest(t *testing.T) {
+	s := &JSTestStrategy{}
+	result := s.Filter([]byte(jestRun), "npm", []string{"test"}, 1)
+
+	want := ` FAIL  src/math.test.js
+  ● math › adds
+
+    expect(received).toBe(expected) // Object.is equality
+
+    Expected: 2
+    Received: 3
+
+      4 | test('adds', () => {
+    > 5 |   expect(add(1, 2)).toBe(2);
+        |                     ^
+      6 | });
+
+      at Object.toBe (src/math.test.js:5:21)
+      (3 library frames hidden)
+
+ FAIL  src/api.test.js
+  ● Test suite failed to run
+
+    Cannot find module './client' from 'src/api.test.js'
+
+Test Suites: 2 failed, 2 passed, 4 total
+Tests:       1 failed, 11 passed, 12 total
+Snapshots:   0 total
+Time:        2.345 s
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestJSTestStrategy_FilterStderr_Jest(t *testing.T) {
+	// Jest writes its reporter output to stderr.
+	s := &JSTestStrategy{}
+	stdout := s.Filter([]byte(jestRun), "npx", []string{"jest"}, 1)
+	stderr := s.FilterStderr([]byte(jestRun), "npx", []string{"jest"}, 1)
+	if stderr != stdout {
+		t.Errorf("FilterStderr differs from Filter.\ngot:\n%s\nwant:\n%s", stderr.Filtered, stdout.Filtered)
+	}
+	if !stderr.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+	if !strings.Contains(stderr.Filtered, "Cannot find module './client'") {
+		t.Errorf("failure dropped:\n%s", stderr.Filtered)
+	}
+}
+
+// vitestRun is `npx vitest run` with one failing test and console output from
+// both a passing and a failing file.
+const vitestRun = `
+ RUN  v1.6.0 /work/proj
+
+stdout | src/a.test.ts > setup
+connecting
+
+stdout | src/b.test.ts > math > adds
+computing 1 + 2
+
+ ✓ src/a.test.ts (3 tests) 5ms
+ ❯ src/b.test.ts (2 tests | 1 failed) 8ms
+   ✓ math > subtracts
+   × math > adds
+     → expected 3 to be 2 // Object.is equality
+ ↓ src/c.test.ts (1 test | 1 skipped)
+
+⎯⎯⎯⎯⎯⎯⎯ Failed Tests 1 ⎯⎯⎯⎯⎯⎯⎯
+
+ FAIL  src/b.test.ts > math > adds
+AssertionError: expected 3 to be 2 // Object.is equality
+
+- Expected
++ Received
+
+- 2
++ 3
+
+ ❯ src/b.test.ts:5:23
+      3| describe('math', () => {
+      4|   it('adds', () => {
+      5|     expect(1 + 2).toBe(2)
+       |                       ^
+      6|   })
+
+⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯⎯[1/1]⎯
+
+ Test Files  1 failed | 1 passed | 1 skipped (3)
+      Tests  1 failed | 4 passed | 1 skipped (6)
+   Start at  12:00:00
+   Duration  1.20s (transform 100ms, setup 0ms, collect 200ms, tests 15ms)
+`
+
+func TestJSTestStrategy_Filter_Vitest(t *testing.T) {
+	s := &JSTestStrategy{}
+	result := s.Filter([]byte(vitestRun), "npx", []string{"vitest", "run"}, 1)
+
+	want := `stdout | src/b.test.ts > math > adds
+computing 1 + 2
+
+ ❯ src/b.test.ts (2 tests | 1 failed) 8ms
+   × math > adds
+     → expected 3 to be 2 // Object.is equality
+
+⎯⎯⎯⎯⎯⎯⎯ Failed Tests 1 ⎯⎯⎯⎯⎯⎯⎯
+
+ FAIL  src/b.test.ts > math > adds
+AssertionError: expected 3 to be 2 // Object.is equality
+
+- Expected
++ Received
+
+- 2
++ 3
+
+ ❯ src/b.test.ts:5:23
+      3| describe('math', () => {
+      4|   it('adds', () => {
+      5|     expect(1 + 2).toBe(2)
+       |                       ^
+      6|   })
+
+ Test Files  1 failed | 1 passed | 1 skipped (3)
+      Tests  1 failed | 4 passed | 1 skipped (6)
+   Duration  1.20s (transform 100ms, setup 0ms, collect 200ms, tests 15ms)
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestJSTestStrategy_Filter_Bun(t *testing.T) {
+	s := &JSTestStrategy{}
+	input := `bun test v1.1.0 (5e5e7c60)
+
+src/a.test.ts:
+✓ math > subtracts [0.05ms]
+✓ math > multiplies [0.02ms]
+error: expect(received).toBe(expected)
+
+Expected: 2
+Received: 3
+
+      at /work/src/a.test.ts:5:10
+(fail) math > adds [0.20ms]
+
+ 2 pass
+ 1 fail
+ 3 expect() calls
+Ran 3 tests across 1 files. [50.00ms]
+`
+	result := s.Filter([]byte(input), "bun", []string{"test"}, 1)
+
+	if strings.Contains(result.Filtered, "✓") || strings.Contains(result.Filtered, "bun test v") {
+		t.Errorf("passing tests and banner should be dropped, got:\n%s", result.Filtered)
+	}
+	for _, want := range []string{"error: expect(received).toBe(expected)", "(fail) math > adds", " 1 fail\n", "Ran 3 tests across 1 files."} {
+		if !strings.Contains(result.Filtered, want) {
+			t.Errorf("missing %q, got:\n%s", want, result.Filtered)
+		}
+	}
+}
+
+func TestJSTestStrategy_Filter_CapsFailureBlocks(t *testing.T) {
+	s := &JSTestStrategy{}
+	var b strings.Builder
+	b.WriteString(" FAIL  src/snap.test.js\n  ● renders\n\n    expect(received).toMatchSnapshot()\n\n")
+	for i := range 100 {
+		fmt.Fprintf(&b, "    + <li>item %d</li>\n", i)
+	}
+	b.WriteString("\nTests:       1 failed, 1 total\n")
+
+	result := s.Filter([]byte(b.String()), "jest", nil, 1)
+
+	if strings.Contains(result.Filtered, "item 99") {
+		t.Errorf("long failure block should be capped, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "    ... 44 more lines\n") {
+		t.Errorf("expected dropped line count, got:\n%s", result.Filtered)
+	}
+	if !strings.HasSuffix(result.Filtered, "Tests:       1 failed, 1 total\n") {
+		t.Errorf("totals should be kept, got:\n%s", result.Filtered)
+	}
+}
+
+func TestJSTestStrategy_Filter_UnknownRunnerFallsBack(t *testing.T) {
+	s := &JSTestStrategy{}
+	var b strings.Builder
+	for i := range 20 {
+		fmt.Fprintf(&b, "  ok %d - case %d\n", i, i)
+	}
+	b.WriteString("Error: mocha exploded\n")
+
+	result := s.Filter([]byte(b.String()), "npm", []string{"test"}, 1)
+	generic := (&GenericErrorStrategy{}).Filter([]byte(b.String()), "npm", []string{"test"}, 1)
+
+	if result.Filtered != generic.Filtered {
+		t.Errorf("expected generic filtering, got:\n%s", result.Filtered)
+	}
+}
+
+func TestJSTestStrategy_ParseOutcome(t *testing.T) {
+	s := &JSTestStrategy{}
+
+	o, ok := s.ParseOutcome([]byte(jestRun), "npm", []string{"test"}, 1)
+	if !ok {
+		t.Fatal("expected ok for jest")
+	}
+	if got := strings.Join(o.Failed, ","); got != "src/math.test.js math › adds,src/api.test.js" {
+		t.Errorf("jest Failed = %q", got)
+	}
+	if got := strings.Join(o.Passed, ","); got != "src/util.test.js,src/str.test.js" {
+		t.Errorf("jest Passed = %q", got)
+	}
+
+	o, ok = s.ParseOutcome([]byte(vitestRun), "npx", []string{"vitest", "run"}, 1)
+	if !ok {
+		t.Fatal("expected ok for vitest")
+	}
+	if got := strings.Join(o.Failed, ","); got != "src/b.test.ts math > adds" {
+		t.Errorf("vitest Failed = %q", got)
+	}
+	if got := strings.Join(o.Passed, ","); got != "src/a.test.ts" {
+		t.Errorf("vitest Passed = %q", got)
+	}
+	if !o.HasPassed("src/a.test.ts math > works") {
+		t.Error("expected a test in a passing file to count as passed")
+	}
+
+	if _, ok := s.ParseOutcome([]byte("Error: mocha exploded\n"), "npm", []string{"test"}, 1); ok {
+		t.Error("expected no outcome for unknown runner output")
+	}
+}
@@ -0,0 +1,554 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// jvmTool returns "mvn" or "gradle" for Maven, Gradle and their project
+// wrappers (./mvnw, ./gradlew, mvnw.cmd, gradlew.bat), or "" otherwise.
+func jvmTool(command string) string {
+	base := filepath.Base(command)
+	if ext := filepath.Ext(base); strings.EqualFold(ext, ".bat") || strings.EqualFold(ext, ".cmd") {
+		base = strings.TrimSuffix(base, ext)
+	}
+	switch base {
+	case "mvn", "mvnw":
+		return "mvn"
+	case "gradle", "gradlew":
+		return "gradle"
+	}
+	return ""
+}
+
+// jvmGoals returns the goals or tasks in args, skipping flags and the values
+// of the flags listed in valueFlags.
+func jvmGoals(args []string, valueFlags map[string]bool) []string {
+	var goals []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if strings.HasPrefix(a, "-") {
+			if valueFlags[a] {
+				i++
+			}
+			continue
+		}
+		goals = append(goals, a)
+	}
+	return goals
+}
+
+// ---------------------------------------------------------------------------
+// MavenStrategy
+// ---------------------------------------------------------------------------
+
+// MavenStrategy filters Maven builds (`mvn` or `./mvnw`) running lifecycle
+// phases. It keeps compiler diagnostics (once, although Maven repeats them in
+// its failure report), failing Surefire/Failsafe test classes and tests with
+// their stack traces, the test totals, failed and skipped modules from the
+// reactor summary, the remaining [ERROR] lines and the BUILD result with its
+// time. Stack traces are condensed by condenseJVMTraces. Downloads, [INFO]
+// banners, plugin headers and the output of passing tests are dropped.
+type MavenStrategy struct{}
+
+func (s *MavenStrategy) Name() string { return "maven" }
+
+// mavenPhases lists the lifecycle phases that build, test or package a project.
+var mavenPhases = map[string]bool{
+	"clean": true, "validate": true, "compile": true, "test-compile": true,
+	"test": true, "package": true, "integration-test": true, "verify": true,
+	"install": true, "deploy": true,
+}
+
+// mavenValueFlags lists Maven flags that take a separate value.
+var mavenValueFlags = map[string]bool{
+	"-pl": true, "--projects": true, "-f": true, "--file": true,
+	"-P": true, "--activate-profiles": true, "-T": true, "--threads": true,
+	"-rf": true, "--resume-from": true, "-s": true, "--settings": true,
+	"-gs": true, "--global-settings": true, "-l": true, "--log-file": true,
+	"-D": true, "--define": true,
+}
+
+// CanHandle accepts Maven runs with a lifecycle phase or a compiler,
+// Surefire or Failsafe goal. Other goals (dependency:tree, help:*,
+// spring-boot:run) print what the user asked for and are left alone.
+func (s *MavenStrategy) CanHandle(command string, args []string) bool {
+	if jvmTool(command) != "mvn" {
+		return false
+	}
+	for _, g := range jvmGoals(args, mavenValueFlags) {
+		if mavenPhases[g] || strings.HasPrefix(g, "compiler:") ||
+			strings.HasPrefix(g, "surefire:") || strings.HasPrefix(g, "failsafe:") {
+			return true
+		}
+	}
+	return false
+}
+
+// FoldRepeats folds the warnings and notes plugins repeat for every module.
+func (s *MavenStrategy) FoldRepeats() {}
+
+// Package-level compiled regexes for MavenStrategy.
+var (
+	// mvnLevelRe splits "[ERROR] msg" into its level and message.
+	mvnLevelRe = regexp.MustCompile(`^\[(INFO|WARNING|WARN|ERROR|FATAL|DEBUG)\] ?(.*)$`)
+	// mvnTransferRe matches artifact downloads and transfer progress.
+	mvnTransferRe = regexp.MustCompile(`^(\[INFO\] )?(Download(ing|ed) from \S+:|Upload(ing|ed) to \S+:|Progress \(\d+\):)`)
+	// mvnResultRe matches the "BUILD SUCCESS/FAILURE" and "Total time:" lines.
+	mvnResultRe = regexp.MustCompile(`^(BUILD (SUCCESS|FAILURE)$|Total time:)`)
+	// mvnReactorRe matches reactor summary entries: "web ..... FAILURE [  2.345 s]".
+	mvnReactorRe = regexp.MustCompile(`^\S.*? \.+ ?(SUCCESS|FAILURE|SKIPPED)\b`)
+	// mvnDiagRe matches javac, kotlinc, scalac and groovyc diagnostics:
+	// "/src/Foo.java:[12,8] msg", "/src/App.kt: (12, 5) msg", "file:///src/App.kt:12:5 msg".
+	mvnDiagRe = regexp.MustCompile(`^\S.*\.(java|kt|scala|groovy)(:\[\d+,\d+\]|: \(\d+, \d+\)|:\d+:\d+) `)
+	// mvnTestClassRe matches Surefire's per-class results:
+	// "Tests run: 3, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.05 s <<< FAILURE! - in com.example.FooTest".
+	mvnTestClassRe = regexp.MustCompile(`^Tests run: \d+, Failures: (\d+), Errors: (\d+), Skipped: \d+, Time elapsed: .*? --? in (\S+)$`)
+	// mvnTestTotalsRe matches Surefire's totals: "Tests run: 5, Failures: 1, Errors: 0, Skipped: 0".
+	mvnTestTotalsRe = regexp.MustCompile(`^Tests run: \d+, Failures: \d+, Errors: \d+, Skipped: \d+$`)
+	// mvnTestCaseRe matches a failing test: "com.example.FooTest.testAdd -- Time elapsed: 0.01 s <<< FAILURE!"
+	// (Surefire 2 prints "testAdd(com.example.FooTest)  Time elapsed: ...").
+	mvnTestCaseRe = regexp.MustCompile(`^(\S+?)(?:\(([\w.$]+)\))?\s+(?:-- )?Time elapsed: .*<<< (FAILURE|ERROR)!$`)
+	// mvnBoilerplateRe matches the help text Maven prints after a failure.
+	mvnBoilerplateRe = regexp.MustCompile(`^(-> \[Help \d+\]|\[Help \d+\] http|To see the full stack trace of the errors|Re-run Maven using the -X switch|For more information about the errors and possible solutions|COMPILATION ERROR :?$|$)`)
+)
+
+// continuation states for lines that follow a kept or dropped headline.
+const (
+	jvmContNone = iota
+	jvmContKeep
+	jvmContDrop
+)
+
+// ShortenPaths opts in to path shortening: javac errors name absolute
+// source paths.
+func (s *MavenStrategy) ShortenPaths() {}
+
+func (s *MavenStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	// Without Maven's level prefixes this isn't a build log (mvn -v, a
+	// launcher error): fall back to plain error highlighting
+	if !hasMavenLevels(lines) {
+		return (&GenericErrorStrategy{}).Filter(raw, command, args, exitCode)
+	}
+
+	var out []string
+	seen := map[string]bool{}
+	cont := jvmContNone
+	for _, line := range lines {
+		if mvnTransferRe.MatchString(line) {
+			continue
+		}
+		m := mvnLevelRe.FindStringSubmatch(line)
+		if m == nil {
+			// Unprefixed lines are stack traces and compiler detail lines of
+			// the previous headline, or the output of running tests
+			if cont == jvmContKeep && strings.TrimSpace(line) != "" {
+				out = append(out, line)
+			}
+			continue
+		}
+		level, msg := m[1], m[2]
+
+		// "[ERROR]   symbol: class Bar" continues a diagnostic in the failure report
+		if cont != jvmContNone && strings.HasPrefix(msg, " ") {
+			if cont == jvmContKeep {
+				out = append(out, line)
+			}
+			continue
+		}
+		cont = jvmContNone
+
+		switch {
+		case mvnResultRe.MatchString(msg), mvnTestTotalsRe.MatchString(msg):
+			out = append(out, line)
+		case mvnReactorRe.MatchString(msg):
+			if mvnReactorRe.FindStringSubmatch(msg)[1] != "SUCCESS" {
+				out = append(out, line)
+			}
+		case mvnTestClassRe.MatchString(msg):
+			if tm := mvnTestClassRe.FindStringSubmatch(msg); tm[1] != "0" || tm[2] != "0" {
+				out = append(out, line)
+			}
+		case mvnTestCaseRe.MatchString(msg):
+			out = append(out, line)
+			cont = jvmContKeep
+		case mvnDiagRe.MatchString(msg):
+			if seen[msg] {
+				cont = jvmContDrop
+				continue
+			}
+			seen[msg] = true
+			out = append(out, line)
+			cont = jvmContKeep
+		case level == "ERROR" || level == "FATAL":
+			if !mvnBoilerplateRe.MatchString(strings.TrimSpace(msg)) {
+				out = append(out, line)
+			}
+		}
+	}
+	out, _ = condenseJVMTraces(out)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// hasMavenLevels reports whether lines contain Maven's "[INFO]"-style prefixes.
+func hasMavenLevels(lines []string) bool {
+	for _, line := range lines {
+		if mvnLevelRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOutcome extracts failing tests, passing test classes and compiler
+// errors. Test ids are the class followed by the method, e.g.
+// "com.example.FooTest testAdd"; passing classes are recorded by name, so a
+// fixed test is recognized through its class.
+func (s *MavenStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	lines := strings.Split(StripANSIString(string(raw)), "\n")
+	if !hasMavenLevels(lines) {
+		return o, false
+	}
+
+	seen := map[string]bool{}
+	for _, line := range lines {
+		m := mvnLevelRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level, msg := m[1], m[2]
+		switch {
+		case mvnTestClassRe.MatchString(msg):
+			if tm := mvnTestClassRe.FindStringSubmatch(msg); tm[1] == "0" && tm[2] == "0" {
+				o.Passed = append(o.Passed, tm[3])
+			}
+		case mvnTestCaseRe.MatchString(msg):
+			tm := mvnTestCaseRe.FindStringSubmatch(msg)
+			class, method := tm[2], tm[1]
+			if class == "" {
+				if i := strings.LastIndex(tm[1], "."); i > 0 {
+					class, method = tm[1][:i], tm[1][i+1:]
+				}
+			}
+			id := class + " " + method
+			if !seen[id] {
+				seen[id] = true
+				o.Failed = append(o.Failed, id)
+			}
+		case level == "ERROR" && mvnDiagRe.MatchString(msg):
+			if !seen[msg] {
+				seen[msg] = true
+				o.Errors = append(o.Errors, msg)
+			}
+		}
+	}
+	return o, true
+}
+
+// ---------------------------------------------------------------------------
+// GradleStrategy
+// ---------------------------------------------------------------------------
+
+// GradleStrategy filters Gradle builds (`gradle` or `./gradlew`) running
+// build, compile or test tasks. It keeps the output of failed tasks, compiler
+// errors, failing tests with their stack traces, the test count, the failure
+// report (without its "Try" advice) and the BUILD result with its time.
+// Stack traces are condensed by condenseJVMTraces. Daemon and configuration
+// messages, downloads, up-to-date task headers, passing tests and the
+// deprecation notice are dropped.
+type GradleStrategy struct{}
+
+func (s *GradleStrategy) Name() string { return "gradle" }
+
+// gradleTasks lists the task names (without project path) that build or test.
+var gradleTasks = map[string]bool{
+	"build": true, "assemble": true, "check": true, "clean": true,
+	"classes": true, "jar": true, "bootJar": true, "war": true,
+	"shadowJar": true, "installDist": true, "lint": true,
+}
+
+// gradleValueFlags lists Gradle flags that take a separate value.
+var gradleValueFlags = map[string]bool{
+	"-x": true, "--exclude-task": true, "-p": true, "--project-dir": true,
+	"-b": true, "--build-file": true, "-c": true, "--settings-file": true,
+	"-g": true, "--gradle-user-home": true, "-I": true, "--init-script": true,
+	"--tests": true, "--max-workers": true, "--console": true, "--warning-mode": true,
+}
+
+// CanHandle accepts Gradle runs of build, compile and test tasks, with or
+// without a project path (":app:test"). Other tasks (run, bootRun,
+// dependencies, tasks) print what the user asked for and are left alone.
+func (s *GradleStrategy) CanHandle(command string, args []string) bool {
+	if jvmTool(command) != "gradle" {
+		return false
+	}
+	for _, g := range jvmGoals(args, gradleValueFlags) {
+		task := g[strings.LastIndex(g, ":")+1:]
+		if gradleTasks[task] || strings.HasPrefix(task, "compile") ||
+			strings.HasPrefix(task, "test") || strings.HasSuffix(task, "Test") {
+			return true
+		}
+	}
+	return false
+}
+
+// FoldRepeats folds the deprecation warning Gradle prints for every
+// subproject.
+func (s *GradleStrategy) FoldRepeats() {}
+
+// Package-level compiled regexes for GradleStrategy.
+var (
+	// gradleTaskRe matches task headers: "> Task :app:compileJava FAILED".
+	gradleTaskRe = regexp.MustCompile(`^> Task (\S+)(?: (.+))?$`)
+	// gradleNoiseRe matches daemon, configuration, download and javac note lines.
+	gradleNoiseRe = regexp.MustCompile(`^(Starting a Gradle Daemon|Starting \d+ Gradle Daemons?|\d+ (busy|incompatible|stopped) Daemons? could not be reused|Daemon will be stopped at the end|To honour the JVM settings|Reusing configuration cache|Configuration cache entry|Calculating task graph|> Configure project|Downloading https?://|\.+\d+%|Unzipping |Note: |<[-=]*> \d+% )`)
+	// gradleResultRe matches "BUILD SUCCESSFUL in 3s", "BUILD FAILED in 5s" and the task count.
+	gradleResultRe = regexp.MustCompile(`^(BUILD (SUCCESSFUL|FAILED) in |\d+ actionable tasks?:)`)
+	// gradleTestRe matches per-test events: "AppTest > testAdd() FAILED".
+	gradleTestRe = regexp.MustCompile(`^(\S.*?) > (.+) (PASSED|FAILED|SKIPPED|STANDARD_OUT|STANDARD_ERROR)$`)
+	// gradleTestCountRe matches "3 tests completed, 1 failed, 1 skipped".
+	gradleTestCountRe = regexp.MustCompile(`^\d+ tests? completed, `)
+	// gradleDiagRe matches javac/groovyc errors and warnings ("App.java:12: error: msg")
+	// and kotlinc's ("e: file:///src/App.kt:12:5 msg").
+	gradleDiagRe = regexp.MustCompile(`^\S.*\.(java|groovy|scala):\d+: (error|warning): |^(e|w): (file://)?\S+\.kts?:\d+:\d+ `)
+	// gradleSectionRe matches the failure report's section headers: "* What went wrong:".
+	gradleSectionRe = regexp.MustCompile(`^\* (What went wrong|Where|Try|Exception is|Get more help at|Please refer to)\b`)
+)
+
+// ShortenPaths opts in to path shortening: Kotlin and Java errors use
+// file:// and absolute paths.
+func (s *GradleStrategy) ShortenPaths() {}
+
+func (s *GradleStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	keepTask := true // output before the first task header (-q, stderr) is kept
+	inReport := false
+	skipSection := false
+	skipParagraph := false
+	cont := jvmContNone
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Multi-line notices: the release highlights on a new Gradle version
+		// and the deprecation notice
+		if strings.HasPrefix(line, "Welcome to Gradle ") || strings.HasPrefix(line, "Deprecated Gradle features were used") {
+			skipParagraph = true
+		}
+		if skipParagraph {
+			if strings.HasPrefix(line, "For more details see ") || strings.HasPrefix(line, "For more on this, please refer to ") {
+				skipParagraph = false
+			}
+			continue
+		}
+
+		switch {
+		case gradleResultRe.MatchString(line):
+			inReport, skipSection = false, false
+			if strings.HasPrefix(line, "BUILD ") {
+				out = appendBlankSeparated(out, line)
+			} else {
+				out = append(out, line)
+			}
+			continue
+		case strings.HasPrefix(line, "FAILURE: "):
+			inReport, skipSection = true, false
+			out = appendBlankSeparated(out, line)
+			continue
+		}
+
+		if inReport {
+			if m := gradleSectionRe.FindStringSubmatch(line); m != nil {
+				switch m[1] {
+				case "Try", "Get more help at", "Please refer to":
+					skipSection = true
+					continue
+				}
+				skipSection = false
+			}
+			if skipSection {
+				continue
+			}
+			if trimmed == "" {
+				out = appendBlankSeparated(out, "")
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		if trimmed == "" {
+			// Blank lines end a failing test's block
+			cont = jvmContNone
+			continue
+		}
+		if gradleNoiseRe.MatchString(line) {
+			continue
+		}
+		if m := gradleTaskRe.FindStringSubmatch(line); m != nil {
+			keepTask = m[2] == "FAILED"
+			if keepTask {
+				out = append(out, line)
+			}
+			cont = jvmContNone
+			continue
+		}
+		if m := gradleTestRe.FindStringSubmatch(line); m != nil {
+			// The indented lines under a test event are its failure or its
+			// captured output
+			cont = jvmContDrop
+			if m[3] == "FAILED" {
+				out = append(out, line)
+				cont = jvmContKeep
+			}
+			continue
+		}
+		if cont == jvmContDrop && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			cont = jvmContNone
+		}
+		switch {
+		case gradleTestCountRe.MatchString(line):
+			out = append(out, line)
+			cont = jvmContNone
+		case gradleDiagRe.MatchString(line):
+			// Warnings of tasks that succeeded are left to the log
+			if m := gradleDiagRe.FindStringSubmatch(line); keepTask || m[2] == "error" || m[3] == "e" {
+				out = append(out, line)
+				cont = jvmContKeep
+			} else {
+				cont = jvmContDrop
+			}
+		case cont == jvmContKeep, keepTask && cont == jvmContNone:
+			out = append(out, line)
+		}
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	out, _ = condenseJVMTraces(out)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// FilterStderr filters stderr like stdout: Gradle writes compiler errors and
+// its failure report there.
+func (s *GradleStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
+// appendBlankSeparated appends line to out, preceded by a blank line unless
+// out is empty or already ends with one. An empty line is appended the same
+// way, so blank lines never repeat.
+func appendBlankSeparated(out []string, line string) []string {
+	if len(out) > 0 && out[len(out)-1] != "" {
+		out = append(out, "")
+	}
+	if line == "" {
+		return out
+	}
+	return append(out, line)
+}
+
+// isGradleTestTask reports whether the task path (":app:test") names a task
+// that runs tests: test, or a custom one such as integrationTest.
+func isGradleTestTask(path string) bool {
+	task := path[strings.LastIndex(path, ":")+1:]
+	return task == "test" || strings.HasSuffix(task, "Test")
+}
+
+// ParseOutcome extracts failing and passing tests, test tasks and compiler
+// errors. Test ids are the class followed by the test, e.g.
+// "com.example.AppTest testAdd()". Gradle only prints passing tests when
+// testLogging includes passed events, so test tasks are recorded by path
+// (":app:test") as well, like go test packages: a task that passed (or was
+// up to date), or one that failed without a failing test, e.g. a crashed
+// test JVM.
+func (s *GradleStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	seen := map[string]bool{}
+	passed := map[string]bool{}
+	var tasks []string
+	taskStatus := map[string]string{} // last status seen: "", FAILED, UP-TO-DATE, ...
+	failingTasks := map[string]bool{} // test tasks with a failing test
+	task := ""
+	for _, line := range strings.Split(StripANSIString(string(raw)), "\n") {
+		if m := gradleTaskRe.FindStringSubmatch(line); m != nil {
+			task = m[1]
+			if isGradleTestTask(task) {
+				if _, ok := taskStatus[task]; !ok {
+					tasks = append(tasks, task)
+				}
+				taskStatus[task] = m[2]
+			}
+		} else if m := gradleTestRe.FindStringSubmatch(line); m != nil {
+			id := m[1] + " " + m[2]
+			switch {
+			case m[3] == "FAILED" && !seen[id]:
+				seen[id] = true
+				o.Failed = append(o.Failed, id)
+				failingTasks[task] = true
+			case m[3] == "PASSED" && !passed[id]:
+				passed[id] = true
+				o.Passed = append(o.Passed, id)
+			}
+		} else if m := gradleDiagRe.FindStringSubmatch(line); m != nil && (m[2] == "error" || m[3] == "e") {
+			if !seen[line] {
+				seen[line] = true
+				o.Errors = append(o.Errors, line)
+			}
+		}
+	}
+	for _, t := range tasks {
+		switch taskStatus[t] {
+		case "", "UP-TO-DATE", "FROM-CACHE":
+			o.Passed = append(o.Passed, t)
+		case "FAILED":
+			if !failingTasks[t] {
+				o.Failed = append(o.Failed, t)
+			}
+		}
+	}
+	return o, true
+}
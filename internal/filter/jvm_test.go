@@ -0,0 +1,422 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// MavenStrategy
+// ---------------------------------------------------------------------------
+
+func TestMavenStrategy_CanHandle(t *testing.T) {
+	s := &MavenStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"mvn test", "mvn", []string{"test"}, true},
+		{"mvn clean install", "mvn", []string{"-B", "clean", "install"}, true},
+		{"mvnw wrapper", "./mvnw", []string{"verify"}, true},
+		{"mvnw.cmd wrapper", "mvnw.cmd", []string{"package"}, true},
+		{"surefire goal", "mvn", []string{"surefire:test"}, true},
+		{"module list value", "mvn", []string{"-pl", "web", "test"}, true},
+		{"dependency tree", "mvn", []string{"dependency:tree"}, false},
+		{"spring-boot run", "mvn", []string{"spring-boot:run"}, false},
+		{"version", "mvn", []string{"-v"}, false},
+		{"gradle", "gradle", []string{"test"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMavenStrategy_Name(t *testing.T) {
+	if got := (&MavenStrategy{}).Name(); got != "maven" {
+		t.Errorf("Name() = %q, want %q", got, "maven")
+	}
+}
+
+// mavenCompileFailure is `mvn compile` failing on a missing symbol: Maven
+// prints the error in the compiler block and again in its failure report.
+const mavenCompileFailure = `[INFO] Scanning for projects...
+Downloading from central: https://repo.maven.apache.org/maven2/org/apache/maven/plugins/maven-compiler-plugin/3.11.0/maven-compiler-plugin-3.11.0.pom
+Downloaded from central: https://repo.maven.apache.org/maven2/org/apache/maven/plugins/maven-compiler-plugin/3.11.0/maven-compiler-plugin-3.11.0.pom (9.8 kB at 120 kB/s)
+[INFO]
+[INFO] --------------------------< com.example:demo >--------------------------
+[INFO] Building demo 0.0.1-SNAPSHOT
+[INFO]   from pom.xml
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO]
+[INFO] --- resources:3.3.1:resources (default-resources) @ demo ---
+[INFO] Copying 1 resource from src/main/resources to target/classes
+[INFO]
+[INFO] --- compiler:3.11.0:compile (default-compile) @ demo ---
+[INFO] Changes detected - recompiling the module! :source
+[INFO] Compiling 5 source files with javac [debug target 17] to target/classes
+[WARNING] /work/demo/src/main/java/com/example/Old.java:[8,12] getValue() in com.example.Legacy has been deprecated
+[INFO] -------------------------------------------------------------
+[ERROR] COMPILATION ERROR :
+[INFO] -------------------------------------------------------------
+[ERROR] /work/demo/src/main/java/com/example/Foo.java:[12,8] cannot find symbol
+  symbol:   class Bar
+  location: class com.example.Foo
+[INFO] 1 error
+[INFO] -------------------------------------------------------------
+[INFO] ------------------------------------------------------------------------
+[INFO] BUILD FAILURE
+[INFO] ------------------------------------------------------------------------
+[INFO] Total time:  2.345 s
+[INFO] Finished at: 2026-02-12T14:30:22Z
+[INFO] ------------------------------------------------------------------------
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.11.0:compile (default-compile) on project demo: Compilation failure
+[ERROR] /work/demo/src/main/java/com/example/Foo.java:[12,8] cannot find symbol
+[ERROR]   symbol:   class Bar
+[ERROR]   location: class com.example.Foo
+[ERROR]
+[ERROR] -> [Help 1]
+[ERROR]
+[ERROR] To see the full stack trace of the errors, re-run Maven with the -e switch.
+[ERROR] Re-run Maven using the -X switch to enable full debug logging.
+[ERROR]
+[ERROR] For more information about the errors and possible solutions, please read the following articles:
+[ERROR] [Help 1] http://cwiki.apache.org/confluence/display/MAVEN/MojoFailureException
+`
+
+func TestMavenStrategy_Filter_CompileFailure(t *testing.T) {
+	s := &MavenStrategy{}
+	result := s.Filter([]byte(mavenCompileFailure), "mvn", []string{"compile"}, 1)
+
+	want := `[WARNING] /work/demo/src/main/java/com/example/Old.java:[8,12] getValue() in com.example.Legacy has been deprecated
+[ERROR] /work/demo/src/main/java/com/example/Foo.java:[12,8] cannot find symbol
+  symbol:   class Bar
+  location: class com.example.Foo
+[INFO] BUILD FAILURE
+[INFO] Total time:  2.345 s
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-compiler-plugin:3.11.0:compile (default-compile) on project demo: Compilation failure
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+// mavenTestFailure is a multi-module `mvn test` with one failing test in the
+// second module; passing test classes print application logs.
+const mavenTestFailure = `[INFO] Reactor Build Order:
+[INFO]
+[INFO] core                                                               [jar]
+[INFO] web                                                                [jar]
+[INFO] api                                                                [jar]
+[INFO]
+[INFO] --- surefire:3.2.2:test (default-test) @ core ---
+[INFO] -------------------------------------------------------
+[INFO]  T E S T S
+[INFO] -------------------------------------------------------
+[INFO] Running com.example.core.ParserTest
+12:00:01.123 [main] INFO com.example.core.Parser -- parsing 3 records
+[INFO] Tests run: 4, Failures: 0, Errors: 0, Skipped: 0, Time elapsed: 0.041 s -- in com.example.core.ParserTest
+[INFO]
+[INFO] Results:
+[INFO]
+[INFO] Tests run: 4, Failures: 0, Errors: 0, Skipped: 0
+[INFO]
+[INFO] --- surefire:3.2.2:test (default-test) @ web ---
+[INFO] Running com.example.web.RouterTest
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.052 s <<< FAILURE! -- in com.example.web.RouterTest
+[ERROR] com.example.web.RouterTest.routesHome -- Time elapsed: 0.012 s <<< FAILURE!
+org.opentest4j.AssertionFailedError: expected: <200> but was: <404>
+	at org.junit.jupiter.api.AssertionFailureBuilder.build(AssertionFailureBuilder.java:151)
+	at org.junit.jupiter.api.AssertEquals.assertEquals(AssertEquals.java:150)
+	at com.example.web.RouterTest.routesHome(RouterTest.java:21)
+
+[INFO]
+[INFO] Results:
+[INFO]
+[ERROR] Failures:
+[ERROR]   RouterTest.routesHome:21 expected: <200> but was: <404>
+[INFO]
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0
+[INFO]
+[INFO] ------------------------------------------------------------------------
+[INFO] Reactor Summary for parent 1.0.0:
+[INFO]
+[INFO] core ............................................... SUCCESS [  1.234 s]
+[INFO] web ................................................ FAILURE [  0.917 s]
+[INFO] api ................................................ SKIPPED
+[INFO] ------------------------------------------------------------------------
+[INFO] BUILD FAILURE
+[INFO] ------------------------------------------------------------------------
+[INFO] Total time:  3.101 s
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-surefire-plugin:3.2.2:test (default-test) on project web: There are test failures.
+[ERROR]
+[ERROR] After correcting the problems, you can resume the build with the command
+[ERROR]   mvn <args> -rf :web
+`
+
+func TestMavenStrategy_Filter_TestFailure(t *testing.T) {
+	s := &MavenStrategy{}
+	result := s.Filter([]byte(mavenTestFailure), "mvn", []string{"test"}, 1)
+
+	want := `[INFO] Tests run: 4, Failures: 0, Errors: 0, Skipped: 0
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0, Time elapsed: 0.052 s <<< FAILURE! -- in com.example.web.RouterTest
+[ERROR] com.example.web.RouterTest.routesHome -- Time elapsed: 0.012 s <<< FAILURE!
+org.opentest4j.AssertionFailedError: expected: <200> but was: <404>
+	at org.junit.jupiter.api.AssertionFailureBuilder.build(AssertionFailureBuilder.java:151)
+	at org.junit.jupiter.api.AssertEquals.assertEquals(AssertEquals.java:150)
+	at com.example.web.RouterTest.routesHome(RouterTest.java:21)
+[ERROR] Failures:
+[ERROR]   RouterTest.routesHome:21 expected: <200> but was: <404>
+[ERROR] Tests run: 2, Failures: 1, Errors: 0, Skipped: 0
+[INFO] web ................................................ FAILURE [  0.917 s]
+[INFO] api ................................................ SKIPPED
+[INFO] BUILD FAILURE
+[INFO] Total time:  3.101 s
+[ERROR] Failed to execute goal org.apache.maven.plugins:maven-surefire-plugin:3.2.2:test (default-test) on project web: There are test failures.
+[ERROR] After correcting the problems, you can resume the build with the command
+[ERROR]   mvn <args> -rf :web
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestMavenStrategy_Filter_NotABuildLog(t *testing.T) {
+	s := &MavenStrategy{}
+	var b strings.Builder
+	for i := range 12 {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	b.WriteString("Error: JAVA_HOME is not defined correctly.\n")
+
+	result := s.Filter([]byte(b.String()), "mvn", []string{"test"}, 1)
+	if !strings.Contains(result.Filtered, "Error: JAVA_HOME is not defined correctly.\n") || strings.Contains(result.Filtered, "line 3") {
+		t.Errorf("expected generic error fallback, got:\n%s", result.Filtered)
+	}
+}
+
+func TestMavenStrategy_ParseOutcome(t *testing.T) {
+	s := &MavenStrategy{}
+	o, ok := s.ParseOutcome([]byte(mavenTestFailure+mavenCompileFailure), "mvn", []string{"test"}, 1)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if got := strings.Join(o.Failed, "\n"); got != "com.example.web.RouterTest routesHome" {
+		t.Errorf("Failed = %q", got)
+	}
+	if got := strings.Join(o.Passed, "\n"); got != "com.example.core.ParserTest" {
+		t.Errorf("Passed = %q", got)
+	}
+	if got := strings.Join(o.Errors, "\n"); got != "/work/demo/src/main/java/com/example/Foo.java:[12,8] cannot find symbol" {
+		t.Errorf("Errors = %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// GradleStrategy
+// ---------------------------------------------------------------------------
+
+func TestGradleStrategy_CanHandle(t *testing.T) {
+	s := &GradleStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"gradle build", "gradle", []string{"build"}, true},
+		{"gradlew test", "./gradlew", []string{"test"}, true},
+		{"gradlew.bat", `gradlew.bat`, []string{"check"}, true},
+		{"project task", "./gradlew", []string{":app:compileJava"}, true},
+		{"integration tests", "./gradlew", []string{"integrationTest"}, true},
+		{"android unit tests", "./gradlew", []string{"testDebugUnitTest"}, true},
+		{"excluded task value", "./gradlew", []string{"run", "-x", "test"}, false},
+		{"bootRun", "./gradlew", []string{"bootRun"}, false},
+		{"dependencies", "./gradlew", []string{"dependencies"}, false},
+		{"mvn", "mvn", []string{"test"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGradleStrategy_Name(t *testing.T) {
+	if got := (&GradleStrategy{}).Name(); got != "gradle" {
+		t.Errorf("Name() = %q, want %q", got, "gradle")
+	}
+}
+
+// gradleTestFailure is `./gradlew build` with a failing test, stdout and
+// stderr interleaved as a terminal would show them.
+const gradleTestFailure = `Starting a Gradle Daemon (subsequent builds will be faster)
+> Task :app:compileJava
+Note: Some input files use unchecked or unsafe operations.
+Note: Recompile with -Xlint:unchecked for details.
+> Task :app:processResources NO-SOURCE
+> Task :app:classes
+> Task :app:compileTestJava UP-TO-DATE
+> Task :app:test
+
+AppTest > greets() PASSED
+
+AppTest > routesHome() FAILED
+    org.opentest4j.AssertionFailedError: expected: <200> but was: <404>
+        at app//org.junit.jupiter.api.AssertionFailureBuilder.build(AssertionFailureBuilder.java:151)
+        at app//com.example.AppTest.routesHome(AppTest.java:21)
+
+2 tests completed, 1 failed
+
+> Task :app:test FAILED
+
+FAILURE: Build failed with an exception.
+
+* What went wrong:
+Execution failed for task ':app:test'.
+> There were failing tests. See the report at: file:///work/app/build/reports/tests/test/index.html
+
+* Try:
+> Run with --scan to get full insights.
+
+* Get more help at https://help.gradle.org
+
+Deprecated Gradle features were used in this build, making it incompatible with Gradle 9.0.
+
+You can use '--warning-mode all' to show the individual deprecation warnings and determine if they come from your own scripts or plugins.
+
+For more on this, please refer to https://docs.gradle.org/8.5/userguide/command_line_interface.html#sec:command_line_warnings in the Gradle documentation.
+
+BUILD FAILED in 7s
+4 actionable tasks: 3 executed, 1 up-to-date
+`
+
+func TestGradleStrategy_Filter_TestFailure(t *testing.T) {
+	s := &GradleStrategy{}
+	result := s.Filter([]byte(gradleTestFailure), "./gradlew", []string{"build"}, 1)
+
+	want := `AppTest > routesHome() FAILED
+    org.opentest4j.AssertionFailedError: expected: <200> but was: <404>
+        at app//org.junit.jupiter.api.AssertionFailureBuilder.build(AssertionFailureBuilder.java:151)
+        at app//com.example.AppTest.routesHome(AppTest.java:21)
+2 tests completed, 1 failed
+> Task :app:test FAILED
+
+FAILURE: Build failed with an exception.
+
+* What went wrong:
+Execution failed for task ':app:test'.
+> There were failing tests. See the report at: file:///work/app/build/reports/tests/test/index.html
+
+BUILD FAILED in 7s
+4 actionable tasks: 3 executed, 1 up-to-date
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestGradleStrategy_Filter_CompileErrors(t *testing.T) {
+	s := &GradleStrategy{}
+	input := `> Task :lib:compileJava
+/work/app/lib/src/main/java/lib/Util.java:4: warning: [deprecation] Date(int,int,int) in Date has been deprecated
+        return new Date(1, 2, 3);
+               ^
+1 warning
+> Task :app:compileJava FAILED
+/work/app/app/src/main/java/app/App.java:12: error: cannot find symbol
+        Foo foo = new Foo();
+        ^
+  symbol:   class Foo
+  location: class App
+1 error
+
+FAILURE: Build failed with an exception.
+
+* What went wrong:
+Execution failed for task ':app:compileJava'.
+> Compilation failed; see the compiler error output for details.
+
+* Try:
+> Run with --info option to get more log output.
+
+BUILD FAILED in 2s
+`
+	result := s.FilterStderr([]byte(input), "gradle", []string{"build"}, 1)
+
+	want := `> Task :app:compileJava FAILED
+/work/app/app/src/main/java/app/App.java:12: error: cannot find symbol
+        Foo foo = new Foo();
+        ^
+  symbol:   class Foo
+  location: class App
+1 error
+
+FAILURE: Build failed with an exception.
+
+* What went wrong:
+Execution failed for task ':app:compileJava'.
+> Compilation failed; see the compiler error output for details.
+
+BUILD FAILED in 2s
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestGradleStrategy_ParseOutcome(t *testing.T) {
+	s := &GradleStrategy{}
+	input := gradleTestFailure + "e: file:///work/app/src/main/kotlin/App.kt:12:5 Unresolved reference: foo\n"
+	o, ok := s.ParseOutcome([]byte(input), "gradle", []string{"build"}, 1)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if got := strings.Join(o.Failed, "\n"); got != "AppTest routesHome()" {
+		t.Errorf("Failed = %q", got)
+	}
+	if got := strings.Join(o.Passed, "\n"); got != "AppTest greets()" {
+		t.Errorf("Passed = %q", got)
+	}
+	if got := strings.Join(o.Errors, "\n"); got != "e: file:///work/app/src/main/kotlin/App.kt:12:5 Unresolved reference: foo" {
+		t.Errorf("Errors = %q", got)
+	}
+}
+
+func TestGradleStrategy_ParseOutcome_TestTasks(t *testing.T) {
+	s := &GradleStrategy{}
+	input := `> Task :app:compileJava
+> Task :app:test
+> Task :lib:test UP-TO-DATE
+> Task :app:integrationTest
+Could not start worker process: java.lang.OutOfMemoryError
+> Task :app:integrationTest FAILED
+
+BUILD FAILED in 9s
+`
+	o, _ := s.ParseOutcome([]byte(input), "gradle", []string{"check"}, 1)
+	if got := strings.Join(o.Passed, ","); got != ":app:test,:lib:test" {
+		t.Errorf("Passed = %q", got)
+	}
+	// A task failing without a failing test is recorded itself
+	if got := strings.Join(o.Failed, ","); got != ":app:integrationTest" {
+		t.Errorf("Failed = %q", got)
+	}
+}
@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// JVM stack trace condenser
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for JVM stack traces.
+var (
+	// jvmFrameRe matches a stack frame: "\tat com.example.Foo.bar(Foo.java:12)",
+	// including class loader and module prefixes ("at app//org.junit.Assert...",
+	// "at java.base/java.lang.Thread.run").
+	jvmFrameRe = regexp.MustCompile(`^(\s+)at (\S+)\(.*\)$`)
+	// jvmMoreRe matches the "... 42 more" line ending a nested trace.
+	jvmMoreRe = regexp.MustCompile(`^\s+\.\.\. \d+ more$`)
+	// jvmCauseRe matches the headers of nested exceptions in a trace.
+	jvmCauseRe = regexp.MustCompile(`^\s*(Caused by|Suppressed|Wrapped by): `)
+)
+
+const (
+	// jvmMaxFrames caps the project frames kept per exception.
+	jvmMaxFrames = 8
+	// frameGroupsShown caps the package groups named in a folded frames line.
+	frameGroupsShown = 3
+)
+
+// jvmLibraryPrefixes lists the JDK and common library packages whose frames
+// are folded when COC_JVM_PACKAGES doesn't name the project's own packages.
+var jvmLibraryPrefixes = []string{
+	"java.", "javax.", "jakarta.", "jdk.", "sun.", "com.sun.",
+	"kotlin.", "kotlinx.", "scala.", "groovy.", "org.codehaus.groovy.",
+	"org.springframework.", "org.junit.", "junit.", "org.opentest4j.",
+	"org.testng.", "org.mockito.", "org.assertj.", "org.hamcrest.",
+	"net.bytebuddy.", "org.apache.", "org.eclipse.", "org.hibernate.",
+	"org.gradle.", "worker.org.gradle.", "com.google.", "com.fasterxml.",
+	"io.netty.", "reactor.", "io.micrometer.", "io.opentelemetry.",
+	"io.quarkus.", "io.micronaut.", "io.grpc.", "okhttp3.", "org.slf4j.",
+	"ch.qos.logback.", "com.zaxxer.", "org.aspectj.", "org.flywaydb.",
+}
+
+// jvmProjectPackages returns the project's package prefixes from
+// COC_JVM_PACKAGES (comma-separated, e.g. "com.acme,org.acme.billing"), or
+// nil when it isn't set.
+func jvmProjectPackages() []string {
+	var packages []string
+	for _, p := range strings.Split(os.Getenv("COC_JVM_PACKAGES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			packages = append(packages, p)
+		}
+	}
+	return packages
+}
+
+// isJVMProjectFrame reports whether the frame's qualified method belongs to
+// the project: one of packages when given, otherwise anything outside the JDK
+// and the libraries in jvmLibraryPrefixes.
+func isJVMProjectFrame(method string, packages []string) bool {
+	if len(packages) > 0 {
+		for _, p := range packages {
+			if method == p || strings.HasPrefix(method, strings.TrimSuffix(p, ".")+".") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range jvmLibraryPrefixes {
+		if strings.HasPrefix(method, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// jvmFrameMethod returns the frame's indentation and qualified method with
+// any class loader or module prefix removed.
+func jvmFrameMethod(line string) (indent, method string, ok bool) {
+	m := jvmFrameRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	method = m[2]
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		method = method[i+1:]
+	}
+	return m[1], method, true
+}
+
+// jvmPackageGroup names the group a folded frame is counted under: the first
+// two elements of its package, e.g. "org.springframework".
+func jvmPackageGroup(method string) string {
+	parts := strings.SplitN(method, ".", 3)
+	if len(parts) < 3 {
+		return parts[0]
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// jvmTrace is one stack trace: the exception line and the frames, nested
+// "Caused by:" headers and "... N more" lines below it.
+type jvmTrace struct {
+	header string
+	body   []string
+}
+
+// condenseJVMTraces condenses each JVM stack trace in lines: exception and
+// "Caused by:" headers and project frames are kept, the first frame of each
+// exception is kept so the throw site stays visible, and runs of other frames
+// are folded into "… N frames in org.springframework" lines. A trace identical
+// to an earlier one is reduced to its exception line and a note. It reports
+// whether any trace was found.
+func condenseJVMTraces(lines []string) ([]string, bool) {
+	packages := jvmProjectPackages()
+	seen := map[string]bool{}
+	var out []string
+	found := false
+	for i := 0; i < len(lines); {
+		if i+1 >= len(lines) || !jvmFrameRe.MatchString(lines[i+1]) || jvmFrameRe.MatchString(lines[i]) {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+
+		t := jvmTrace{header: lines[i]}
+		j := i + 1
+		for j < len(lines) && (jvmFrameRe.MatchString(lines[j]) || jvmMoreRe.MatchString(lines[j]) || jvmCauseRe.MatchString(lines[j])) {
+			t.body = append(t.body, lines[j])
+			j++
+		}
+		i = j
+		found = true
+
+		key := t.header + "\n" + strings.Join(t.body, "\n")
+		if seen[key] {
+			indent, _, _ := jvmFrameMethod(t.body[0])
+			out = append(out, t.header, indent+"… same stack trace as above")
+			continue
+		}
+		seen[key] = true
+		out = append(out, t.header)
+		out = append(out, renderJVMTraceBody(t.body, packages)...)
+	}
+	return out, found
+}
+
+// renderJVMTraceBody keeps the body's headers and project frames and folds
+// the other frames. A fold of a single frame keeps the frame itself.
+func renderJVMTraceBody(body []string, packages []string) []string {
+	var out []string
+	var folded []string // original lines of the current fold
+	var groups []string
+	foldIndent := ""
+	flush := func() {
+		switch {
+		case len(folded) == 1:
+			out = append(out, folded[0])
+		case len(folded) > 1:
+			out = append(out, fmt.Sprintf("%s… %d frames in %s", foldIndent, len(folded), frameGroupsLabel(groups)))
+		}
+		folded, groups = nil, nil
+	}
+
+	first := true // the next frame is the first of its exception
+	kept := 0
+	for _, line := range body {
+		indent, method, ok := jvmFrameMethod(line)
+		if !ok {
+			flush()
+			out = append(out, line)
+			first, kept = true, 0
+			continue
+		}
+		if first || (kept < jvmMaxFrames && isJVMProjectFrame(method, packages)) {
+			flush()
+			out = append(out, line)
+			if !first {
+				kept++
+			}
+			first = false
+			continue
+		}
+		if len(folded) == 0 {
+			foldIndent = indent
+		}
+		folded = append(folded, line)
+		if g := jvmPackageGroup(method); !slices.Contains(groups, g) {
+			groups = append(groups, g)
+		}
+	}
+	flush()
+	return out
+}
+
+// frameGroupsLabel lists the package groups of folded frames, at most
+// frameGroupsShown of them.
+func frameGroupsLabel(groups []string) string {
+	if len(groups) > frameGroupsShown {
+		return strings.Join(groups[:frameGroupsShown], ", ") + fmt.Sprintf(" +%d more", len(groups)-frameGroupsShown)
+	}
+	return strings.Join(groups, ", ")
+}
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// springTrace is a Spring Boot startup failure with a two-level cause chain.
+const springTrace = `2026-02-12 14:30:22.101 ERROR 4242 --- [main] o.s.boot.SpringApplication : Application run failed
+org.springframework.beans.factory.BeanCreationException: Error creating bean with name 'orderService'
+	at org.springframework.beans.factory.support.AbstractAutowireCapableBeanFactory.initializeBean(AbstractAutowireCapableBeanFactory.java:1786)
+	at org.springframework.beans.factory.support.AbstractAutowireCapableBeanFactory.doCreateBean(AbstractAutowireCapableBeanFactory.java:600)
+	at org.springframework.beans.factory.support.AbstractAutowireCapableBeanFactory.createBean(AbstractAutowireCapableBeanFactory.java:522)
+	at org.springframework.beans.factory.support.AbstractBeanFactory.getBean(AbstractBeanFactory.java:200)
+	at org.springframework.boot.SpringApplication.run(SpringApplication.java:325)
+	at com.acme.shop.ShopApplication.main(ShopApplication.java:10)
+Caused by: java.lang.IllegalStateException: Failed to load pricing rules
+	at com.acme.shop.pricing.RuleLoader.load(RuleLoader.java:41)
+	at com.acme.shop.order.OrderService.init(OrderService.java:28)
+	at java.base/jdk.internal.reflect.DirectMethodHandleAccessor.invoke(DirectMethodHandleAccessor.java:103)
+	at java.base/java.lang.reflect.Method.invoke(Method.java:580)
+	at org.springframework.beans.factory.annotation.InitDestroyAnnotationBeanPostProcessor$LifecycleMethod.invoke(InitDestroyAnnotationBeanPostProcessor.java:457)
+	... 5 more
+Caused by: java.io.FileNotFoundException: rules.yaml (No such file or directory)
+	at java.base/java.io.FileInputStream.open0(Native Method)
+	at java.base/java.io.FileInputStream.open(FileInputStream.java:213)
+	at java.base/java.io.FileInputStream.<init>(FileInputStream.java:152)
+	at com.acme.shop.pricing.RuleLoader.load(RuleLoader.java:37)
+	... 9 more
+`
+
+func TestCondenseJVMTraces(t *testing.T) {
+	t.Setenv("COC_JVM_PACKAGES", "")
+	got, found := condenseJVMTraces(strings.Split(springTrace, "\n"))
+	if !found {
+		t.Fatal("expected a trace")
+	}
+
+	want := `2026-02-12 14:30:22.101 ERROR 4242 --- [main] o.s.boot.SpringApplication : Application run failed
+org.springframework.beans.factory.BeanCreationException: Error creating bean with name 'orderService'
+	at org.springframework.beans.factory.support.AbstractAutowireCapableBeanFactory.initializeBean(AbstractAutowireCapableBeanFactory.java:1786)
+	… 4 frames in org.springframework
+	at com.acme.shop.ShopApplication.main(ShopApplication.java:10)
+Caused by: java.lang.IllegalStateException: Failed to load pricing rules
+	at com.acme.shop.pricing.RuleLoader.load(RuleLoader.java:41)
+	at com.acme.shop.order.OrderService.init(OrderService.java:28)
+	… 3 frames in jdk.internal, java.lang, org.springframework
+	... 5 more
+Caused by: java.io.FileNotFoundException: rules.yaml (No such file or directory)
+	at java.base/java.io.FileInputStream.open0(Native Method)
+	… 2 frames in java.io
+	at com.acme.shop.pricing.RuleLoader.load(RuleLoader.java:37)
+	... 9 more
+`
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("condensed mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestCondenseJVMTraces_ProjectPackages(t *testing.T) {
+	// With explicit packages, only those count as project frames
+	t.Setenv("COC_JVM_PACKAGES", "com.acme.shop.order")
+	got, _ := condenseJVMTraces(strings.Split(springTrace, "\n"))
+	s := strings.Join(got, "\n")
+
+	if strings.Contains(s, "RuleLoader.load(RuleLoader.java:37)") {
+		t.Errorf("frame outside COC_JVM_PACKAGES should be folded, got:\n%s", s)
+	}
+	if !strings.Contains(s, "\tat com.acme.shop.order.OrderService.init(OrderService.java:28)\n") {
+		t.Errorf("project frame should be kept, got:\n%s", s)
+	}
+	if !strings.Contains(s, "\t… 5 frames in org.springframework, com.acme\n") {
+		t.Errorf("expected folded frames naming both groups, got:\n%s", s)
+	}
+}
+
+func TestCondenseJVMTraces_DedupesIdenticalTraces(t *testing.T) {
+	trace := "java.lang.NullPointerException: Cannot invoke \"String.length()\" because \"name\" is null\n" +
+		"\tat com.acme.Greeter.greet(Greeter.java:9)\n" +
+		"\tat java.base/java.lang.Thread.run(Thread.java:1583)"
+	input := "request 1 failed\n" + trace + "\nrequest 2 failed\n" + trace + "\n"
+
+	got, _ := condenseJVMTraces(strings.Split(input, "\n"))
+
+	want := "request 1 failed\n" + trace + "\nrequest 2 failed\n" +
+		"java.lang.NullPointerException: Cannot invoke \"String.length()\" because \"name\" is null\n" +
+		"\t… same stack trace as above\n"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("condensed mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestCondenseJVMTraces_NoTrace(t *testing.T) {
+	lines := []string{"at the end of the day", "  at noon (approximately)", "done"}
+	got, found := condenseJVMTraces(lines)
+	if found {
+		t.Error("expected no trace")
+	}
+	if strings.Join(got, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("lines should be unchanged, got %q", got)
+	}
+}
+
+func TestIsJVMProjectFrame(t *testing.T) {
+	tests := []struct {
+		method   string
+		packages []string
+		want     bool
+	}{
+		{"com.acme.shop.Main.main", nil, true},
+		{"org.springframework.boot.SpringApplication.run", nil, false},
+		{"java.lang.Thread.run", nil, false},
+		{"com.acme.shop.Main.main", []string{"com.acme"}, true},
+		{"com.acme.shop.Main.main", []string{"com.acme."}, true},
+		{"com.acmeother.Main.main", []string{"com.acme"}, false},
+		{"org.springframework.boot.SpringApplication.run", []string{"org.springframework"}, true},
+	}
+	for _, tc := range tests {
+		if got := isJVMProjectFrame(tc.method, tc.packages); got != tc.want {
+			t.Errorf("isJVMProjectFrame(%q, %v) = %v, want %v", tc.method, tc.packages, got, tc.want)
+		}
+	}
+}
+
+func TestGenericErrorStrategy_Filter_CondensesJVMTrace(t *testing.T) {
+	t.Setenv("COC_JVM_PACKAGES", "")
+	s := &GenericErrorStrategy{}
+	input := "  .   ____          _            __ _ _\n" + springTrace
+
+	result := s.Filter([]byte(input), "java", []string{"-jar", "shop.jar"}, 1)
+
+	if !strings.Contains(result.Filtered, "\t… 4 frames in org.springframework\n") {
+		t.Errorf("expected condensed trace, got:\n%s", result.Filtered)
+	}
+	if !strings.HasPrefix(result.Filtered, "  .   ____") {
+		t.Errorf("output around the trace should be kept, got:\n%s", result.Filtered)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
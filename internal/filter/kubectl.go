@@ -0,0 +1,340 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// kubectlValueFlags are kubectl global and common per-command flags that
+// consume the next argument as a value.
+var kubectlValueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "--context": true, "--cluster": true,
+	"--user": true, "--kubeconfig": true, "-s": true, "--server": true,
+	"--token": true, "--as": true, "--as-group": true, "--request-timeout": true,
+	"--cache-dir": true, "--certificate-authority": true,
+	"--client-certificate": true, "--client-key": true, "--tls-server-name": true,
+	"-o": true, "--output": true, "-l": true, "--selector": true,
+	"--field-selector": true, "--sort-by": true, "-c": true, "--container": true,
+	"--since": true, "--since-time": true, "--tail": true,
+}
+
+// isKubectl reports whether command is kubectl, invoked by name or by path.
+func isKubectl(command string) bool {
+	return filepath.Base(command) == "kubectl"
+}
+
+// kubectlOutputFormat returns the value of -o/--output in args, or "" when
+// the default table format is used.
+func kubectlOutputFormat(args []string) string {
+	for i, a := range args {
+		switch {
+		case a == "-o" || a == "--output":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		case strings.HasPrefix(a, "--output="):
+			return strings.TrimPrefix(a, "--output=")
+		case strings.HasPrefix(a, "-o="):
+			return strings.TrimPrefix(a, "-o=")
+		case strings.HasPrefix(a, "-o") && len(a) > 2:
+			return a[2:]
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// KubectlGetStrategy
+// ---------------------------------------------------------------------------
+
+// KubectlGetStrategy summarizes `kubectl get` tables by status: rows that are
+// not Running or not Ready are kept, healthy rows are only counted. Structured
+// output (-o yaml, json, jsonpath, name) is left to the generic strategy.
+type KubectlGetStrategy struct{}
+
+func (s *KubectlGetStrategy) Name() string { return "kubectl-get" }
+
+func (s *KubectlGetStrategy) CanHandle(command string, args []string) bool {
+	if !isKubectl(command) || !isSubcommand(args, "get", kubectlValueFlags) {
+		return false
+	}
+	format := kubectlOutputFormat(args)
+	return format == "" || format == "wide"
+}
+
+// Package-level compiled regexes for KubectlGetStrategy.
+var (
+	// kubeHeaderRe matches a table header: "NAME  READY  STATUS" or "NAMESPACE  NAME  ...".
+	kubeHeaderRe = regexp.MustCompile(`^(?:NAMESPACE|NAME)\s{2,}[A-Z][A-Z0-9 ()/-]*$`)
+	// kubeReadyRe matches a READY value such as "1/2".
+	kubeReadyRe = regexp.MustCompile(`^(\d+)/(\d+)$`)
+)
+
+// kubeHealthyStatuses are STATUS values of resources that need no attention.
+var kubeHealthyStatuses = map[string]bool{
+	"Running": true, "Completed": true, "Succeeded": true, "Ready": true,
+	"Active": true, "Bound": true, "Available": true, "Complete": true,
+}
+
+// kubeTable is one table of `kubectl get` output.
+type kubeTable struct {
+	tableColumns
+	header string
+	rows   []string
+}
+
+func newKubeTable(header string) *kubeTable {
+	return &kubeTable{tableColumns: newTableColumns(header), header: header}
+}
+
+// rowState returns the state a row is counted under and whether it is healthy.
+// STATUS decides when present; a READY count ("1/2", or "2" against DESIRED
+// for replica sets) that is not full makes any row unhealthy.
+func (t *kubeTable) rowState(row string) (string, bool) {
+	status := t.field(row, "STATUS")
+	healthy := status == "" || kubeHealthyStatuses[status]
+
+	ready := t.field(row, "READY")
+	notReady := false
+	if m := kubeReadyRe.FindStringSubmatch(ready); m != nil {
+		// Completed pods report 0/1: their containers have exited
+		notReady = m[1] != m[2] && status != "Completed" && status != "Succeeded"
+	} else if desired := t.field(row, "DESIRED"); desired != "" && ready != "" {
+		notReady = ready != desired
+	}
+
+	switch {
+	case status == "" && notReady:
+		return "not ready", false
+	case status == "":
+		return "ready", true
+	case notReady && healthy:
+		return status + " (not ready)", false
+	}
+	return status, healthy && !notReady
+}
+
+// render keeps the header and unhealthy rows and appends a status summary.
+// Tables without a STATUS or READY column are kept as is.
+func (t *kubeTable) render() []string {
+	if !t.has("STATUS") && !t.has("READY") {
+		return append([]string{t.header}, t.rows...)
+	}
+
+	counts := map[string]int{}
+	var states []string
+	var unhealthy []string
+	for _, row := range t.rows {
+		state, healthy := t.rowState(row)
+		if counts[state] == 0 {
+			states = append(states, state)
+		}
+		counts[state]++
+		if !healthy {
+			unhealthy = append(unhealthy, row)
+		}
+	}
+	sort.SliceStable(states, func(i, j int) bool { return counts[states[i]] > counts[states[j]] })
+
+	parts := make([]string, len(states))
+	for i, st := range states {
+		parts[i] = fmt.Sprintf("%d %s", counts[st], st)
+	}
+	summary := fmt.Sprintf("%d total: %s", len(t.rows), strings.Join(parts, ", "))
+
+	var out []string
+	if len(unhealthy) > 0 {
+		out = append(out, t.header)
+		out = append(out, unhealthy...)
+	}
+	return append(out, summary)
+}
+
+func (s *KubectlGetStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	// `kubectl get all` prints one table per kind, separated by blank lines
+	var out []string
+	var table *kubeTable
+	flush := func() {
+		if table != nil {
+			out = append(out, table.render()...)
+			table = nil
+		}
+	}
+	for _, line := range lines {
+		switch {
+		case kubeHeaderRe.MatchString(line):
+			flush()
+			table = newKubeTable(line)
+		case strings.TrimSpace(line) == "":
+			flush()
+			out = append(out, line)
+		case table != nil:
+			table.rows = append(table.rows, line)
+		default:
+			out = append(out, line)
+		}
+	}
+	flush()
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// ---------------------------------------------------------------------------
+// KubectlDescribeStrategy
+// ---------------------------------------------------------------------------
+
+// KubectlDescribeStrategy filters `kubectl describe` output: status fields,
+// conditions, container states and warning events are kept; annotations,
+// volumes, tolerations and the per-container ports, resources, probes,
+// environment and mounts are dropped, as are Normal events.
+type KubectlDescribeStrategy struct{}
+
+func (s *KubectlDescribeStrategy) Name() string { return "kubectl-describe" }
+
+func (s *KubectlDescribeStrategy) CanHandle(command string, args []string) bool {
+	return isKubectl(command) && isSubcommand(args, "describe", kubectlValueFlags)
+}
+
+// kubeDescribeKeyRe matches a "Key:" line, capturing its indentation and key.
+var kubeDescribeKeyRe = regexp.MustCompile(`^( *)([A-Za-z][A-Za-z0-9 ./-]*?):(?:\s|$)`)
+
+// kubeDescribeDropTop are top-level fields dropped with their continuation lines.
+var kubeDescribeDropTop = map[string]bool{
+	"Annotations": true, "Volumes": true, "Tolerations": true,
+}
+
+// kubeDescribeDropNested are indented fields (containers, pod templates)
+// dropped with their nested lines. Top-level fields of the same name, such
+// as a service's Port, are kept.
+var kubeDescribeDropNested = map[string]bool{
+	"Annotations": true, "Volumes": true, "Container ID": true, "Image ID": true,
+	"Port": true, "Ports": true, "Host Port": true, "Host Ports": true,
+	"Command": true, "Args": true, "Limits": true, "Requests": true,
+	"Liveness": true, "Readiness": true, "Startup": true,
+	"Environment": true, "Environment Variables from": true, "Mounts": true,
+	"SeccompProfile": true,
+}
+
+func (s *KubectlDescribeStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	skipIndent := -1 // dropping lines indented deeper than this
+	inEvents := false
+	normalEvents := 0
+	endEvents := func() {
+		if normalEvents > 0 {
+			out = append(out, fmt.Sprintf("  (%d Normal events hidden)", normalEvents))
+		}
+		inEvents, normalEvents = false, 0
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if skipIndent >= 0 {
+			if trimmed != "" && indent > skipIndent {
+				continue
+			}
+			skipIndent = -1
+		}
+
+		if inEvents {
+			if trimmed != "" && indent > 0 {
+				if strings.HasPrefix(trimmed, "Normal ") {
+					normalEvents++
+					continue
+				}
+				out = append(out, line)
+				continue
+			}
+			endEvents()
+		}
+
+		if m := kubeDescribeKeyRe.FindStringSubmatch(line); m != nil {
+			key := m[2]
+			if (indent == 0 && kubeDescribeDropTop[key]) || (indent > 0 && kubeDescribeDropNested[key]) {
+				skipIndent = indent
+				continue
+			}
+			if indent == 0 && key == "Events" {
+				inEvents = true
+			}
+		}
+		out = append(out, line)
+	}
+	if inEvents {
+		endEvents()
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
+
+// ---------------------------------------------------------------------------
+// KubectlLogsStrategy
+// ---------------------------------------------------------------------------
+
+// KubectlLogsStrategy clusters `kubectl logs` output with LogClusterStrategy:
+// lines that differ only in timestamps, ids, paths and numbers are shown once
+// as their template with a line count, and each distinct error line is kept.
+// JVM and Python stack traces are condensed first.
+type KubectlLogsStrategy struct{}
+
+func (s *KubectlLogsStrategy) Name() string { return "kubectl-logs" }
+
+func (s *KubectlLogsStrategy) CanHandle(command string, args []string) bool {
+	return isKubectl(command) && isSubcommand(args, "logs", kubectlValueFlags)
+}
+
+func (s *KubectlLogsStrategy) Filter(raw []byte, command string, args []string, exitCode int) Result {
+	return (&LogClusterStrategy{}).Filter(raw, command, args, exitCode)
+}
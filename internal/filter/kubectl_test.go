@@ -0,0 +1,301 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKubectlStrategies_CanHandle(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		get     bool
+		desc    bool
+		logs    bool
+	}{
+		{"get pods", "kubectl", []string{"get", "pods"}, true, false, false},
+		{"get wide", "kubectl", []string{"get", "pods", "-o", "wide"}, true, false, false},
+		{"get with namespace first", "kubectl", []string{"-n", "get", "get", "pods"}, true, false, false},
+		{"get with context", "kubectl", []string{"--context", "logs", "get", "nodes"}, true, false, false},
+		{"get json", "kubectl", []string{"get", "pods", "-ojson"}, false, false, false},
+		{"get yaml long flag", "kubectl", []string{"get", "pods", "--output=yaml"}, false, false, false},
+		{"get jsonpath", "kubectl", []string{"get", "pods", "-o", "jsonpath={.items[*].metadata.name}"}, false, false, false},
+		{"describe", "kubectl", []string{"describe", "pod", "api"}, false, true, false},
+		{"logs", "kubectl", []string{"logs", "-f", "api"}, false, false, true},
+		{"logs with kubeconfig", "/usr/local/bin/kubectl", []string{"--kubeconfig", "k.yaml", "logs", "api"}, false, false, true},
+		{"apply", "kubectl", []string{"apply", "-f", "x.yaml"}, false, false, false},
+		{"not kubectl", "oc", []string{"get", "pods"}, false, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (&KubectlGetStrategy{}).CanHandle(tc.command, tc.args); got != tc.get {
+				t.Errorf("get CanHandle = %v, want %v", got, tc.get)
+			}
+			if got := (&KubectlDescribeStrategy{}).CanHandle(tc.command, tc.args); got != tc.desc {
+				t.Errorf("describe CanHandle = %v, want %v", got, tc.desc)
+			}
+			if got := (&KubectlLogsStrategy{}).CanHandle(tc.command, tc.args); got != tc.logs {
+				t.Errorf("logs CanHandle = %v, want %v", got, tc.logs)
+			}
+		})
+	}
+}
+
+const kubectlGetPods = `NAMESPACE     NAME                              READY   STATUS             RESTARTS       AGE
+default       api-7d9f8b6c5-x2x9z               0/1     CrashLoopBackOff   12 (3m ago)    1h
+default       api-7d9f8b6c5-q8w7e               1/1     Running            0              1h
+default       web-5c6b7d8f9-abcde               1/1     Running            0              2d
+default       web-5c6b7d8f9-fghij               1/1     Running            0              2d
+default       migrate-28471230-kx2lp            0/1     Completed          0              3h
+default       worker-6f7c8d9e0-lmnop            1/2     Running            3 (10m ago)    5h
+kube-system   coredns-5d78c9869d-4xk2m          1/1     Running            0              30d
+kube-system   coredns-5d78c9869d-9zq8r          1/1     Running            0              30d
+kube-system   kube-proxy-7hz6n                  1/1     Running            0              30d
+monitoring    prometheus-0                      0/2     Pending            0              4m
+`
+
+func TestKubectlGetStrategy_Filter(t *testing.T) {
+	s := &KubectlGetStrategy{}
+	result := s.Filter([]byte(kubectlGetPods), "kubectl", []string{"get", "pods", "-A"}, 0)
+
+	want := `NAMESPACE     NAME                              READY   STATUS             RESTARTS       AGE
+default       api-7d9f8b6c5-x2x9z               0/1     CrashLoopBackOff   12 (3m ago)    1h
+default       worker-6f7c8d9e0-lmnop            1/2     Running            3 (10m ago)    5h
+monitoring    prometheus-0                      0/2     Pending            0              4m
+10 total: 6 Running, 1 CrashLoopBackOff, 1 Completed, 1 Running (not ready), 1 Pending
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestKubectlGetStrategy_Filter_AllHealthyAndMultipleTables(t *testing.T) {
+	s := &KubectlGetStrategy{}
+	input := `NAME                       READY   STATUS    RESTARTS   AGE
+pod/web-5c6b7d8f9-abcde    1/1     Running   0          2d
+pod/web-5c6b7d8f9-fghij    1/1     Running   0          2d
+
+NAME          TYPE        CLUSTER-IP     EXTERNAL-IP   PORT(S)   AGE
+service/web   ClusterIP   10.96.12.34    <none>        80/TCP    2d
+
+NAME                  READY   UP-TO-DATE   AVAILABLE   AGE
+deployment.apps/web   2/2     2            2           2d
+
+NAME                             DESIRED   CURRENT   READY   AGE
+replicaset.apps/web-5c6b7d8f9    2         2         1       2d
+`
+	result := s.Filter([]byte(input), "kubectl", []string{"get", "all"}, 0)
+
+	want := `2 total: 2 Running
+
+NAME          TYPE        CLUSTER-IP     EXTERNAL-IP   PORT(S)   AGE
+service/web   ClusterIP   10.96.12.34    <none>        80/TCP    2d
+
+1 total: 1 ready
+
+NAME                             DESIRED   CURRENT   READY   AGE
+replicaset.apps/web-5c6b7d8f9    2         2         1       2d
+1 total: 1 not ready
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestKubectlGetStrategy_Filter_Nodes(t *testing.T) {
+	s := &KubectlGetStrategy{}
+	var b strings.Builder
+	b.WriteString("NAME     STATUS                     ROLES           AGE   VERSION\n")
+	for _, n := range []string{"node-1", "node-2", "node-3", "node-4", "node-5", "node-6", "node-7", "node-8"} {
+		b.WriteString(n + "   Ready                      <none>          40d   v1.30.2\n")
+	}
+	b.WriteString("node-9   Ready,SchedulingDisabled   <none>          40d   v1.30.2\n")
+	b.WriteString("cp-1     NotReady                   control-plane   40d   v1.30.2\n")
+
+	result := s.Filter([]byte(b.String()), "kubectl", []string{"get", "nodes"}, 0)
+
+	want := `NAME     STATUS                     ROLES           AGE   VERSION
+node-9   Ready,SchedulingDisabled   <none>          40d   v1.30.2
+cp-1     NotReady                   control-plane   40d   v1.30.2
+10 total: 8 Ready, 1 Ready,SchedulingDisabled, 1 NotReady
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestKubectlGetStrategy_Filter_SmallOutput(t *testing.T) {
+	s := &KubectlGetStrategy{}
+	input := "NAME   READY   STATUS    RESTARTS   AGE\napi    0/1     Pending   0          1m\n"
+	result := s.Filter([]byte(input), "kubectl", []string{"get", "pods"}, 0)
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("small output should pass through, got:\n%s", result.Filtered)
+	}
+}
+
+const kubectlDescribePod = `Name:             api-7d9f8b6c5-x2x9z
+Namespace:        default
+Priority:         0
+Service Account:  default
+Node:             node-1/10.0.0.5
+Labels:           app=api
+                  pod-template-hash=7d9f8b6c5
+Annotations:      kubectl.kubernetes.io/restartedAt: 2026-02-12T13:59:00Z
+                  prometheus.io/scrape: true
+Status:           Running
+IP:               10.244.1.7
+Controlled By:    ReplicaSet/api-7d9f8b6c5
+Containers:
+  api:
+    Container ID:   containerd://4f1c2a
+    Image:          ghcr.io/acme/api:1.4.2
+    Image ID:       ghcr.io/acme/api@sha256:9b2e
+    Port:           8080/TCP
+    Host Port:      0/TCP
+    State:          Waiting
+      Reason:       CrashLoopBackOff
+    Last State:     Terminated
+      Reason:       Error
+      Exit Code:    1
+    Ready:          False
+    Restart Count:  12
+    Limits:
+      memory:  512Mi
+    Requests:
+      cpu:        100m
+      memory:     256Mi
+    Liveness:     http-get http://:8080/healthz delay=10s timeout=1s period=10s #success=1 #failure=3
+    Environment:
+      DB_HOST:  postgres
+    Mounts:
+      /var/run/secrets/kubernetes.io/serviceaccount from kube-api-access-x (ro)
+Conditions:
+  Type              Status
+  Initialized       True
+  Ready             False
+  ContainersReady   False
+  PodScheduled      True
+Volumes:
+  kube-api-access-x:
+    Type:                    Projected (a volume that contains injected data from multiple sources)
+    TokenExpirationSeconds:  3607
+QoS Class:                   Burstable
+Node-Selectors:              <none>
+Tolerations:                 node.kubernetes.io/not-ready:NoExecute op=Exists for 300s
+                             node.kubernetes.io/unreachable:NoExecute op=Exists for 300s
+Events:
+  Type     Reason     Age                  From               Message
+  ----     ------     ----                 ----               -------
+  Normal   Scheduled  60m                  default-scheduler  Successfully assigned default/api-7d9f8b6c5-x2x9z to node-1
+  Normal   Pulled     58m (x12 over 60m)   kubelet            Container image "ghcr.io/acme/api:1.4.2" already present on machine
+  Warning  BackOff    2m (x250 over 59m)   kubelet            Back-off restarting failed container api in pod api-7d9f8b6c5-x2x9z_default
+`
+
+func TestKubectlDescribeStrategy_Filter(t *testing.T) {
+	s := &KubectlDescribeStrategy{}
+	result := s.Filter([]byte(kubectlDescribePod), "kubectl", []string{"describe", "pod", "api-7d9f8b6c5-x2x9z"}, 0)
+
+	want := `Name:             api-7d9f8b6c5-x2x9z
+Namespace:        default
+Priority:         0
+Service Account:  default
+Node:             node-1/10.0.0.5
+Labels:           app=api
+                  pod-template-hash=7d9f8b6c5
+Status:           Running
+IP:               10.244.1.7
+Controlled By:    ReplicaSet/api-7d9f8b6c5
+Containers:
+  api:
+    Image:          ghcr.io/acme/api:1.4.2
+    State:          Waiting
+      Reason:       CrashLoopBackOff
+    Last State:     Terminated
+      Reason:       Error
+      Exit Code:    1
+    Ready:          False
+    Restart Count:  12
+Conditions:
+  Type              Status
+  Initialized       True
+  Ready             False
+  ContainersReady   False
+  PodScheduled      True
+QoS Class:                   Burstable
+Node-Selectors:              <none>
+Events:
+  Type     Reason     Age                  From               Message
+  ----     ------     ----                 ----               -------
+  Warning  BackOff    2m (x250 over 59m)   kubelet            Back-off restarting failed container api in pod api-7d9f8b6c5-x2x9z_default
+  (2 Normal events hidden)
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestKubectlDescribeStrategy_Filter_KeepsTopLevelPort(t *testing.T) {
+	s := &KubectlDescribeStrategy{}
+	input := `Name:              web
+Namespace:         default
+Labels:            app=web
+Annotations:       meta.helm.sh/release-name: web
+                   meta.helm.sh/release-namespace: default
+Selector:          app=web
+Type:              ClusterIP
+IP:                10.96.12.34
+Port:              http  80/TCP
+TargetPort:        8080/TCP
+Endpoints:         <none>
+Events:            <none>
+`
+	result := s.Filter([]byte(input), "kubectl", []string{"describe", "svc", "web"}, 0)
+
+	if strings.Contains(result.Filtered, "meta.helm.sh") {
+		t.Errorf("annotations should be dropped, got:\n%s", result.Filtered)
+	}
+	for _, keep := range []string{"Port:              http  80/TCP\n", "Endpoints:         <none>\n", "Events:            <none>\n"} {
+		if !strings.Contains(result.Filtered, keep) {
+			t.Errorf("expected %q to be kept, got:\n%s", keep, result.Filtered)
+		}
+	}
+}
+
+func TestKubectlLogsStrategy_Filter(t *testing.T) {
+	s := &KubectlLogsStrategy{}
+	var b strings.Builder
+	b.WriteString("2026-02-12T14:30:00.001Z INFO starting server on :8080\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&b, "2026-02-12T14:30:%02d.120Z INFO GET /healthz 200 %dms from 10.244.0.1:%d\n", i, i%3+1, 53412+i)
+	}
+	b.WriteString("2026-02-12T14:31:07.554Z ERROR payment 5f2c9a1e-0b7d-4e8e-9c1a-3d2b4f6a7c8e failed: card declined\n")
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "2026-02-12T14:31:%02d.002Z WARN slow query took %dms\n", i+8, 1500+i*7)
+	}
+
+	result := s.Filter([]byte(b.String()), "kubectl", []string{"logs", "deploy/api"}, 0)
+
+	want := `2026-02-12T14:30:00.001Z INFO starting server on :8080
+<TS> INFO GET /healthz <N> <N>ms from <IP>  (40 lines)
+  e.g. 2026-02-12T14:30:00.120Z INFO GET /healthz 200 1ms from 10.244.0.1:53412
+2026-02-12T14:31:07.554Z ERROR payment 5f2c9a1e-0b7d-4e8e-9c1a-3d2b4f6a7c8e failed: card declined
+<TS> WARN slow query took <N>ms  (12 lines)
+  e.g. 2026-02-12T14:31:08.002Z WARN slow query took 1500ms
+
+54 log lines, 4 distinct
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
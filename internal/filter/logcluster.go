@@ -0,0 +1,226 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Log line clustering (Drain)
+// ---------------------------------------------------------------------------
+
+// logMasks turn a log line into its template by replacing the parts that vary
+// between otherwise identical lines. They are applied in order.
+var logMasks = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Timestamps: "2026-02-12T14:30:22.101Z", "2026/02/12 14:30:22", "12/Feb/2026:14:30:22 +0000", "14:30:22"
+	{regexp.MustCompile(`\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?` +
+		`|\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2}(?: [+-]\d{4})?` +
+		`|\b\d{2}:\d{2}:\d{2}(?:[.,]\d+)?`), "<TS>"},
+	// UUIDs and long hex ids (request ids, hashes, container ids)
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b|\b[0-9a-fA-F]{8,}\b`), "<ID>"},
+	// IPv4 addresses with an optional port
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<IP>"},
+	// Paths of two or more segments: "/var/lib/app/data.db", "src/app/main.py"
+	{regexp.MustCompile(`[\w.-]*(?:/[\w.@%+-]+){2,}/?`), "<PATH>"},
+	// Any other number, including durations and sizes ("12ms", "3.5MB")
+	{regexp.MustCompile(`\d+(?:\.\d+)?`), "<N>"},
+}
+
+// logTemplate returns the masked form of a log line.
+func logTemplate(line string) string {
+	t := strings.TrimSpace(line)
+	for _, m := range logMasks {
+		t = m.re.ReplaceAllString(t, m.repl)
+	}
+	return t
+}
+
+// logErrorRe matches lines that report an error. Error lines are never
+// clustered together with other lines.
+var logErrorRe = regexp.MustCompile(`(?i)\b(?:error|err|fatal|panic|exception|fail(?:ed|ure)?|critical|traceback)\b`)
+
+// logWildcard marks a template position where clustered lines differ.
+const logWildcard = "<*>"
+
+// logSimilarity is the share of positions a line must have in common with a
+// template to join its cluster.
+const logSimilarity = 0.6
+
+// logCluster is a group of log lines sharing a template.
+type logCluster struct {
+	template []string // masked tokens, logWildcard where lines differ
+	example  string   // first line seen
+	count    int
+	isError  bool
+	// errorLines holds, for an error cluster, the first line of each distinct
+	// masked form, and errorCounts how often each form was seen.
+	errorLines  []string
+	errorCounts map[string]int
+	errorKeys   []string
+}
+
+// logClusterer learns line templates online, in the manner of Drain (He et
+// al., "Drain: An Online Log Parsing Approach with Fixed Depth Tree"). Lines
+// are masked with logMasks and split into tokens. The candidate clusters for a
+// line are those with the same token count, first token and error class, and
+// the line joins the most similar candidate if its tokens match the template
+// in at least logSimilarity of the positions. Positions that differ become
+// wildcards.
+type logClusterer struct {
+	clusters []*logCluster            // in order of first appearance
+	leaves   map[string][]*logCluster // candidates by token count, first token and error class
+}
+
+func newLogClusterer() *logClusterer {
+	return &logClusterer{leaves: map[string][]*logCluster{}}
+}
+
+// add assigns a line to a cluster, creating one if no template is similar
+// enough. Blank lines are ignored.
+func (c *logClusterer) add(line string) {
+	masked := logTemplate(line)
+	tokens := strings.Fields(masked)
+	if len(tokens) == 0 {
+		return
+	}
+	isError := logErrorRe.MatchString(line)
+	key := fmt.Sprintf("%d %s %t", len(tokens), tokens[0], isError)
+
+	var best *logCluster
+	bestSim := 0.0
+	for _, cl := range c.leaves[key] {
+		if sim := templateSimilarity(cl.template, tokens); sim > bestSim {
+			best, bestSim = cl, sim
+		}
+	}
+	if best == nil || bestSim < logSimilarity {
+		best = &logCluster{template: tokens, example: line, isError: isError}
+		if isError {
+			best.errorCounts = map[string]int{}
+		}
+		c.leaves[key] = append(c.leaves[key], best)
+		c.clusters = append(c.clusters, best)
+	} else {
+		for i, tok := range tokens {
+			if best.template[i] != tok {
+				best.template[i] = logWildcard
+			}
+		}
+	}
+	best.count++
+	if isError {
+		if best.errorCounts[masked] == 0 {
+			best.errorKeys = append(best.errorKeys, masked)
+			best.errorLines = append(best.errorLines, line)
+		}
+		best.errorCounts[masked]++
+	}
+}
+
+// templateSimilarity returns the share of positions where tokens match the
+// template. Wildcards match any token.
+func templateSimilarity(template, tokens []string) float64 {
+	same := 0
+	for i, tok := range tokens {
+		if template[i] == tok || template[i] == logWildcard {
+			same++
+		}
+	}
+	return float64(same) / float64(len(tokens))
+}
+
+// render shows each cluster once, in order of first appearance: a line seen
+// once (a rare line) as is, a repeated template with its placeholders followed
+// by the number of lines and, indented below, its first line, so the masked
+// values of at least one real line are shown. Error clusters are not summarized: each distinct
+// error line is shown as is, with a count when only masked values (timestamps,
+// ids, numbers) differ between its repeats.
+func (c *logClusterer) render() []string {
+	out := make([]string, 0, len(c.clusters))
+	for _, cl := range c.clusters {
+		switch {
+		case cl.isError:
+			for i, line := range cl.errorLines {
+				if n := cl.errorCounts[cl.errorKeys[i]]; n > 1 {
+					line = fmt.Sprintf("%s  (repeated %d times)", line, n)
+				}
+				out = append(out, line)
+			}
+		case cl.count == 1:
+			out = append(out, cl.example)
+		default:
+			out = append(out,
+				fmt.Sprintf("%s  (%d lines)", strings.Join(cl.template, " "), cl.count),
+				"  e.g. "+strings.TrimSpace(cl.example))
+		}
+	}
+	return out
+}
+
+// clusterLogLines clusters log lines with a logClusterer. It returns the
+// rendered lines and the number of templates.
+func clusterLogLines(lines []string) ([]string, int) {
+	c := newLogClusterer()
+	for _, line := range lines {
+		c.add(line)
+	}
+	return c.render(), len(c.clusters)
+}
+
+// summarizeLog condenses the JVM and Python stack traces in log lines, then
+// clusters them. It returns the rendered lines and the number of templates.
+func summarizeLog(lines []string) ([]string, int) {
+	lines, _ = condenseJVMTraces(lines)
+	lines, _ = condensePythonTracebacks(lines)
+	return clusterLogLines(lines)
+}
+
+// ---------------------------------------------------------------------------
+// LogClusterStrategy
+// ---------------------------------------------------------------------------
+
+// LogClusterStrategy clusters log output by line template. It handles
+// `journalctl` and can be selected for any command with `--filter log-cluster`.
+type LogClusterStrategy struct{}
+
+func (s *LogClusterStrategy) Name() string { return "log-cluster" }
+
+func (s *LogClusterStrategy) CanHandle(command string, args []string) bool {
+	return filepath.Base(command) == "journalctl"
+}
+
+func (s *LogClusterStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	clustered, templates := summarizeLog(lines)
+	out := append(clustered, "", fmt.Sprintf("%d log lines, %d distinct", nonBlankLines(lines), templates))
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLogTemplate(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"2026-02-12T14:30:22.101Z INFO GET /healthz 200 3ms", "<TS> INFO GET /healthz <N> <N>ms"},
+		{"2026-02-12 14:30:22,101 WARN retrying in 5s", "<TS> WARN retrying in <N>s"},
+		{"request 5f2c9a1e-0b7d-4e8e-9c1a-3d2b4f6a7c8e done", "request <ID> done"},
+		{"container 4f1c2a9b8e7d started", "container <ID> started"},
+		{"  dial tcp 10.0.0.5:5432: connection refused", "dial tcp <IP>: connection refused"},
+		{"opened /var/lib/app/data-3.db in 4ms", "opened <PATH> in <N>ms"},
+		{`10.0.0.1 - - [12/Feb/2026:14:30:00 +0000] "GET / HTTP/1.1"`, `<IP> - - [<TS>] "GET / HTTP/<N>"`},
+	}
+	for _, tc := range tests {
+		if got := logTemplate(tc.line); got != tc.want {
+			t.Errorf("logTemplate(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestClusterLogLines(t *testing.T) {
+	lines := []string{
+		"12:00:01 worker 1 picked job 17",
+		"12:00:02 worker 2 picked job 18",
+		"",
+		"12:00:03 job 17 failed: timeout",
+		"12:00:04 worker 1 picked job 19",
+	}
+	got, templates := clusterLogLines(lines)
+
+	want := "<TS> worker <N> picked job <N>  (3 lines)\n  e.g. 12:00:01 worker 1 picked job 17\n12:00:03 job 17 failed: timeout"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("clustered mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+	if templates != 2 {
+		t.Errorf("templates = %d, want 2", templates)
+	}
+}
+
+func TestClusterLogLines_LearnsWildcards(t *testing.T) {
+	lines := []string{
+		"session opened for user alice by sshd",
+		"session opened for user bob by sshd",
+		"session closed for user alice",
+		"session opened for user carol by cron",
+	}
+	c := newLogClusterer()
+	for _, line := range lines {
+		c.add(line)
+	}
+
+	if len(c.clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %v", len(c.clusters), c.render())
+	}
+	if got := strings.Join(c.clusters[0].template, " "); got != "session opened for user <*> by <*>" {
+		t.Errorf("template = %q", got)
+	}
+	if c.clusters[0].count != 3 {
+		t.Errorf("count = %d, want 3", c.clusters[0].count)
+	}
+}
+
+func TestClusterLogLines_KeepsErrorLinesApart(t *testing.T) {
+	lines := []string{
+		"job 17 finished in 3s",
+		"job 18 finished in 4s",
+		"job 19 failed in 2s",
+	}
+	got, templates := clusterLogLines(lines)
+
+	want := "job <N> finished in <N>s  (2 lines)\n  e.g. job 17 finished in 3s\njob 19 failed in 2s"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("clustered mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+	if templates != 2 {
+		t.Errorf("templates = %d, want 2", templates)
+	}
+}
+
+func TestClusterLogLines_ListsDistinctErrors(t *testing.T) {
+	lines := []string{
+		"12:00:01 ERROR payment failed: card declined",
+		"12:00:02 ERROR payment failed: card expired",
+		"12:00:03 ERROR payment failed: card declined",
+		"12:00:04 ERROR payment failed: insufficient funds",
+	}
+	got, _ := clusterLogLines(lines)
+
+	want := "12:00:01 ERROR payment failed: card declined  (repeated 2 times)\n" +
+		"12:00:02 ERROR payment failed: card expired\n" +
+		"12:00:04 ERROR payment failed: insufficient funds"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("clustered mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestLogClusterStrategy_Filter(t *testing.T) {
+	s := &LogClusterStrategy{}
+	var b strings.Builder
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, "Feb 12 14:30:%02d web-1 systemd[1]: Started session-%d.scope - Session %d of User deploy.\n", i, 300+i, 300+i)
+	}
+	b.WriteString("Feb 12 14:31:02 web-1 kernel: Out of memory: Killed process 4242 (api) total-vm:2048000kB\n")
+	b.WriteString("Feb 12 14:31:03 web-1 systemd[1]: api.service: Main process exited, code=killed, status=9/KILL\n")
+	b.WriteString("Feb 12 14:31:03 web-1 systemd[1]: api.service: Failed with result 'signal'.\n")
+
+	result := s.Filter([]byte(b.String()), "journalctl", []string{"-u", "api", "--since", "today"}, 0)
+
+	want := `Feb <N> <TS> web-<N> systemd[<N>]: Started session-<N>.scope - Session <N> of User deploy.  (20 lines)
+  e.g. Feb 12 14:30:00 web-1 systemd[1]: Started session-300.scope - Session 300 of User deploy.
+Feb 12 14:31:02 web-1 kernel: Out of memory: Killed process 4242 (api) total-vm:2048000kB
+Feb 12 14:31:03 web-1 systemd[1]: api.service: Main process exited, code=killed, status=9/KILL
+Feb 12 14:31:03 web-1 systemd[1]: api.service: Failed with result 'signal'.
+
+23 log lines, 4 distinct
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestLogClusterStrategy_CanHandle(t *testing.T) {
+	s := &LogClusterStrategy{}
+	if !s.CanHandle("journalctl", []string{"-u", "api"}) {
+		t.Error("expected journalctl to be handled")
+	}
+	if s.CanHandle("tail", []string{"-f", "app.log"}) {
+		t.Error("tail should only be clustered with --filter log-cluster")
+	}
+}
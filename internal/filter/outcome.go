@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Outcome is the parsed result of a test or build run. It is what gets
+// compared between runs of the same command, so it holds identifiers rather
+// than raw output.
+type Outcome struct {
+	// Failed lists failing tests (e.g. "github.com/x/pkg TestFoo" for Go,
+	// "tests::parse" for Cargo).
+	Failed []string `json:"failed,omitempty"`
+	// Passed lists passing tests and, where the tool only reports at package
+	// level, passing packages. A failure is considered fixed when it or its
+	// package appears here.
+	Passed []string `json:"passed,omitempty"`
+	// Errors lists compiler/build error lines as printed by the tool.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// OutcomeParser is implemented by strategies that can extract a structured
+// Outcome from raw output. It powers `--since-last` run comparison.
+type OutcomeParser interface {
+	ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool)
+}
+
+// errorPositionRe matches the ":line:col" (or ":line") position embedded in a
+// compiler error line, or Maven's ":[line,col]", so errors can be compared
+// across runs even when unrelated edits shift them to a different line.
+var errorPositionRe = regexp.MustCompile(`:\d+(:\d+)?:|:\[\d+,\d+\]`)
+
+// errorKey returns the position-independent identity of an error line.
+func errorKey(line string) string {
+	return errorPositionRe.ReplaceAllString(strings.TrimSpace(line), ":")
+}
+
+// HasPassed reports whether test id is accounted for as passing in o, either
+// directly or through its package (the part before the first space).
+func (o Outcome) HasPassed(id string) bool {
+	if slices.Contains(o.Passed, id) {
+		return true
+	}
+	if pkg, _, ok := strings.Cut(id, " "); ok {
+		return slices.Contains(o.Passed, pkg)
+	}
+	return false
+}
+
+// DiffOutcomes renders what changed between a previous and the current run:
+// newly failing tests, newly passing tests, new errors and errors that went away.
+func DiffOutcomes(prev, cur Outcome, prevTime time.Time) string {
+	var newlyFailing, newlyPassing, notReported, stillFailing []string
+	for _, f := range cur.Failed {
+		if slices.Contains(prev.Failed, f) {
+			stillFailing = append(stillFailing, f)
+		} else {
+			newlyFailing = append(newlyFailing, f)
+		}
+	}
+	for _, f := range prev.Failed {
+		if slices.Contains(cur.Failed, f) {
+			continue
+		}
+		if cur.HasPassed(f) {
+			newlyPassing = append(newlyPassing, f)
+		} else {
+			notReported = append(notReported, f)
+		}
+	}
+
+	prevErrs := make(map[string]bool, len(prev.Errors))
+	for _, e := range prev.Errors {
+		prevErrs[errorKey(e)] = true
+	}
+	curErrs := make(map[string]bool, len(cur.Errors))
+	var newErrors []string
+	for _, e := range cur.Errors {
+		k := errorKey(e)
+		curErrs[k] = true
+		if !prevErrs[k] {
+			newErrors = append(newErrors, e)
+		}
+	}
+	var fixedErrors []string
+	for _, e := range prev.Errors {
+		if !curErrs[errorKey(e)] {
+			fixedErrors = append(fixedErrors, e)
+		}
+	}
+	stillErrors := len(cur.Errors) - len(newErrors)
+
+	var out []string
+	when := prevTime.Format("2006-01-02 15:04:05")
+	if len(newlyFailing)+len(newlyPassing)+len(notReported)+len(newErrors)+len(fixedErrors) == 0 {
+		out = append(out, fmt.Sprintf("No changes since last run (%s).", when))
+	} else {
+		out = append(out, fmt.Sprintf("Changes since last run (%s):", when))
+		out = appendSection(out, "newly failing", newlyFailing)
+		out = appendSection(out, "newly passing", newlyPassing)
+		out = appendSection(out, "no longer reported", notReported)
+		out = appendSection(out, "new errors", newErrors)
+		out = appendSection(out, "errors fixed", fixedErrors)
+	}
+	out = append(out, fmt.Sprintf("%d still failing, %d errors remaining", len(stillFailing), stillErrors))
+
+	return strings.Join(out, "\n") + "\n"
+}
+
+// appendSection appends a titled, indented list to out when items is non-empty.
+func appendSection(out []string, title string, items []string) []string {
+	if len(items) == 0 {
+		return out
+	}
+	out = append(out, fmt.Sprintf("  %s (%d):", title, len(items)))
+	for _, item := range items {
+		out = append(out, "    "+item)
+	}
+	return out
+}
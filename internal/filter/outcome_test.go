@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorKey(t *testing.T) {
+	a := errorKey("./main.go:12:5: undefined: foo")
+	b := errorKey("./main.go:40:5: undefined: foo")
+	if a != b {
+		t.Errorf("errorKey should ignore positions: %q != %q", a, b)
+	}
+	if errorKey("./main.go:12:5: undefined: bar") == a {
+		t.Error("errorKey should distinguish different messages")
+	}
+	if errorKey("/src/Foo.java:[12,8] cannot find symbol") != errorKey("/src/Foo.java:[30,8] cannot find symbol") {
+		t.Error("errorKey should ignore Maven positions")
+	}
+}
+
+func TestDiffOutcomes_Changes(t *testing.T) {
+	prev := Outcome{
+		Failed: []string{"example.com/pkg TestA", "example.com/pkg TestB", "example.com/other TestC"},
+		Errors: []string{"./a.go:1:2: undefined: x"},
+	}
+	cur := Outcome{
+		Failed: []string{"example.com/pkg TestB", "example.com/pkg TestD"},
+		Passed: []string{"example.com/pkg TestA"},
+		Errors: []string{"./b.go:3:4: missing return"},
+	}
+	when := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	got := DiffOutcomes(prev, cur, when)
+
+	for _, want := range []string{
+		"Changes since last run (2026-01-02 03:04:05):",
+		"newly failing (1):\n    example.com/pkg TestD",
+		"newly passing (1):\n    example.com/pkg TestA",
+		"no longer reported (1):\n    example.com/other TestC",
+		"new errors (1):\n    ./b.go:3:4: missing return",
+		"errors fixed (1):\n    ./a.go:1:2: undefined: x",
+		"1 still failing, 0 errors remaining",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in diff, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestDiffOutcomes_PackagePassResolvesTest(t *testing.T) {
+	prev := Outcome{Failed: []string{"example.com/pkg TestA"}}
+	cur := Outcome{Passed: []string{"example.com/pkg"}}
+
+	got := DiffOutcomes(prev, cur, time.Now())
+	if !strings.Contains(got, "newly passing (1):") {
+		t.Errorf("a passing package should resolve its failed tests, got:\n%s", got)
+	}
+}
+
+func TestDiffOutcomes_NoChanges(t *testing.T) {
+	o := Outcome{
+		Failed: []string{"TestA"},
+		Errors: []string{"./a.go:1:2: undefined: x"},
+	}
+	moved := Outcome{
+		Failed: []string{"TestA"},
+		Errors: []string{"./a.go:9:2: undefined: x"},
+	}
+
+	got := DiffOutcomes(o, moved, time.Now())
+	if !strings.HasPrefix(got, "No changes since last run") {
+		t.Errorf("expected no changes, got:\n%s", got)
+	}
+	if !strings.Contains(got, "1 still failing, 1 errors remaining") {
+		t.Errorf("expected remaining counts, got:\n%s", got)
+	}
+}
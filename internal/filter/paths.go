@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathRewriter is implemented by build, test and lint strategies, whose
+// diagnostics name source files by long absolute paths. The executor shortens
+// paths (see PathShortener) only in the output of strategies that opt in, so
+// paths a command prints as its result, such as pwd or go env, stay intact.
+type PathRewriter interface {
+	ShortenPaths()
+}
+
+// ---------------------------------------------------------------------------
+// Path shortening
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for path shortening.
+var (
+	// absPathRe matches an absolute path and the character before it. The
+	// path stops at ":" so "file:line:col" suffixes are left as they are, and
+	// a path must follow a non-path character so URLs are not matched.
+	absPathRe = regexp.MustCompile(`(^|[^\w./~-])(/[\w.@%+~-]+(?:/[\w.@%+~-]+)*)`)
+	// sitePackagesRe matches a Python package directory inside a path.
+	sitePackagesRe = regexp.MustCompile(`^.*?/(?:site|dist)-packages(?:/|$)`)
+)
+
+// sitePackagesAlias is the alias for the first Python package directory seen
+// in an output. It is found in the output rather than the environment, as a
+// machine usually has several interpreters and virtualenvs.
+const sitePackagesAlias = "$SITE_PACKAGES"
+
+// pathAlias is a dependency root printed as a short name.
+type pathAlias struct {
+	name string // e.g. "$GOMODCACHE"
+	dir  string // absolute, cleaned
+}
+
+// PathShortener rewrites absolute paths in filtered output: paths inside the
+// repository become relative to the working directory, and paths inside
+// well-known dependency roots (the Go module cache, Cargo and rustup homes,
+// Python site-packages) start with an alias explained by a legend line.
+type PathShortener struct {
+	cwd     string
+	root    string
+	aliases []pathAlias
+}
+
+// NewPathShortener returns a shortener for output of a command run in cwd
+// inside the repository at root (cwd itself outside a repository). Dependency
+// roots are taken from the environment, with the tools' defaults.
+func NewPathShortener(cwd, root string) *PathShortener {
+	home, _ := os.UserHomeDir()
+	fromEnv := func(name, def string) string {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		if home == "" {
+			return ""
+		}
+		return filepath.Join(home, def)
+	}
+
+	gomodcache := os.Getenv("GOMODCACHE")
+	if gomodcache == "" {
+		if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+			gomodcache = filepath.Join(gopath[0], "pkg", "mod")
+		} else if home != "" {
+			gomodcache = filepath.Join(home, "go", "pkg", "mod")
+		}
+	}
+
+	p := &PathShortener{cwd: filepath.Clean(cwd), root: filepath.Clean(root)}
+	for _, a := range []pathAlias{
+		{"$GOMODCACHE", gomodcache},
+		{"$GOROOT", os.Getenv("GOROOT")},
+		{"$CARGO_HOME", fromEnv("CARGO_HOME", ".cargo")},
+		{"$RUSTUP_HOME", fromEnv("RUSTUP_HOME", ".rustup")},
+	} {
+		if a.dir == "" || !filepath.IsAbs(a.dir) {
+			continue
+		}
+		a.dir = filepath.Clean(a.dir)
+		if a.dir != "/" {
+			p.aliases = append(p.aliases, a)
+		}
+	}
+	return p
+}
+
+// Shorten rewrites the paths of a filtered result and appends a legend for
+// the aliases used. The result is reported as reduced if anything changed.
+func (p *PathShortener) Shorten(r Result) Result {
+	used := map[string]string{} // alias name -> dir
+	var sitePackages string
+
+	out := absPathRe.ReplaceAllStringFunc(r.Filtered, func(m string) string {
+		sub := absPathRe.FindStringSubmatch(m)
+		lead, path := sub[1], sub[2]
+		if sitePackages == "" {
+			if sp := sitePackagesRe.FindString(path); sp != "" && !withinDir(path, p.root) {
+				sitePackages = strings.TrimSuffix(sp, "/")
+			}
+		}
+		return lead + p.shortenPath(path, sitePackages, used)
+	})
+	if out == r.Filtered {
+		return r
+	}
+
+	if len(used) > 0 {
+		var legend []string
+		for _, a := range append(p.aliases, pathAlias{sitePackagesAlias, sitePackages}) {
+			if dir, ok := used[a.name]; ok {
+				legend = append(legend, fmt.Sprintf("%s=%s", a.name, dir))
+			}
+		}
+		hadTrailing := endsWithNewline(out)
+		if !hadTrailing {
+			out += "\n"
+		}
+		out = ensureTrailingNewline(out+"Path aliases: "+strings.Join(legend, ", "), hadTrailing)
+	}
+	return Result{Filtered: out, WasReduced: true}
+}
+
+// shortenPath applies the most specific rule matching path: an alias, or the
+// repository. Paths matching neither are returned unchanged.
+func (p *PathShortener) shortenPath(path, sitePackages string, used map[string]string) string {
+	best := ""
+	var alias *pathAlias
+	if withinDir(path, p.root) {
+		best = p.root
+	}
+	aliases := p.aliases
+	if sitePackages != "" {
+		aliases = append(aliases[:len(aliases):len(aliases)], pathAlias{sitePackagesAlias, sitePackages})
+	}
+	for i := range aliases {
+		if a := &aliases[i]; withinDir(path, a.dir) && len(a.dir) > len(best) {
+			best, alias = a.dir, a
+		}
+	}
+
+	switch {
+	case alias != nil:
+		used[alias.name] = alias.dir
+		return alias.name + strings.TrimPrefix(path, alias.dir)
+	case best != "":
+		if rel, err := filepath.Rel(p.cwd, path); err == nil {
+			return rel
+		}
+	}
+	return path
+}
+
+// withinDir reports whether path is dir or inside it.
+func withinDir(path, dir string) bool {
+	return dir != "" && (path == dir || strings.HasPrefix(path, dir+"/"))
+}
@@ -0,0 +1,80 @@
+package filter
+
+import "testing"
+
+// testShortener returns a shortener for /home/dev/src/app/cmd with fixed
+// dependency roots.
+func testShortener(t *testing.T) *PathShortener {
+	t.Helper()
+	t.Setenv("HOME", "/home/dev")
+	t.Setenv("GOMODCACHE", "/home/dev/go/pkg/mod")
+	t.Setenv("GOPATH", "")
+	t.Setenv("GOROOT", "")
+	t.Setenv("CARGO_HOME", "")
+	t.Setenv("RUSTUP_HOME", "")
+	return NewPathShortener("/home/dev/src/app/cmd", "/home/dev/src/app")
+}
+
+func TestPathShortener_GoPanic(t *testing.T) {
+	input := `panic: runtime error: index out of range [3] with length 3
+
+goroutine 1 [running]:
+github.com/org/app/internal/store.(*Store).Get(...)
+	/home/dev/src/app/internal/store/store.go:42 +0x1d
+github.com/org/app/cmd.run()
+	/home/dev/src/app/cmd/main.go:17 +0x45
+github.com/spf13/cobra.(*Command).execute(0xc000126000)
+	/home/dev/go/pkg/mod/github.com/spf13/cobra@v1.8.0/command.go:987 +0xab8
+`
+	want := `panic: runtime error: index out of range [3] with length 3
+
+goroutine 1 [running]:
+github.com/org/app/internal/store.(*Store).Get(...)
+	../internal/store/store.go:42 +0x1d
+github.com/org/app/cmd.run()
+	main.go:17 +0x45
+github.com/spf13/cobra.(*Command).execute(0xc000126000)
+	$GOMODCACHE/github.com/spf13/cobra@v1.8.0/command.go:987 +0xab8
+Path aliases: $GOMODCACHE=/home/dev/go/pkg/mod
+`
+	result := testShortener(t).Shorten(Result{Filtered: input})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestPathShortener_CargoAndPython(t *testing.T) {
+	input := `error[E0308]: mismatched types
+  --> /home/dev/src/app/cmd/src/lib.rs:10:5
+   |
+  ::: /home/dev/.cargo/registry/src/index.crates.io-6f17d22bba15001f/serde-1.0.200/src/de/mod.rs:540:12
+  File "/usr/lib/python3.12/site-packages/requests/api.py", line 59, in request
+  File "/usr/lib/python3.12/site-packages/requests/sessions.py", line 589, in request
+`
+	want := `error[E0308]: mismatched types
+  --> src/lib.rs:10:5
+   |
+  ::: $CARGO_HOME/registry/src/index.crates.io-6f17d22bba15001f/serde-1.0.200/src/de/mod.rs:540:12
+  File "$SITE_PACKAGES/requests/api.py", line 59, in request
+  File "$SITE_PACKAGES/requests/sessions.py", line 589, in request
+Path aliases: $CARGO_HOME=/home/dev/.cargo, $SITE_PACKAGES=/usr/lib/python3.12/site-packages
+`
+	result := testShortener(t).Shorten(Result{Filtered: input})
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestPathShortener_LeavesOtherPathsAlone(t *testing.T) {
+	input := "wrote /tmp/out.txt\nsee https://example.com/home/dev/src/app/docs\n/home/dev/src/application/x.go:3:1: other repo\n"
+	result := testShortener(t).Shorten(Result{Filtered: input, WasReduced: true})
+	if result.Filtered != input {
+		t.Errorf("expected output unchanged, got:\n%s", result.Filtered)
+	}
+	if !result.WasReduced {
+		t.Error("WasReduced should be kept from the filter")
+	}
+}
@@ -49,6 +49,16 @@ func (s *ProgressStripStrategy) CanHandle(command string, args []string) bool {
 	return false
 }
 
+// FoldRepeats folds the deprecation and peer dependency warnings package
+// managers print for many packages.
+func (s *ProgressStripStrategy) FoldRepeats() {}
+
+// FilterStderr filters stderr like stdout: pip draws its progress bars and
+// npm and yarn print their warnings there.
+func (s *ProgressStripStrategy) FilterStderr(raw []byte, command string, args []string, exitCode int) Result {
+	return s.Filter(raw, command, args, exitCode)
+}
+
 // Package-level compiled regexes for ProgressStripStrategy.
 var (
 	progressBarRe         = regexp.MustCompile(`\[#+[=> ]*\]`)
@@ -139,6 +149,9 @@ func (s *ProgressStripStrategy) Filter(raw []byte, command string, args []string
 		prevLine = line
 	}
 
+	// pip shows a failed package build's traceback; condense it like any other
+	kept, _ = condensePythonTracebacks(kept)
+
 	linesRemoved := len(lines) - len(kept)
 	if linesRemoved <= 0 && !crCleaned {
 		return Result{Filtered: cleaned, WasReduced: false}
@@ -0,0 +1,326 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// PytestStrategy
+// ---------------------------------------------------------------------------
+
+// PytestStrategy filters pytest output down to failures, errors, the short
+// test summary and the final tally. Traceback frames outside the user's code
+// (site-packages, the standard library) are hidden.
+type PytestStrategy struct{}
+
+func (s *PytestStrategy) Name() string { return "pytest" }
+
+func (s *PytestStrategy) CanHandle(command string, args []string) bool {
+	switch command {
+	case "pytest", "py.test":
+		return true
+	case "python", "python3":
+		return isPythonModule(args, "pytest")
+	}
+	return false
+}
+
+// isPythonModule reports whether python args run module via `-m module`.
+// The values of -X and -W are skipped; -c runs code rather than a module.
+func isPythonModule(args []string, module string) bool {
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "-m":
+			return i+1 < len(args) && args[i+1] == module
+		case a == "-m"+module:
+			return true
+		case a == "-X", a == "-W":
+			i++ // the option's value
+		case strings.HasPrefix(a, "-c"), !strings.HasPrefix(a, "-"):
+			return false // code or a script path: the module is not run with -m
+		}
+	}
+	return false
+}
+
+// Package-level compiled regexes for PytestStrategy.
+var (
+	// pytestSectionRe matches "===== FAILURES =====" style section headers.
+	pytestSectionRe = regexp.MustCompile(`^=+ (.+?) =+$`)
+	// pytestTestHeaderRe matches "_____ test_name _____" failure headers.
+	pytestTestHeaderRe = regexp.MustCompile(`^_{3,} (.+?) _{3,}$`)
+	// pytestFrameSepRe matches the "_ _ _ _" separator between traceback frames.
+	pytestFrameSepRe = regexp.MustCompile(`^(_ ){3,}_?$`)
+	// pytestCaptureRe matches "----- Captured stdout call -----" headers.
+	pytestCaptureRe = regexp.MustCompile(`^-+ (Captured .+|.* log .+) -+$`)
+	// pytestLocationRe matches a long-format frame's location line "path.py:12: Exc".
+	pytestLocationRe = regexp.MustCompile(`^(\S+\.py):(\d+):`)
+	// pytestShortFrameRe matches a --tb=short frame header "path.py:12: in func".
+	pytestShortFrameRe = regexp.MustCompile(`^(\S+\.py):\d+: in \S+`)
+	// pytestProgressRe matches progress lines: "tests/test_a.py ..F.s  [ 40%]".
+	pytestProgressRe = regexp.MustCompile(`^(\S+\.py )?[.FEsxXp]+\s*(\[\s*\d+%\])?$`)
+	// pytestVerboseRe matches -v result lines: "tests/test_a.py::test_x PASSED  [ 40%]".
+	pytestVerboseRe = regexp.MustCompile(`^(\S+::\S.*?) (PASSED|FAILED|ERROR|SKIPPED|XFAIL|XPASS)(?: \(.*\))?(\s+\[\s*\d+%\])?$`)
+	// pytestHeaderInfoRe matches the session header's environment lines.
+	pytestHeaderInfoRe = regexp.MustCompile(`^(platform|cachedir|rootdir|configfile|inifile|testpaths|plugins|collecting|collected|asyncio|hypothesis|django|metadata|benchmark|Using)[: ]`)
+	// pytestSummaryRe matches short test summary entries: "FAILED tests/test_a.py::test_x - msg".
+	pytestSummaryRe = regexp.MustCompile(`^(FAILED|ERROR) (\S+)`)
+	// pytestTallyRe recognizes the final tally section, e.g. "1 failed, 40 passed in 0.52s".
+	pytestTallyRe = regexp.MustCompile(`\b(passed|failed|errors?|skipped|deselected|xfailed|xpassed|warnings?|no tests ran)\b.* in [\d.]+s`)
+)
+
+// pytestCaptureShown caps the lines kept from each captured output section.
+const pytestCaptureShown = 20
+
+// isPythonLibraryPath reports whether a traceback path is outside the user's
+// code: installed packages, the standard library or frozen modules.
+func isPythonLibraryPath(path string) bool {
+	return strings.Contains(path, "site-packages/") ||
+		strings.Contains(path, "dist-packages/") ||
+		strings.Contains(path, "/lib/python") ||
+		strings.HasPrefix(path, "<frozen")
+}
+
+// ShortenPaths opts in to path shortening: traceback frames run through
+// site-packages.
+func (s *PytestStrategy) ShortenPaths() {}
+
+func (s *PytestStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	section := ""
+	var block []string // current failure/error block, trimmed on flush
+
+	flush := func() {
+		if len(block) > 0 {
+			// --tb=native tracebacks and tracebacks in captured output
+			block, _ = condensePythonTracebacks(block)
+			out = append(out, trimPytestFailure(block)...)
+			block = nil
+		}
+	}
+
+	for _, line := range lines {
+		if m := pytestSectionRe.FindStringSubmatch(line); m != nil {
+			flush()
+			section = strings.ToLower(m[1])
+			if section != "test session starts" && section != "warnings summary" {
+				out = append(out, line)
+			}
+			continue
+		}
+
+		switch section {
+		case "", "test session starts":
+			if strings.TrimSpace(line) == "" || pytestHeaderInfoRe.MatchString(line) ||
+				pytestProgressRe.MatchString(line) || pytestVerboseRe.MatchString(line) {
+				continue
+			}
+			out = append(out, line)
+		case "warnings summary":
+			// The tally already counts warnings; the full list stays in the log
+		case "failures", "errors":
+			if pytestTestHeaderRe.MatchString(line) {
+				flush()
+			}
+			block = append(block, line)
+		default:
+			if strings.TrimSpace(line) != "" {
+				out = append(out, line)
+			}
+		}
+	}
+	flush()
+
+	// Nothing recognizable as pytest output
+	if len(out) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// trimPytestFailure trims one FAILURES/ERRORS block: traceback frames in
+// library code are replaced by a count (keeping their "E" lines when the
+// exception was raised there) and captured output sections are capped.
+func trimPytestFailure(block []string) []string {
+	var out []string
+	if len(block) > 0 && pytestTestHeaderRe.MatchString(block[0]) {
+		out = append(out, block[0])
+		block = block[1:]
+	}
+
+	// Split into chunks: frames (long format) and captured output sections
+	var chunks [][]string
+	var cur []string
+	for _, line := range block {
+		if pytestFrameSepRe.MatchString(line) || pytestCaptureRe.MatchString(line) {
+			chunks = append(chunks, cur)
+			cur = nil
+			if pytestFrameSepRe.MatchString(line) {
+				continue
+			}
+		}
+		cur = append(cur, line)
+	}
+	chunks = append(chunks, cur)
+
+	hidden := 0
+	flushHidden := func() {
+		if hidden > 0 {
+			out = append(out, fmt.Sprintf("    (%d library frames hidden)", hidden))
+			hidden = 0
+		}
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk) == 0 {
+			continue
+		}
+		if pytestCaptureRe.MatchString(chunk[0]) {
+			flushHidden()
+			out = append(out, capPytestCapture(chunk)...)
+			continue
+		}
+		if hasPytestShortFrames(chunk) {
+			out = append(out, trimPytestShortFrames(chunk)...)
+			continue
+		}
+		if !pytestChunkIsLibrary(chunk) {
+			flushHidden()
+			out = append(out, chunk...)
+			continue
+		}
+		hidden++
+		// The exception was raised in library code: keep what it says and where
+		var raised []string
+		location := ""
+		for _, line := range chunk {
+			if strings.HasPrefix(line, "E ") {
+				raised = append(raised, line)
+			} else if pytestLocationRe.MatchString(line) {
+				location = line
+			}
+		}
+		if len(raised) > 0 {
+			flushHidden()
+			out = append(out, raised...)
+			out = append(out, location)
+		}
+	}
+	flushHidden()
+	return out
+}
+
+// pytestChunkIsLibrary reports whether a long-format frame's location line
+// points into library code.
+func pytestChunkIsLibrary(chunk []string) bool {
+	for i := len(chunk) - 1; i >= 0; i-- {
+		if m := pytestLocationRe.FindStringSubmatch(chunk[i]); m != nil {
+			return isPythonLibraryPath(m[1])
+		}
+	}
+	return false
+}
+
+// hasPytestShortFrames reports whether chunk is a --tb=short traceback.
+func hasPytestShortFrames(chunk []string) bool {
+	for _, line := range chunk {
+		if pytestShortFrameRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimPytestShortFrames drops --tb=short frames in library code: the
+// "path:line: in func" header and its indented source lines.
+func trimPytestShortFrames(chunk []string) []string {
+	var out []string
+	skipping := false
+	hidden := 0
+	for _, line := range chunk {
+		if m := pytestShortFrameRe.FindStringSubmatch(line); m != nil {
+			skipping = isPythonLibraryPath(m[1])
+			if skipping {
+				hidden++
+				continue
+			}
+		} else if skipping && strings.HasPrefix(line, " ") {
+			continue
+		} else {
+			skipping = false
+		}
+		if hidden > 0 {
+			out = append(out, fmt.Sprintf("    (%d library frames hidden)", hidden))
+			hidden = 0
+		}
+		out = append(out, line)
+	}
+	if hidden > 0 {
+		out = append(out, fmt.Sprintf("    (%d library frames hidden)", hidden))
+	}
+	return out
+}
+
+// capPytestCapture keeps the header and first lines of a captured output section.
+func capPytestCapture(chunk []string) []string {
+	// Trailing blank lines separate sections; they carry nothing
+	for len(chunk) > 1 && strings.TrimSpace(chunk[len(chunk)-1]) == "" {
+		chunk = chunk[:len(chunk)-1]
+	}
+	if len(chunk)-1 <= pytestCaptureShown {
+		return chunk
+	}
+	out := append([]string{}, chunk[:pytestCaptureShown+1]...)
+	return append(out, fmt.Sprintf("... %d more lines", len(chunk)-1-pytestCaptureShown))
+}
+
+// ParseOutcome extracts failing tests from the short test summary and, with
+// -v, passing tests from the per-test result lines. Test ids are pytest node
+// ids such as "tests/test_a.py::test_x".
+func (s *PytestStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	var o Outcome
+	found := false
+	section := ""
+	for _, line := range strings.Split(StripANSIString(string(raw)), "\n") {
+		if m := pytestSectionRe.FindStringSubmatch(line); m != nil {
+			section = strings.ToLower(m[1])
+			if pytestTallyRe.MatchString(m[1]) {
+				found = true
+			}
+			continue
+		}
+		if section == "short test summary info" {
+			if m := pytestSummaryRe.FindStringSubmatch(line); m != nil {
+				o.Failed = append(o.Failed, m[2])
+			}
+			continue
+		}
+		if m := pytestVerboseRe.FindStringSubmatch(line); m != nil && m[2] == "PASSED" {
+			o.Passed = append(o.Passed, m[1])
+		}
+	}
+	return o, found
+}
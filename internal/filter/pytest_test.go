@@ -0,0 +1,238 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// PytestStrategy
+// ---------------------------------------------------------------------------
+
+func TestPytestStrategy_CanHandle(t *testing.T) {
+	s := &PytestStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"pytest", "pytest", []string{"-q"}, true},
+		{"py.test", "py.test", nil, true},
+		{"python -m pytest", "python", []string{"-m", "pytest", "tests/"}, true},
+		{"python3 with flags", "python3", []string{"-X", "dev", "-m", "pytest"}, true},
+		{"python3 -W value", "python3", []string{"-W", "error", "-X", "importtime", "-m", "pytest", "-q"}, true},
+		{"python -c", "python", []string{"-c", "import pytest", "-m", "pytest"}, false},
+		{"python3 -B -m pytest", "python3", []string{"-B", "-m", "pytest"}, true},
+		{"python -m pip", "python", []string{"-m", "pip", "install"}, false},
+		{"python script", "python", []string{"pytest.py"}, false},
+		{"not python", "node", []string{"-m", "pytest"}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPytestStrategy_Name(t *testing.T) {
+	if got := (&PytestStrategy{}).Name(); got != "pytest" {
+		t.Errorf("Name() = %q, want %q", got, "pytest")
+	}
+}
+
+// pytestFailingRun is a pytest session with one failure whose exception is
+// raised inside an installed package.
+const pytestFailingRun = `============================= test session starts ==============================
+platform linux -- Python 3.12.1, pytest-8.0.0, pluggy-1.4.0
+rootdir: /work/proj
+configfile: pyproject.toml
+plugins: cov-4.1.0
+collected 42 items
+
+tests/test_api.py ......F.....                                           [ 28%]
+tests/test_calc.py ..............................                        [100%]
+
+=================================== FAILURES ===================================
+_________________________________ test_fetch ___________________________________
+
+    def test_fetch():
+>       assert fetch("http://localhost:1") == 200
+
+tests/test_api.py:12:
+_ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _
+
+url = 'http://localhost:1'
+
+    def fetch(url):
+>       return requests.get(url).status_code
+
+src/proj/api.py:8:
+_ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _
+
+url = 'http://localhost:1', params = None, kwargs = {}
+
+    def get(url, params=None, **kwargs):
+>       return request("get", url, params=params, **kwargs)
+
+/work/proj/.venv/lib/python3.12/site-packages/requests/api.py:73:
+_ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _ _
+
+self = <requests.adapters.HTTPAdapter object at 0x7f>
+
+    def send(self, request):
+>           raise ConnectionError(e, request=request)
+E           requests.exceptions.ConnectionError: Connection refused
+
+/work/proj/.venv/lib/python3.12/site-packages/requests/adapters.py:519: ConnectionError
+----------------------------- Captured stdout call -----------------------------
+fetching http://localhost:1
+=============================== warnings summary ===============================
+tests/test_calc.py::test_div
+  /work/proj/tests/test_calc.py:5: DeprecationWarning: old api
+    warnings.warn("old api", DeprecationWarning)
+
+-- Docs: https://docs.pytest.org/en/stable/how-to/capture-warnings.html
+=========================== short test summary info ============================
+FAILED tests/test_api.py::test_fetch - requests.exceptions.ConnectionError: Connection refused
+=================== 1 failed, 41 passed, 1 warning in 0.52s ====================
+`
+
+func TestPytestStrategy_Filter_Failures(t *testing.T) {
+	s := &PytestStrategy{}
+	result := s.Filter([]byte(pytestFailingRun), "pytest", nil, 1)
+
+	want := `=================================== FAILURES ===================================
+_________________________________ test_fetch ___________________________________
+
+    def test_fetch():
+>       assert fetch("http://localhost:1") == 200
+
+tests/test_api.py:12:
+
+url = 'http://localhost:1'
+
+    def fetch(url):
+>       return requests.get(url).status_code
+
+src/proj/api.py:8:
+    (2 library frames hidden)
+E           requests.exceptions.ConnectionError: Connection refused
+/work/proj/.venv/lib/python3.12/site-packages/requests/adapters.py:519: ConnectionError
+----------------------------- Captured stdout call -----------------------------
+fetching http://localhost:1
+=========================== short test summary info ============================
+FAILED tests/test_api.py::test_fetch - requests.exceptions.ConnectionError: Connection refused
+=================== 1 failed, 41 passed, 1 warning in 0.52s ====================
+`
+
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestPytestStrategy_Filter_VerbosePassingRun(t *testing.T) {
+	s := &PytestStrategy{}
+
+	var b strings.Builder
+	b.WriteString("============================= test session starts ==============================\n")
+	b.WriteString("platform linux -- Python 3.12.1, pytest-8.0.0, pluggy-1.4.0\n")
+	b.WriteString("collected 12 items\n\n")
+	for i := range 12 {
+		fmt.Fprintf(&b, "tests/test_calc.py::test_case_%d PASSED%s[%3d%%]\n", i, strings.Repeat(" ", 30), (i+1)*100/12)
+	}
+	b.WriteString("\n============================== 12 passed in 0.10s ==============================\n")
+
+	result := s.Filter([]byte(b.String()), "pytest", []string{"-v"}, 0)
+
+	want := "============================== 12 passed in 0.10s ==============================\n"
+	if result.Filtered != want {
+		t.Errorf("Filtered = %q, want %q", result.Filtered, want)
+	}
+}
+
+func TestPytestStrategy_Filter_ShortTracebacks(t *testing.T) {
+	s := &PytestStrategy{}
+	input := `tests/test_api.py F                                                      [100%]
+
+=================================== FAILURES ===================================
+__________________________________ test_fetch __________________________________
+tests/test_api.py:12: in test_fetch
+    assert fetch("http://localhost:1") == 200
+src/proj/api.py:8: in fetch
+    return requests.get(url).status_code
+.venv/lib/python3.12/site-packages/requests/api.py:73: in get
+    return request("get", url, params=params, **kwargs)
+.venv/lib/python3.12/site-packages/requests/adapters.py:519: in send
+    raise ConnectionError(e, request=request)
+E   requests.exceptions.ConnectionError: Connection refused
+=========================== short test summary info ============================
+FAILED tests/test_api.py::test_fetch - requests.exceptions.ConnectionError
+============================== 1 failed in 0.05s ===============================
+`
+	result := s.Filter([]byte(input), "pytest", []string{"--tb=short"}, 1)
+
+	if strings.Contains(result.Filtered, "site-packages") {
+		t.Errorf("library frames should be hidden, got:\n%s", result.Filtered)
+	}
+	for _, want := range []string{
+		"src/proj/api.py:8: in fetch\n    return requests.get(url).status_code\n    (2 library frames hidden)\nE   requests.exceptions.ConnectionError",
+		"FAILED tests/test_api.py::test_fetch",
+	} {
+		if !strings.Contains(result.Filtered, want) {
+			t.Errorf("missing %q, got:\n%s", want, result.Filtered)
+		}
+	}
+	if strings.Contains(result.Filtered, "[100%]") {
+		t.Errorf("progress lines should be dropped, got:\n%s", result.Filtered)
+	}
+}
+
+func TestPytestStrategy_Filter_CapsCapturedOutput(t *testing.T) {
+	s := &PytestStrategy{}
+	var b strings.Builder
+	b.WriteString("=================================== FAILURES ===================================\n")
+	b.WriteString("___________________________________ test_log ___________________________________\n")
+	b.WriteString("tests/test_log.py:3: in test_log\n    assert False\nE   assert False\n")
+	b.WriteString("----------------------------- Captured stdout call -----------------------------\n")
+	for i := range 50 {
+		fmt.Fprintf(&b, "log line %d\n", i)
+	}
+	b.WriteString("============================== 1 failed in 0.05s ===============================\n")
+
+	result := s.Filter([]byte(b.String()), "pytest", nil, 1)
+
+	if !strings.Contains(result.Filtered, "log line 19\n... 30 more lines\n") {
+		t.Errorf("captured output should be capped, got:\n%s", result.Filtered)
+	}
+}
+
+func TestPytestStrategy_ParseOutcome(t *testing.T) {
+	s := &PytestStrategy{}
+	input := "tests/test_a.py::test_ok PASSED                                   [ 50%]\n" +
+		"tests/test_a.py::test_bad FAILED                                  [100%]\n" +
+		"=========================== short test summary info ============================\n" +
+		"FAILED tests/test_a.py::test_bad - assert 1 == 2\n" +
+		"ERROR tests/test_b.py::test_db - RuntimeError: no db\n" +
+		"==================== 1 failed, 1 passed, 1 error in 0.03s =====================\n"
+
+	o, ok := s.ParseOutcome([]byte(input), "pytest", []string{"-v"}, 1)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if got := strings.Join(o.Failed, ","); got != "tests/test_a.py::test_bad,tests/test_b.py::test_db" {
+		t.Errorf("Failed = %q", got)
+	}
+	if got := strings.Join(o.Passed, ","); got != "tests/test_a.py::test_ok" {
+		t.Errorf("Passed = %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Python traceback condenser
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for Python tracebacks.
+var (
+	// pyTracebackRe matches a traceback header. It may be indented: pip shows
+	// a failed build backend's output indented under its error.
+	pyTracebackRe = regexp.MustCompile(`^(\s*)Traceback \(most recent call last\):$`)
+	// pyFrameRe matches a frame's location line: `  File "/app/x.py", line 12, in main`.
+	pyFrameRe = regexp.MustCompile(`^(\s*)File "([^"]+)", line \d+(?:, in .+)?$`)
+)
+
+// pyFrame is one traceback frame: its location line followed by its source
+// and caret lines.
+type pyFrame struct {
+	path  string
+	lines []string
+}
+
+// pythonPackageGroup names the package a library frame is counted under:
+// "django" for site-packages/django/core/handlers/base.py, "asyncio" for
+// /usr/lib/python3.12/asyncio/runners.py, "importlib" for <frozen importlib._bootstrap>.
+func pythonPackageGroup(path string) string {
+	for _, marker := range []string{"site-packages/", "dist-packages/"} {
+		if i := strings.LastIndex(path, marker); i >= 0 {
+			name, _, _ := strings.Cut(path[i+len(marker):], "/")
+			return strings.TrimSuffix(name, ".py")
+		}
+	}
+	if after, ok := strings.CutPrefix(path, "<frozen "); ok {
+		name, _, _ := strings.Cut(strings.TrimSuffix(after, ">"), ".")
+		return name
+	}
+	if i := strings.Index(path, "/lib/python"); i >= 0 {
+		// Skip the version directory: "/lib/python3.12/asyncio/runners.py"
+		if _, rest, ok := strings.Cut(path[i+len("/lib/python"):], "/"); ok {
+			name, _, _ := strings.Cut(rest, "/")
+			return strings.TrimSuffix(name, ".py")
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".py")
+}
+
+// condensePythonTracebacks condenses each Python traceback in lines: frames
+// in the project are kept with their source lines, as is the innermost frame
+// so the raise site stays visible, and runs of frames in installed packages or
+// the standard library are folded into "… N frames in django, asyncio" lines.
+// Exception lines and the "During handling of the above exception" markers
+// between chained tracebacks are kept as printed. A traceback identical to an
+// earlier one (same frames and exception) keeps only its header, a note and
+// its exception. It reports whether any traceback was found.
+func condensePythonTracebacks(lines []string) ([]string, bool) {
+	seen := map[string]bool{}
+	var out []string
+	found := false
+	for i := 0; i < len(lines); {
+		m := pyTracebackRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+		indent := m[1]
+		out = append(out, lines[i])
+
+		var frames []pyFrame
+		j := i + 1
+		for ; j < len(lines); j++ {
+			line := lines[j]
+			if fm := pyFrameRe.FindStringSubmatch(line); fm != nil && fm[1] == indent+"  " {
+				frames = append(frames, pyFrame{path: fm[2], lines: []string{line}})
+				continue
+			}
+			// Source and caret lines, and "[Previous line repeated N more times]"
+			if len(frames) > 0 && (strings.HasPrefix(line, indent+"    ") ||
+				strings.HasPrefix(strings.TrimSpace(line), "[Previous line repeated")) {
+				f := &frames[len(frames)-1]
+				f.lines = append(f.lines, line)
+				continue
+			}
+			break
+		}
+		i = j
+		if len(frames) == 0 {
+			continue
+		}
+		found = true
+
+		// The exception line right after the frames is part of the identity
+		key := ""
+		for _, f := range frames {
+			key += strings.Join(f.lines, "\n") + "\n"
+		}
+		if j < len(lines) {
+			key += lines[j]
+		}
+		if seen[key] {
+			out = append(out, indent+"  … same traceback as above")
+			continue
+		}
+		seen[key] = true
+		out = append(out, renderPythonFrames(frames, indent)...)
+	}
+	return out, found
+}
+
+// renderPythonFrames keeps project frames and the innermost frame and folds
+// the others. A fold of a single frame keeps the frame itself.
+func renderPythonFrames(frames []pyFrame, indent string) []string {
+	var out []string
+	var folded []pyFrame
+	var groups []string
+	flush := func() {
+		switch {
+		case len(folded) == 1:
+			out = append(out, folded[0].lines...)
+		case len(folded) > 1:
+			out = append(out, fmt.Sprintf("%s  … %d frames in %s", indent, len(folded), frameGroupsLabel(groups)))
+		}
+		folded, groups = nil, nil
+	}
+
+	for i, f := range frames {
+		if i == len(frames)-1 || !isPythonLibraryPath(f.path) {
+			flush()
+			out = append(out, f.lines...)
+			continue
+		}
+		folded = append(folded, f)
+		if g := pythonPackageGroup(f.path); !slices.Contains(groups, g) {
+			groups = append(groups, g)
+		}
+	}
+	flush()
+	return out
+}
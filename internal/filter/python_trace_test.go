@@ -0,0 +1,216 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// djangoTraceback is a `manage.py migrate` failure: a KeyError in the
+// project's settings, re-raised by Django as ImproperlyConfigured.
+const djangoTraceback = `Traceback (most recent call last):
+  File "/work/shop/shop/settings.py", line 14, in <module>
+    SECRET_KEY = os.environ["SECRET_KEY"]
+                 ~~~~~~~~~~^^^^^^^^^^^^^^
+  File "<frozen os>", line 679, in __getitem__
+KeyError: 'SECRET_KEY'
+
+During handling of the above exception, another exception occurred:
+
+Traceback (most recent call last):
+  File "/work/shop/manage.py", line 22, in <module>
+    main()
+  File "/work/shop/manage.py", line 18, in main
+    execute_from_command_line(sys.argv)
+  File "/work/shop/.venv/lib/python3.12/site-packages/django/core/management/__init__.py", line 442, in execute_from_command_line
+    utility.execute()
+  File "/work/shop/.venv/lib/python3.12/site-packages/django/core/management/__init__.py", line 382, in execute
+    settings.INSTALLED_APPS
+  File "/work/shop/.venv/lib/python3.12/site-packages/django/conf/__init__.py", line 89, in __getattr__
+    self._setup(name)
+  File "/usr/lib/python3.12/importlib/__init__.py", line 90, in import_module
+    return _bootstrap._gcd_import(name[level:], package, level)
+  File "<frozen importlib._bootstrap>", line 1387, in _gcd_import
+  File "<frozen importlib._bootstrap>", line 1360, in _find_and_load
+  File "/work/shop/shop/settings.py", line 16, in <module>
+    raise ImproperlyConfigured("SECRET_KEY is not set")
+  File "/work/shop/.venv/lib/python3.12/site-packages/django/core/exceptions.py", line 40, in __init__
+    super().__init__(msg)
+django.core.exceptions.ImproperlyConfigured: SECRET_KEY is not set
+`
+
+func TestCondensePythonTracebacks(t *testing.T) {
+	got, found := condensePythonTracebacks(strings.Split(djangoTraceback, "\n"))
+	if !found {
+		t.Fatal("expected a traceback")
+	}
+
+	want := `Traceback (most recent call last):
+  File "/work/shop/shop/settings.py", line 14, in <module>
+    SECRET_KEY = os.environ["SECRET_KEY"]
+                 ~~~~~~~~~~^^^^^^^^^^^^^^
+  File "<frozen os>", line 679, in __getitem__
+KeyError: 'SECRET_KEY'
+
+During handling of the above exception, another exception occurred:
+
+Traceback (most recent call last):
+  File "/work/shop/manage.py", line 22, in <module>
+    main()
+  File "/work/shop/manage.py", line 18, in main
+    execute_from_command_line(sys.argv)
+  … 6 frames in django, importlib
+  File "/work/shop/shop/settings.py", line 16, in <module>
+    raise ImproperlyConfigured("SECRET_KEY is not set")
+  File "/work/shop/.venv/lib/python3.12/site-packages/django/core/exceptions.py", line 40, in __init__
+    super().__init__(msg)
+django.core.exceptions.ImproperlyConfigured: SECRET_KEY is not set
+`
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("condensed mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestCondensePythonTracebacks_DedupesIdenticalTracebacks(t *testing.T) {
+	tb := "Traceback (most recent call last):\n" +
+		"  File \"/work/app/worker.py\", line 9, in handle\n" +
+		"    return payload[\"id\"]\n" +
+		"KeyError: 'id'"
+	input := "job 1 failed\n" + tb + "\njob 2 failed\n" + tb + "\n"
+
+	got, _ := condensePythonTracebacks(strings.Split(input, "\n"))
+
+	want := "job 1 failed\n" + tb + "\njob 2 failed\n" +
+		"Traceback (most recent call last):\n" +
+		"  … same traceback as above\n" +
+		"KeyError: 'id'\n"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Errorf("condensed mismatch.\ngot:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestCondensePythonTracebacks_NoTraceback(t *testing.T) {
+	lines := []string{"Traceback (most recent call last):", "nothing useful", "done"}
+	got, found := condensePythonTracebacks(lines)
+	if found {
+		t.Error("expected no traceback")
+	}
+	if strings.Join(got, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("lines should be unchanged, got %q", got)
+	}
+}
+
+func TestPythonPackageGroup(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/venv/lib/python3.12/site-packages/django/core/handlers/base.py", "django"},
+		{"/usr/lib/python3/dist-packages/pip/_vendor/pyproject_hooks/_impl.py", "pip"},
+		{"/venv/lib/python3.12/site-packages/six.py", "six"},
+		{"/usr/lib/python3.12/asyncio/runners.py", "asyncio"},
+		{"/usr/lib/python3.12/subprocess.py", "subprocess"},
+		{"<frozen importlib._bootstrap>", "importlib"},
+		{"<frozen runpy>", "runpy"},
+	}
+	for _, tc := range tests {
+		if got := pythonPackageGroup(tc.path); got != tc.want {
+			t.Errorf("pythonPackageGroup(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestGenericErrorStrategy_Filter_CondensesPythonTraceback(t *testing.T) {
+	s := &GenericErrorStrategy{}
+	input := "Operations to perform:\n  Apply all migrations: admin, auth\n" + djangoTraceback
+
+	result := s.Filter([]byte(input), "python", []string{"manage.py", "migrate"}, 1)
+
+	if !strings.Contains(result.Filtered, "  … 6 frames in django, importlib\n") {
+		t.Errorf("expected condensed traceback, got:\n%s", result.Filtered)
+	}
+	if !strings.HasPrefix(result.Filtered, "Operations to perform:\n") {
+		t.Errorf("output around the traceback should be kept, got:\n%s", result.Filtered)
+	}
+}
+
+// pipBuildFailure is a `pip install` failing to build an sdist, which pip
+// reports on stderr.
+const pipBuildFailure = `Collecting legacy-pkg==0.3
+  Downloading legacy-pkg-0.3.tar.gz (12 kB)
+  Installing build dependencies: started
+  Installing build dependencies: finished with status 'done'
+  Getting requirements to build wheel: started
+  Getting requirements to build wheel: finished with status 'error'
+  error: subprocess-exited-with-error
+
+  × Getting requirements to build wheel did not run successfully.
+  │ exit code: 1
+  ╰─> [12 lines of output]
+      Traceback (most recent call last):
+        File "/usr/lib/python3/dist-packages/pip/_vendor/pyproject_hooks/_in_process/_in_process.py", line 353, in <module>
+          main()
+        File "/usr/lib/python3/dist-packages/pip/_vendor/pyproject_hooks/_in_process/_in_process.py", line 335, in main
+          json_out['return_val'] = hook(**hook_input['kwargs'])
+        File "/tmp/pip-build-env-x1/overlay/lib/python3.12/site-packages/setuptools/build_meta.py", line 325, in get_requires_for_build_wheel
+          return self._get_build_requires(config_settings, requirements=['wheel'])
+        File "<string>", line 4, in <module>
+      ModuleNotFoundError: No module named 'numpy'
+      [end of output]
+`
+
+func TestProgressStripStrategy_Filter_CondensesPipBuildTraceback(t *testing.T) {
+	s := &ProgressStripStrategy{}
+	input := pipBuildFailure
+	result := s.Filter([]byte(input), "pip", []string{"install", "legacy-pkg==0.3"}, 1)
+
+	want := `      Traceback (most recent call last):
+        … 3 frames in pip, setuptools
+        File "<string>", line 4, in <module>
+      ModuleNotFoundError: No module named 'numpy'
+`
+	if !strings.Contains(result.Filtered, want) {
+		t.Errorf("expected condensed traceback, got:\n%s", result.Filtered)
+	}
+	if stderr := s.FilterStderr([]byte(input), "pip", []string{"install", "legacy-pkg==0.3"}, 1); stderr != result {
+		t.Errorf("FilterStderr differs from Filter:\n%s", stderr.Filtered)
+	}
+}
+
+func TestPytestStrategy_Filter_CondensesNativeTracebacks(t *testing.T) {
+	s := &PytestStrategy{}
+	input := `============================= test session starts ==============================
+collected 3 items
+
+tests/test_api.py .F.                                                    [100%]
+
+=================================== FAILURES ===================================
+__________________________________ test_fetch __________________________________
+Traceback (most recent call last):
+  File "/work/app/tests/test_api.py", line 12, in test_fetch
+    resp = client.fetch("/items")
+  File "/work/app/app/client.py", line 30, in fetch
+    return self.session.get(self.base + path, timeout=1)
+  File "/work/app/.venv/lib/python3.12/site-packages/requests/sessions.py", line 602, in get
+    return self.request("GET", url, **kwargs)
+  File "/work/app/.venv/lib/python3.12/site-packages/requests/sessions.py", line 589, in request
+    resp = self.send(prep, **send_kwargs)
+  File "/work/app/.venv/lib/python3.12/site-packages/requests/adapters.py", line 519, in send
+    raise ConnectionError(e, request=request)
+requests.exceptions.ConnectionError: connection refused
+=========================== short test summary info ============================
+FAILED tests/test_api.py::test_fetch - requests.exceptions.ConnectionError: connection refused
+========================= 1 failed, 2 passed in 0.31s ==========================
+`
+	result := s.Filter([]byte(input), "pytest", []string{"--tb=native"}, 1)
+
+	want := `  File "/work/app/app/client.py", line 30, in fetch
+    return self.session.get(self.base + path, timeout=1)
+  … 2 frames in requests
+  File "/work/app/.venv/lib/python3.12/site-packages/requests/adapters.py", line 519, in send
+    raise ConnectionError(e, request=request)
+requests.exceptions.ConnectionError: connection refused
+`
+	if !strings.Contains(result.Filtered, want) {
+		t.Errorf("expected condensed native traceback, got:\n%s", result.Filtered)
+	}
+}
@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Go race detector report condenser
+// ---------------------------------------------------------------------------
+
+// Package-level compiled regexes for race detector reports.
+var (
+	// raceSectionRe matches section headers such as "Read at 0x00c0000a0 by goroutine 8:"
+	// and "Goroutine 8 (running) created at:".
+	raceSectionRe = regexp.MustCompile(`^(Read|Write|Previous read|Previous write|Atomic read|Atomic write|Previous atomic read|Previous atomic write)( at 0x[0-9a-f]+)? by .*:$|^Goroutine \d+ \(.*\) created at:$`)
+	// raceFuncRe matches a frame's function line, indented by two spaces.
+	raceFuncRe = regexp.MustCompile(`^  (\S.*)$`)
+	// raceFileRe matches a frame's location line, indented by six spaces.
+	raceFileRe = regexp.MustCompile(`^      (\S+:\d+)(?: \+0x[0-9a-f]+)?$`)
+	// raceAddrRe matches the memory address in an access header.
+	raceAddrRe = regexp.MustCompile(` at 0x[0-9a-f]+`)
+)
+
+const (
+	raceSeparator = "=================="
+	raceWarning   = "WARNING: DATA RACE"
+)
+
+// raceSection is one stack of a race report: an access or a goroutine creation.
+type raceSection struct {
+	title  string // address removed
+	frames []goFrame
+}
+
+// raceReport is one "WARNING: DATA RACE" block.
+type raceReport struct {
+	sections []raceSection
+	other    []string // lines that are not part of a section
+}
+
+// key identifies a race by its two accesses: kind and innermost location of
+// each, ignoring order so reports with swapped current/previous roles match.
+func (r *raceReport) key() string {
+	var sides []string
+	for _, sec := range r.sections {
+		if strings.HasPrefix(sec.title, "Goroutine ") {
+			continue
+		}
+		kind := strings.TrimPrefix(strings.ToLower(sec.title[:strings.Index(sec.title, " by ")]), "previous ")
+		// The innermost non-runtime frame: races inside runtime helpers such as
+		// map access would otherwise all share one location
+		loc := ""
+		for _, f := range sec.frames {
+			if loc == "" {
+				loc = f.location
+			}
+			if !isGoStdFrame(f.fn) {
+				loc = f.location
+				break
+			}
+		}
+		sides = append(sides, kind+" "+loc)
+	}
+	sort.Strings(sides)
+	return strings.Join(sides, " / ")
+}
+
+// parseRaceReport parses the report whose "WARNING: DATA RACE" line is at
+// lines[start]. It returns the report and the index just past its closing
+// separator.
+func parseRaceReport(lines []string, start int) (*raceReport, int) {
+	r := &raceReport{}
+	var cur *raceSection
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == raceSeparator {
+			i++
+			break
+		}
+		if raceSectionRe.MatchString(line) {
+			r.sections = append(r.sections, raceSection{title: raceAddrRe.ReplaceAllString(line, "")})
+			cur = &r.sections[len(r.sections)-1]
+			continue
+		}
+		if cur != nil && i+1 < len(lines) {
+			if fm := raceFuncRe.FindStringSubmatch(line); fm != nil {
+				if lm := raceFileRe.FindStringSubmatch(lines[i+1]); lm != nil {
+					cur.frames = append(cur.frames, goFrame{fn: goFuncName(fm[1]), location: lm[1]})
+					i++
+					continue
+				}
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		r.other = append(r.other, line)
+	}
+	return r, i
+}
+
+// condenseRaceReports replaces `-race` reports in lines with one condensed
+// report per distinct race (grouped by the pair of racing locations), shown
+// where it first occurred with its number of reports and trimmed stacks.
+// A summary line follows the last report. It reports whether any race was found.
+func condenseRaceReports(lines []string) ([]string, bool) {
+	type raceGroup struct {
+		report *raceReport
+		count  int
+	}
+
+	// First pass: parse every report so counts are known when rendering
+	type span struct{ start, end int }
+	var spans []span
+	var reports []*raceReport
+	for i := 0; i < len(lines); i++ {
+		if lines[i] != raceWarning || i == 0 || lines[i-1] != raceSeparator {
+			continue
+		}
+		r, end := parseRaceReport(lines, i)
+		spans = append(spans, span{i - 1, end})
+		reports = append(reports, r)
+		i = end - 1
+	}
+	if len(reports) == 0 {
+		return lines, false
+	}
+
+	var groups []*raceGroup
+	index := map[string]*raceGroup{}
+	groupOf := make([]*raceGroup, len(reports))
+	for n, r := range reports {
+		k := r.key()
+		g, ok := index[k]
+		if !ok {
+			g = &raceGroup{report: r}
+			index[k] = g
+			groups = append(groups, g)
+		}
+		g.count++
+		groupOf[n] = g
+	}
+
+	// Second pass: emit each distinct race at its first occurrence
+	module := goModulePath()
+	var out []string
+	prev := 0
+	emitted := map[*raceGroup]bool{}
+	for n, sp := range spans {
+		out = append(out, lines[prev:sp.start]...)
+		prev = sp.end
+		g := groupOf[n]
+		if !emitted[g] {
+			emitted[g] = true
+			out = append(out, renderRaceGroup(g.report, g.count, module)...)
+		}
+		if n == len(spans)-1 {
+			out = append(out, fmt.Sprintf("data races: %d reports, %d distinct races", len(reports), len(groups)))
+		}
+	}
+	out = append(out, lines[prev:]...)
+	return out, true
+}
+
+// renderRaceGroup renders one distinct race with trimmed stacks.
+func renderRaceGroup(r *raceReport, count int, module string) []string {
+	header := raceWarning
+	if count > 1 {
+		header = fmt.Sprintf("%s (%d reports)", raceWarning, count)
+	}
+	out := []string{raceSeparator, header}
+	for _, sec := range r.sections {
+		out = append(out, sec.title)
+		out = append(out, renderGoFrames(sec.frames, module)...)
+	}
+	out = append(out, r.other...)
+	return append(out, raceSeparator)
+}
@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// Race detector report condenser
+// ---------------------------------------------------------------------------
+
+// raceReportFixture renders a race detector report between two accesses.
+func raceReportFixture(access, accessFn string, accessLine int, prev, prevFn string, prevLine int) string {
+	return "==================\n" +
+		"WARNING: DATA RACE\n" +
+		fmt.Sprintf("%s at 0x00c00002a3a0 by goroutine 9:\n", access) +
+		fmt.Sprintf("  example.com/racedemo.(*Counter).%s()\n", accessFn) +
+		fmt.Sprintf("      /tmp/racedemo/counter.go:%d +0x7d\n", accessLine) +
+		"  example.com/racedemo.TestRace.func2()\n" +
+		"      /tmp/racedemo/counter_test.go:21 +0x12\n" +
+		"\n" +
+		fmt.Sprintf("%s at 0x00c00002a3a0 by goroutine 8:\n", prev) +
+		fmt.Sprintf("  example.com/racedemo.(*Counter).%s()\n", prevFn) +
+		fmt.Sprintf("      /tmp/racedemo/counter.go:%d +0x8f\n", prevLine) +
+		"\n" +
+		"Goroutine 9 (running) created at:\n" +
+		"  example.com/racedemo.TestRace()\n" +
+		"      /tmp/racedemo/counter_test.go:21 +0x11a\n" +
+		"  testing.tRunner()\n" +
+		"      /usr/local/go/src/testing/testing.go:2193 +0x21c\n" +
+		"==================\n"
+}
+
+func TestCondenseRaceReports(t *testing.T) {
+	input := "=== RUN   TestRace\n" +
+		raceReportFixture("Read", "Get", 11, "Previous write", "Inc", 10) +
+		raceReportFixture("Write", "Inc", 10, "Previous read", "Get", 11) +
+		raceReportFixture("Read", "Get", 11, "Previous write", "Inc", 10) +
+		raceReportFixture("Write", "Reset", 14, "Previous write", "Inc", 10) +
+		"--- FAIL: TestRace (0.00s)\n" +
+		"    testing.go:1865: race detected during execution of test\n"
+
+	out, found := condenseRaceReports(strings.Split(input, "\n"))
+	if !found {
+		t.Fatal("expected race reports to be found")
+	}
+
+	want := "=== RUN   TestRace\n" +
+		"==================\n" +
+		"WARNING: DATA RACE (3 reports)\n" +
+		"Read by goroutine 9:\n" +
+		"  example.com/racedemo.(*Counter).Get  /tmp/racedemo/counter.go:11\n" +
+		"  example.com/racedemo.TestRace.func2  /tmp/racedemo/counter_test.go:21\n" +
+		"Previous write by goroutine 8:\n" +
+		"  example.com/racedemo.(*Counter).Inc  /tmp/racedemo/counter.go:10\n" +
+		"Goroutine 9 (running) created at:\n" +
+		"  example.com/racedemo.TestRace  /tmp/racedemo/counter_test.go:21\n" +
+		"  (1 runtime/stdlib frames hidden)\n" +
+		"==================\n" +
+		"==================\n" +
+		"WARNING: DATA RACE\n" +
+		"Write by goroutine 9:\n" +
+		"  example.com/racedemo.(*Counter).Reset  /tmp/racedemo/counter.go:14\n" +
+		"  example.com/racedemo.TestRace.func2  /tmp/racedemo/counter_test.go:21\n" +
+		"Previous write by goroutine 8:\n" +
+		"  example.com/racedemo.(*Counter).Inc  /tmp/racedemo/counter.go:10\n" +
+		"Goroutine 9 (running) created at:\n" +
+		"  example.com/racedemo.TestRace  /tmp/racedemo/counter_test.go:21\n" +
+		"  (1 runtime/stdlib frames hidden)\n" +
+		"==================\n" +
+		"data races: 4 reports, 2 distinct races\n" +
+		"--- FAIL: TestRace (0.00s)\n" +
+		"    testing.go:1865: race detected during execution of test\n"
+
+	// Module lookup depends on the working directory; example.com/racedemo is never the current module
+	if got := strings.Join(out, "\n"); got != want {
+		t.Errorf("condensed mismatch.\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCondenseRaceReports_NoRace(t *testing.T) {
+	lines := []string{"==================", "not a race", "=================="}
+	out, found := condenseRaceReports(lines)
+	if found || len(out) != len(lines) {
+		t.Errorf("expected lines unchanged, got %q", out)
+	}
+}
+
+func TestGoTestStrategy_Filter_CondensesRaces(t *testing.T) {
+	s := &GoTestStrategy{}
+	var b strings.Builder
+	b.WriteString("=== RUN   TestRace\n")
+	for range 10 {
+		b.WriteString(raceReportFixture("Read", "Get", 11, "Previous write", "Inc", 10))
+	}
+	b.WriteString("--- FAIL: TestRace (0.00s)\n" +
+		"    testing.go:1865: race detected during execution of test\n" +
+		"FAIL\n" +
+		"FAIL\tracedemo\t0.020s\n" +
+		"FAIL\n")
+
+	result := s.Filter([]byte(b.String()), "go", []string{"test", "-race", "./..."}, 1)
+
+	if strings.Count(result.Filtered, "WARNING: DATA RACE") != 1 {
+		t.Errorf("expected a single race report, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "WARNING: DATA RACE (10 reports)") {
+		t.Errorf("expected report count, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "data races: 10 reports, 1 distinct races") {
+		t.Errorf("expected race summary, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "FAIL\tracedemo\t0.020s") {
+		t.Errorf("package summary should be kept, got:\n%s", result.Filtered)
+	}
+}
@@ -24,6 +24,28 @@ func (r *Registry) Find(command string, args []string) Strategy {
 	return r.fallback
 }
 
+// Lookup returns the strategy with the given name, including the fallback.
+func (r *Registry) Lookup(name string) (Strategy, bool) {
+	for _, s := range r.strategies {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	if r.fallback.Name() == name {
+		return r.fallback, true
+	}
+	return nil, false
+}
+
+// Names returns the names of all strategies in priority order, fallback last.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.strategies)+1)
+	for _, s := range r.strategies {
+		names = append(names, s.Name())
+	}
+	return append(names, r.fallback.Name())
+}
+
 // DefaultRegistry returns a registry with all built-in strategies.
 // Phase 3: git, go, cargo, docker, grep, progress, and generic error filters.
 func DefaultRegistry() *Registry {
@@ -32,14 +54,45 @@ func DefaultRegistry() *Registry {
 		&GitStatusStrategy{},
 		&GitDiffStrategy{},
 		&GitLogStrategy{},
-		// Go strategies
+		// Go strategies (structured -json before text)
+		&GoTestJSONStrategy{},
 		&GoTestStrategy{},
+		&GoLintStrategy{},
+		&GoModStrategy{},
 		&GoBuildStrategy{},
-		// Cargo strategies
+		// Cargo strategies (structured JSON before text)
+		&CargoJSONStrategy{},
 		&CargoTestStrategy{},
+		&CargoLintStrategy{Subcommand: "clippy"},
+		&CargoLintStrategy{Subcommand: "check"},
 		&CargoBuildStrategy{},
-		// Docker strategies
+		// Python test runners
+		&PytestStrategy{},
+		// JavaScript tools (directly, via npx/pnpm/yarn/bun, or a package script)
+		&TscStrategy{},
+		&JSLintStrategy{},
+		&JSTestStrategy{},
+		// C/C++ builds (make, ninja, cmake --build, compilers)
+		&CBuildStrategy{},
+		// JVM builds (Maven, Gradle and their project wrappers)
+		&MavenStrategy{},
+		&GradleStrategy{},
+		// Kubernetes (kubectl get, describe and logs)
+		&KubectlGetStrategy{},
+		&KubectlDescribeStrategy{},
+		&KubectlLogsStrategy{},
+		// Infrastructure as code (Terraform, OpenTofu) and rendered manifests
+		&TerraformStrategy{},
+		&HelmStrategy{},
+		&KustomizeStrategy{},
+		// Docker strategies (compose first: it filters `compose build` like docker build)
+		&DockerComposeStrategy{},
 		&DockerBuildStrategy{},
+		&DockerPsStrategy{},
+		&DockerImagesStrategy{},
+		&DockerLogsStrategy{},
+		// Log clustering (journalctl; any command with --filter log-cluster)
+		&LogClusterStrategy{},
 		// Grep/rg grouping
 		&GrepGroupStrategy{},
 		// Progress strip (package managers, docker pull/push)
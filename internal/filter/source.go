@@ -0,0 +1,34 @@
+package filter
+
+import "path/filepath"
+
+// sourceCommands print file contents: source code, configuration and diffs.
+var sourceCommands = map[string]bool{
+	"cat": true, "bat": true, "batcat": true, "head": true, "tail": true,
+	"less": true, "more": true, "nl": true, "sed": true, "awk": true,
+	"grep": true, "egrep": true, "fgrep": true, "rg": true, "ag": true, "ack": true,
+	"diff": true,
+}
+
+// gitSourceSubcommands are the git subcommands that print file contents.
+var gitSourceSubcommands = []string{"diff", "show", "log", "blame", "grep", "format-patch"}
+
+// PrintsSource reports whether a command prints file contents, such as `cat`,
+// `grep` or `git diff`. Its output must reach the agent exactly as in the file,
+// so secret redaction leaves it alone: an agent editing code from a redacted
+// view would write the placeholder back.
+func PrintsSource(command string, args []string) bool {
+	name := filepath.Base(command)
+	if sourceCommands[name] {
+		return true
+	}
+	if name != "git" {
+		return false
+	}
+	for _, sub := range gitSourceSubcommands {
+		if isSubcommand(args, sub, gitValueFlags) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package filter
+
+import "testing"
+
+func TestPrintsSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"cat", "cat", []string{"main.go"}, true},
+		{"rg", "/usr/bin/rg", []string{"token"}, true},
+		{"git diff", "git", []string{"diff", "HEAD"}, true},
+		{"git -C show", "git", []string{"-C", "repo", "show", "HEAD"}, true},
+		{"git status", "git", []string{"status"}, false},
+		{"env", "env", nil, false},
+		{"kubectl", "kubectl", []string{"get", "secret", "-o", "yaml"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PrintsSource(tc.command, tc.args); got != tc.want {
+				t.Errorf("PrintsSource(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
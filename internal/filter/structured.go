@@ -0,0 +1,71 @@
+package filter
+
+import "strings"
+
+// StructuredArgs rewrites args so the command emits a machine-readable format
+// that coc has a dedicated strategy for (e.g. `go test` → `go test -json`,
+// `cargo build` → `cargo build --message-format=json`).
+// Commands without a structured strategy, or that already request one, are
+// returned unchanged. The caller opts in; the raw structured output is what
+// ends up in the log file.
+func StructuredArgs(command string, args []string) []string {
+	if command == "go" && isSubcommand(args, "test", goValueFlags) && !hasGoJSONFlag(args) {
+		return insertAfterSubcommand(args, goValueFlags, "-json")
+	}
+	if command == "cargo" && !hasCargoJSONFormat(args) && !hasCargoMessageFormat(args) &&
+		(isSubcommand(args, "build", cargoValueFlags) ||
+			isSubcommand(args, "check", cargoValueFlags) ||
+			isSubcommand(args, "clippy", cargoValueFlags)) {
+		return insertAfterSubcommand(args, cargoValueFlags, "--message-format=json")
+	}
+	return args
+}
+
+// insertAfterSubcommand returns a copy of args with extra inserted right after
+// the first non-flag argument (the subcommand), so it is parsed as a flag of
+// that subcommand rather than of the tool or the program under test.
+func insertAfterSubcommand(args []string, valueFlags map[string]bool, extra ...string) []string {
+	idx := subcommandIndex(args, valueFlags)
+	if idx < 0 {
+		return args
+	}
+	out := make([]string, 0, len(args)+len(extra))
+	out = append(out, args[:idx+1]...)
+	out = append(out, extra...)
+	return append(out, args[idx+1:]...)
+}
+
+// subcommandIndex returns the index of the first non-flag argument in args,
+// skipping values of flags listed in valueFlags, or -1 if there is none.
+func subcommandIndex(args []string, valueFlags map[string]bool) int {
+	skip := false
+	for i, a := range args {
+		if skip {
+			skip = false
+			continue
+		}
+		if valueFlags[a] {
+			skip = true
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// hasCargoMessageFormat reports whether args already choose a message format,
+// in which case the user's choice is left alone.
+func hasCargoMessageFormat(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if a == "--message-format" || strings.HasPrefix(a, "--message-format=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tableColumnRe matches one column name in a table header; names may contain
+// single spaces ("CONTAINER ID", "NOMINATED NODE").
+var tableColumnRe = regexp.MustCompile(`\S+(?: \S+)*`)
+
+// tableColumns locates the columns of a table aligned with Go's tabwriter,
+// as printed by kubectl and docker. Values are cut at the header's column
+// offsets, since values such as "3 (2m ago)" or "Up 2 hours" contain spaces.
+type tableColumns struct {
+	index  map[string]int // column name → index into starts
+	starts []int
+}
+
+func newTableColumns(header string) tableColumns {
+	c := tableColumns{index: map[string]int{}}
+	for i, loc := range tableColumnRe.FindAllStringIndex(header, -1) {
+		c.index[header[loc[0]:loc[1]]] = i
+		c.starts = append(c.starts, loc[0])
+	}
+	return c
+}
+
+// has reports whether the table has the named column.
+func (c tableColumns) has(name string) bool {
+	_, ok := c.index[name]
+	return ok
+}
+
+// field returns the value of the named column in row, or "" if the table has
+// no such column.
+func (c tableColumns) field(row, name string) string {
+	i, ok := c.index[name]
+	if !ok || c.starts[i] >= len(row) {
+		return ""
+	}
+	end := len(row)
+	if i+1 < len(c.starts) && c.starts[i+1] < end {
+		end = c.starts[i+1]
+	}
+	return strings.TrimSpace(row[c.starts[i]:end])
+}
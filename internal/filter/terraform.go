@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// terraformValueFlags are plan/apply flags that may take their value as the
+// next argument (`-var name=value`) rather than after "=".
+var terraformValueFlags = map[string]bool{
+	"-var": true, "-var-file": true, "-target": true, "-replace": true,
+	"-lock-timeout": true, "-parallelism": true,
+}
+
+// ---------------------------------------------------------------------------
+// TerraformStrategy
+// ---------------------------------------------------------------------------
+
+// TerraformStrategy reduces `terraform plan`, `apply` and `destroy` output
+// (and OpenTofu's `tofu`) to the resource-level changes, the attributes that
+// force replacement, the Plan/Apply summary and any errors and warnings.
+// Attribute diffs, the action legend and refresh/progress lines are dropped.
+type TerraformStrategy struct{}
+
+func (s *TerraformStrategy) Name() string { return "terraform" }
+
+func (s *TerraformStrategy) CanHandle(command string, args []string) bool {
+	switch filepath.Base(command) {
+	case "terraform", "tofu":
+	default:
+		return false
+	}
+	for _, sub := range []string{"plan", "apply", "destroy"} {
+		if isSubcommand(args, sub, terraformValueFlags) {
+			return true
+		}
+	}
+	return false
+}
+
+// Package-level compiled regexes for TerraformStrategy.
+var (
+	// tfProgressRe matches refresh and apply progress lines:
+	// "aws_vpc.main: Refreshing state... [id=vpc-0abc]", "aws_instance.web: Still creating... [10s elapsed]".
+	tfProgressRe = regexp.MustCompile(`^\S+: (?:(?:Reading|Refreshing state|Still \w+|Creating|Modifying|Destroying)\.\.\.|(?:Read|Creation|Modifications|Destruction) complete after)`)
+	// tfResourceRe matches a resource change header: "  # aws_instance.web will be created".
+	tfResourceRe = regexp.MustCompile(`^\s*# [^\s(]\S* (?:will|must|has|is) `)
+	// tfSummaryRe matches the lines that close a plan or apply.
+	tfSummaryRe = regexp.MustCompile(`^(?:Plan: \d+ to add|No changes\.|Apply complete!|Destroy complete!|Apply cancelled)`)
+	// tfSectionRe matches section headings worth keeping before resource headers.
+	tfSectionRe = regexp.MustCompile(`^(?:Terraform|OpenTofu) will perform the following actions:|^Note: Objects have changed outside of (?:Terraform|OpenTofu)`)
+	// tfOutputsRe matches the output blocks, kept up to the next blank line.
+	tfOutputsRe = regexp.MustCompile(`^(?:Changes to Outputs|Outputs):$`)
+	// tfDiagRe matches an error or warning printed with -no-color (no box).
+	tfDiagRe = regexp.MustCompile(`^(?:Error|Warning): `)
+)
+
+func (s *TerraformStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	inBox := false     // inside a ╷ … ╵ diagnostic box
+	inOutputs := false // inside an Outputs block
+	keepRest := false  // after an unboxed Error:/Warning:, everything is kept
+	for _, line := range lines {
+		if tfProgressRe.MatchString(line) {
+			continue
+		}
+
+		switch {
+		case keepRest:
+			out = append(out, line)
+		case inBox:
+			out = append(out, line)
+			inBox = !strings.HasPrefix(line, "╵")
+		case strings.HasPrefix(line, "╷"):
+			out = appendBlankSeparated(out, line)
+			inBox = true
+		case tfDiagRe.MatchString(line):
+			out = appendBlankSeparated(out, line)
+			keepRest = true
+		case inOutputs:
+			if strings.TrimSpace(line) == "" {
+				inOutputs = false
+				continue
+			}
+			out = append(out, line)
+		case tfOutputsRe.MatchString(line):
+			out = appendBlankSeparated(out, line)
+			inOutputs = true
+		case tfSectionRe.MatchString(line), tfSummaryRe.MatchString(line):
+			out = appendBlankSeparated(out, line)
+		case tfResourceRe.MatchString(line), strings.Contains(line, "# forces replacement"):
+			out = append(out, line)
+		}
+	}
+	// Drop blank lines copied from the end of the output
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	if len(filtered) >= len(cleaned) {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+	return Result{Filtered: filtered, WasReduced: true}
+}
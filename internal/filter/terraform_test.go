@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerraformStrategy_CanHandle(t *testing.T) {
+	s := &TerraformStrategy{}
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"plan", "terraform", []string{"plan"}, true},
+		{"plan with chdir", "terraform", []string{"-chdir=infra", "plan", "-out=tfplan"}, true},
+		{"apply", "terraform", []string{"apply", "-auto-approve"}, true},
+		{"destroy", "terraform", []string{"destroy"}, true},
+		{"tofu plan", "tofu", []string{"plan"}, true},
+		{"path", "/usr/local/bin/terraform", []string{"plan"}, true},
+		{"init", "terraform", []string{"init"}, false},
+		{"validate", "terraform", []string{"validate"}, false},
+		{"no args", "terraform", nil, false},
+		{"not terraform", "terragrunt", []string{"plan"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := s.CanHandle(tc.command, tc.args); got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+const terraformPlan = `data.aws_ami.ubuntu: Reading...
+aws_vpc.main: Refreshing state... [id=vpc-0abc]
+aws_security_group.web: Refreshing state... [id=sg-0123]
+aws_s3_bucket.logs: Refreshing state... [id=acme-logs]
+data.aws_ami.ubuntu: Read complete after 1s [id=ami-0ff8a91507f77f867]
+
+Terraform used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+  - destroy
+-/+ destroy and then create replacement
+
+Terraform will perform the following actions:
+
+  # aws_instance.web will be created
+  + resource "aws_instance" "web" {
+      + ami                          = "ami-0ff8a91507f77f867"
+      + arn                          = (known after apply)
+      + id                           = (known after apply)
+      + instance_type                = "t3.micro"
+      + private_ip                   = (known after apply)
+      + root_block_device {
+          + volume_size = 20
+          + volume_type = (known after apply)
+        }
+    }
+
+  # aws_s3_bucket.logs will be updated in-place
+  ~ resource "aws_s3_bucket" "logs" {
+        id   = "acme-logs"
+      ~ tags = {
+          + "env" = "prod"
+        }
+        # (10 unchanged attributes hidden)
+    }
+
+  # aws_security_group.web must be replaced
+-/+ resource "aws_security_group" "web" {
+      ~ arn         = "arn:aws:ec2:eu-west-1:123:security-group/sg-0123" -> (known after apply)
+      ~ id          = "sg-0123" -> (known after apply)
+      ~ name        = "web" -> "web-sg" # forces replacement
+        # (3 unchanged attributes hidden)
+    }
+
+  # aws_iam_role.old will be destroyed
+  - resource "aws_iam_role" "old" {
+      - arn  = "arn:aws:iam::123:role/old" -> null
+      - name = "old" -> null
+    }
+
+Plan: 2 to add, 1 to change, 2 to destroy.
+
+Changes to Outputs:
+  + web_ip = (known after apply)
+
+╷
+│ Warning: Argument is deprecated
+│ 
+│   with aws_s3_bucket.logs,
+│   on main.tf line 40, in resource "aws_s3_bucket" "logs":
+│   40:   acl = "private"
+│ 
+│ Use the aws_s3_bucket_acl resource instead
+╵
+
+─────────────────────────────────────────────────────────────────────────────
+
+Note: You didn't use the -out option to save this plan, so Terraform can't
+guarantee to take exactly these actions if you run "terraform apply" now.
+`
+
+func TestTerraformStrategy_Filter_Plan(t *testing.T) {
+	s := &TerraformStrategy{}
+	result := s.Filter([]byte(terraformPlan), "terraform", []string{"plan"}, 0)
+
+	want := `Terraform will perform the following actions:
+  # aws_instance.web will be created
+  # aws_s3_bucket.logs will be updated in-place
+  # aws_security_group.web must be replaced
+      ~ name        = "web" -> "web-sg" # forces replacement
+  # aws_iam_role.old will be destroyed
+
+Plan: 2 to add, 1 to change, 2 to destroy.
+
+Changes to Outputs:
+  + web_ip = (known after apply)
+
+╷
+│ Warning: Argument is deprecated
+│ 
+│   with aws_s3_bucket.logs,
+│   on main.tf line 40, in resource "aws_s3_bucket" "logs":
+│   40:   acl = "private"
+│ 
+│ Use the aws_s3_bucket_acl resource instead
+╵
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+func TestTerraformStrategy_Filter_ApplyError(t *testing.T) {
+	s := &TerraformStrategy{}
+	input := `aws_vpc.main: Refreshing state... [id=vpc-0abc]
+aws_subnet.a: Refreshing state... [id=subnet-1]
+aws_subnet.b: Refreshing state... [id=subnet-2]
+
+Terraform will perform the following actions:
+
+  # aws_instance.web will be created
+  + resource "aws_instance" "web" {
+      + ami           = "ami-0ff8a91507f77f867"
+      + instance_type = "t3.nano"
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+aws_instance.web: Creating...
+aws_instance.web: Still creating... [10s elapsed]
+aws_instance.web: Still creating... [20s elapsed]
+
+Error: creating EC2 Instance: InvalidParameterCombination: The specified instance type is not eligible for Free Tier.
+	status code: 400, request id: 5f2c9a1e-0b7d-4e8e-9c1a-3d2b4f6a7c8e
+
+  with aws_instance.web,
+  on main.tf line 12, in resource "aws_instance" "web":
+  12: resource "aws_instance" "web" {
+
+`
+	result := s.Filter([]byte(input), "terraform", []string{"apply", "-auto-approve", "-no-color"}, 1)
+
+	want := `Terraform will perform the following actions:
+  # aws_instance.web will be created
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+
+Error: creating EC2 Instance: InvalidParameterCombination: The specified instance type is not eligible for Free Tier.
+	status code: 400, request id: 5f2c9a1e-0b7d-4e8e-9c1a-3d2b4f6a7c8e
+
+  with aws_instance.web,
+  on main.tf line 12, in resource "aws_instance" "web":
+  12: resource "aws_instance" "web" {
+`
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestTerraformStrategy_Filter_NoChanges(t *testing.T) {
+	s := &TerraformStrategy{}
+	var b strings.Builder
+	for _, r := range []string{"aws_vpc.main", "aws_subnet.a", "aws_subnet.b", "aws_route_table.main", "aws_instance.web", "aws_eip.web", "aws_s3_bucket.logs", "aws_iam_role.app"} {
+		b.WriteString(r + ": Refreshing state... [id=x]\n")
+	}
+	b.WriteString("\nNo changes. Your infrastructure matches the configuration.\n\n")
+	b.WriteString("Terraform has compared your real infrastructure against your configuration\nand found no differences, so no changes are needed.\n")
+
+	result := s.Filter([]byte(b.String()), "tofu", []string{"plan"}, 0)
+
+	want := "No changes. Your infrastructure matches the configuration.\n"
+	if result.Filtered != want {
+		t.Errorf("filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
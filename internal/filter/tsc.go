@@ -0,0 +1,285 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// TscStrategy
+// ---------------------------------------------------------------------------
+
+// TscStrategy groups TypeScript compiler diagnostics by file and error code.
+// A diagnostic repeated across files (same code and message, e.g. a missing
+// module) is collapsed into a single entry listing its locations.
+type TscStrategy struct{}
+
+func (s *TscStrategy) Name() string { return "tsc" }
+
+func (s *TscStrategy) CanHandle(command string, args []string) bool {
+	tool, _ := jsTool(command, args)
+	return tool == "tsc" || tool == "vue-tsc"
+}
+
+// Package-level compiled regexes for TscStrategy.
+var (
+	// tscPlainRe matches the plain format: "src/a.ts(12,5): error TS2322: msg".
+	tscPlainRe = regexp.MustCompile(`^(\S.*?)\((\d+),(\d+)\): (error|warning|message) (TS\d+): (.*)$`)
+	// tscPrettyRe matches the --pretty format: "src/a.ts:12:5 - error TS2322: msg".
+	tscPrettyRe = regexp.MustCompile(`^(\S.*?):(\d+):(\d+) - (error|warning|message) (TS\d+): (.*)$`)
+	// tscGlobalRe matches diagnostics without a location, e.g. config errors.
+	tscGlobalRe = regexp.MustCompile(`^(error|warning|message) (TS\d+): (.*)$`)
+	// tscFrameRe matches --pretty code frame lines: "12   const x = 1;".
+	tscFrameRe = regexp.MustCompile(`^\s*\d+ `)
+	// tscUnderlineRe matches --pretty squiggle lines under a code frame.
+	tscUnderlineRe = regexp.MustCompile(`^\s*~+\s*$`)
+	// tscRelatedRe matches a --pretty related information location: "  src/types.ts:3:3".
+	tscRelatedRe = regexp.MustCompile(`^\s+(\S+:\d+:\d+)$`)
+	// tscSummaryRe matches tsc's own summary and its per-file table.
+	tscSummaryRe = regexp.MustCompile(`^Found \d+ errors?|^Errors\s+Files$|^\s+\d+\s+\S+:\d+$`)
+)
+
+const (
+	// tscMaxFiles caps the files listed individually.
+	tscMaxFiles = 25
+	// tscMaxLocations caps the locations listed for one grouped diagnostic.
+	tscMaxLocations = 4
+	// tscMaxDetails caps the message chain and related information lines kept
+	// per diagnostic.
+	tscMaxDetails = 2
+)
+
+// tscDiag is one parsed compiler diagnostic.
+type tscDiag struct {
+	file     string // empty for global diagnostics
+	line     int
+	col      int
+	severity string
+	code     string
+	message  string
+	details  []string // message chain and related information
+}
+
+// location returns "file:line:col" for the diagnostic.
+func (d *tscDiag) location() string {
+	return fmt.Sprintf("%s:%d:%d", d.file, d.line, d.col)
+}
+
+// parseTscDiagnostics parses plain and --pretty tsc output. Lines that are not
+// part of a diagnostic or tsc's summary are returned as other.
+func parseTscDiagnostics(lines []string) (diags []*tscDiag, other []string) {
+	var cur *tscDiag
+	related := "" // pending --pretty related location, waiting for its message
+	for _, line := range lines {
+		var d *tscDiag
+		if m := tscPlainRe.FindStringSubmatch(line); m != nil {
+			d = &tscDiag{file: m[1], severity: m[4], code: m[5], message: m[6]}
+			d.line, _ = strconv.Atoi(m[2])
+			d.col, _ = strconv.Atoi(m[3])
+		} else if m := tscPrettyRe.FindStringSubmatch(line); m != nil {
+			d = &tscDiag{file: m[1], severity: m[4], code: m[5], message: m[6]}
+			d.line, _ = strconv.Atoi(m[2])
+			d.col, _ = strconv.Atoi(m[3])
+		} else if m := tscGlobalRe.FindStringSubmatch(line); m != nil {
+			d = &tscDiag{severity: m[1], code: m[2], message: m[3]}
+		}
+		if d != nil {
+			diags = append(diags, d)
+			cur, related = d, ""
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", tscUnderlineRe.MatchString(line):
+			continue
+		case tscSummaryRe.MatchString(line):
+			cur = nil
+			continue
+		case cur == nil, !strings.HasPrefix(line, " ") && !tscFrameRe.MatchString(line):
+			cur = nil
+			other = append(other, line)
+		case tscRelatedRe.MatchString(line):
+			related = tscRelatedRe.FindStringSubmatch(line)[1]
+		case tscFrameRe.MatchString(line):
+			// Source excerpt: the location already says where
+		case related != "":
+			cur.details = append(cur.details, fmt.Sprintf("%s (%s)", trimmed, related))
+			related = ""
+		default:
+			cur.details = append(cur.details, trimmed)
+		}
+	}
+	return diags, other
+}
+
+// ShortenPaths opts in to path shortening for diagnostic file names.
+func (s *TscStrategy) ShortenPaths() {}
+
+func (s *TscStrategy) Filter(raw []byte, command string, args []string, exitCode int) (result Result) {
+	filterName := s.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "coc: filter %s recovered from panic: %v\n", filterName, r)
+			result = Result{Filtered: string(raw), WasReduced: false}
+		}
+	}()
+
+	cleaned := StripANSIString(string(raw))
+	hadTrailing := endsWithNewline(cleaned)
+	lines := strings.Split(cleaned, "\n")
+
+	// Small output — pass through
+	if len(lines) < 10 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	diags, other := parseTscDiagnostics(lines)
+	if len(diags) == 0 {
+		return Result{Filtered: cleaned, WasReduced: false}
+	}
+
+	var out []string
+	out = append(out, other...)
+	out = append(out, renderTscDiagnostics(diags)...)
+
+	filtered := strings.Join(out, "\n")
+	filtered = ensureTrailingNewline(filtered, hadTrailing)
+	return Result{Filtered: filtered, WasReduced: len(filtered) < len(cleaned)}
+}
+
+// renderTscDiagnostics renders diagnostics repeated across files first, then
+// the rest grouped by file and by error code, then a total line.
+func renderTscDiagnostics(diags []*tscDiag) []string {
+	type group struct {
+		code, severity, message string
+		diags                   []*tscDiag // details are taken from the first
+		files                   map[string]bool
+	}
+
+	var groups []*group
+	byKey := map[string]*group{}
+	files := map[string]bool{}
+	errors := 0
+	for _, d := range diags {
+		if d.severity == "error" {
+			errors++
+		}
+		if d.file != "" {
+			files[d.file] = true
+		}
+		key := d.code + " " + d.message
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{code: d.code, severity: d.severity, message: d.message, files: map[string]bool{}}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.diags = append(g.diags, d)
+		g.files[d.file] = true
+	}
+
+	var out []string
+	detailLines := func(details []string, indent string) {
+		for i, detail := range details {
+			if i == tscMaxDetails {
+				out = append(out, fmt.Sprintf("%s... %d more lines", indent, len(details)-tscMaxDetails))
+				break
+			}
+			out = append(out, indent+detail)
+		}
+	}
+
+	// Diagnostics without a file, and those repeated across files
+	perFile := map[string][]*group{}
+	var fileOrder []string
+	repeatedHeader := false
+	for _, g := range groups {
+		if g.files[""] {
+			out = append(out, fmt.Sprintf("%s %s: %s", g.severity, g.code, g.message))
+			detailLines(g.diags[0].details, "  ")
+			continue
+		}
+		if len(g.files) > 1 {
+			if !repeatedHeader {
+				out = append(out, "repeated across files:")
+				repeatedHeader = true
+			}
+			out = append(out, fmt.Sprintf("  %s %s (%d in %d files)", g.code, g.message, len(g.diags), len(g.files)))
+			detailLines(g.diags[0].details, "    ")
+			var locs []string
+			for _, d := range g.diags {
+				locs = append(locs, d.location())
+			}
+			suffix := ""
+			if len(locs) > tscMaxLocations {
+				suffix = fmt.Sprintf(" +%d more", len(locs)-tscMaxLocations)
+				locs = locs[:tscMaxLocations]
+			}
+			out = append(out, "    "+strings.Join(locs, ", ")+suffix)
+			continue
+		}
+		file := g.diags[0].file
+		if _, ok := perFile[file]; !ok {
+			fileOrder = append(fileOrder, file)
+		}
+		perFile[file] = append(perFile[file], g)
+	}
+
+	hiddenFiles, hiddenDiags := 0, 0
+	for n, file := range fileOrder {
+		fgroups := perFile[file]
+		if n >= tscMaxFiles {
+			hiddenFiles++
+			for _, g := range fgroups {
+				hiddenDiags += len(g.diags)
+			}
+			continue
+		}
+		sort.SliceStable(fgroups, func(i, j int) bool { return fgroups[i].code < fgroups[j].code })
+		out = append(out, file+":")
+		for _, g := range fgroups {
+			var pos []string
+			for _, d := range g.diags {
+				pos = append(pos, fmt.Sprintf("%d:%d", d.line, d.col))
+			}
+			suffix := ""
+			if len(pos) > tscMaxLocations {
+				suffix = fmt.Sprintf(" +%d more", len(pos)-tscMaxLocations)
+				pos = pos[:tscMaxLocations]
+			}
+			sev := ""
+			if g.severity != "error" {
+				sev = g.severity + " "
+			}
+			out = append(out, fmt.Sprintf("  %s%s %s: %s%s", sev, g.code, strings.Join(pos, ", "), g.message, suffix))
+			detailLines(g.diags[0].details, "    ")
+		}
+	}
+	if hiddenFiles > 0 {
+		out = append(out, fmt.Sprintf("... and %d more files (%d diagnostics)", hiddenFiles, hiddenDiags))
+	}
+
+	out = append(out, "")
+	out = append(out, fmt.Sprintf("Found %d errors in %d files", errors, len(files)))
+	return out
+}
+
+// ParseOutcome reports each diagnostic as "file:line:col: error TSxxxx: message"
+// so `--since-last` can show new and fixed type errors.
+func (s *TscStrategy) ParseOutcome(raw []byte, command string, args []string, exitCode int) (Outcome, bool) {
+	diags, _ := parseTscDiagnostics(strings.Split(StripANSIString(string(raw)), "\n"))
+	var o Outcome
+	for _, d := range diags {
+		entry := fmt.Sprintf("%s %s: %s", d.severity, d.code, d.message)
+		if d.file != "" {
+			entry = d.location() + ": " + entry
+		}
+		o.Errors = append(o.Errors, entry)
+	}
+	return o, true
+}
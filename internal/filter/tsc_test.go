@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TscStrategy
+// ---------------------------------------------------------------------------
+
+func TestTscStrategy_CanHandle(t *testing.T) {
+	s := &TscStrategy{}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    bool
+	}{
+		{"tsc", "tsc", []string{"--noEmit"}, true},
+		{"npx tsc", "npx", []string{"tsc", "--noEmit"}, true},
+		{"pnpm tsc", "pnpm", []string{"tsc", "-p", "."}, true},
+		{"pnpm exec tsc", "pnpm", []string{"exec", "tsc"}, true},
+		{"yarn tsc", "yarn", []string{"tsc"}, true},
+		{"vue-tsc", "npx", []string{"vue-tsc", "--noEmit"}, true},
+		{"npm run typecheck", "npm", []string{"run", "typecheck"}, false},
+		{"npx eslint", "npx", []string{"eslint", "."}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.CanHandle(tc.command, tc.args)
+			if got != tc.want {
+				t.Errorf("CanHandle(%q, %v) = %v, want %v", tc.command, tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTscStrategy_Name(t *testing.T) {
+	if got := (&TscStrategy{}).Name(); got != "tsc" {
+		t.Errorf("Name() = %q, want %q", got, "tsc")
+	}
+}
+
+func TestTscStrategy_Filter_Plain(t *testing.T) {
+	s := &TscStrategy{}
+	var b strings.Builder
+	for i, f := range []string{"src/a.tsx", "src/b.tsx", "src/c.tsx", "src/d.tsx", "src/e.tsx", "src/f.tsx"} {
+		fmt.Fprintf(&b, "%s(1,19): error TS2307: Cannot find module 'react' or its corresponding type declarations.\n", f)
+		if i == 0 {
+			b.WriteString("src/a.tsx(12,5): error TS2322: Type 'string' is not assignable to type 'number'.\n")
+			b.WriteString("src/a.tsx(14,7): error TS2322: Type 'string' is not assignable to type 'number'.\n")
+			b.WriteString("src/a.tsx(9,3): error TS2304: Cannot find name 'foo'.\n")
+		}
+	}
+	b.WriteString("src/g.ts(4,7): error TS2345: Argument of type '{ a: string; }' is not assignable to parameter of type 'Props'.\n" +
+		"  Property 'b' is missing in type '{ a: string; }' but required in type 'Props'.\n")
+
+	result := s.Filter([]byte(b.String()), "npx", []string{"tsc", "--noEmit"}, 2)
+
+	want := `repeated across files:
+  TS2307 Cannot find module 'react' or its corresponding type declarations. (6 in 6 files)
+    src/a.tsx:1:19, src/b.tsx:1:19, src/c.tsx:1:19, src/d.tsx:1:19 +2 more
+src/a.tsx:
+  TS2304 9:3: Cannot find name 'foo'.
+  TS2322 12:5, 14:7: Type 'string' is not assignable to type 'number'.
+src/g.ts:
+  TS2345 4:7: Argument of type '{ a: string; }' is not assignable to parameter of type 'Props'.
+    Property 'b' is missing in type '{ a: string; }' but required in type 'Props'.
+
+Found 10 errors in 7 files
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+	if !result.WasReduced {
+		t.Error("expected WasReduced=true")
+	}
+}
+
+// tscPrettyOutput is `tsc --pretty` output with a related information block
+// and tsc's own summary table.
+const tscPrettyOutput = `src/a.ts:12:5 - error TS2322: Type 'string' is not assignable to type 'number'.
+
+12   const x: number = "a";
+         ~
+
+src/b.ts:3:1 - error TS2741: Property 'b' is missing in type '{ a: string; }' but required in type 'Props'.
+
+3 render({ a: "x" });
+  ~~~~~~~~~~~~~~~~~~
+
+  src/types.ts:3:3
+    3   b: string;
+        ~
+    'b' is declared here.
+
+error TS5023: Unknown compiler option 'strictest'.
+
+
+Found 3 errors in 2 files.
+
+Errors  Files
+     1  src/a.ts:12
+     1  src/b.ts:3
+`
+
+func TestTscStrategy_Filter_Pretty(t *testing.T) {
+	s := &TscStrategy{}
+	result := s.Filter([]byte(tscPrettyOutput), "tsc", []string{"--pretty"}, 2)
+
+	want := `error TS5023: Unknown compiler option 'strictest'.
+src/a.ts:
+  TS2322 12:5: Type 'string' is not assignable to type 'number'.
+src/b.ts:
+  TS2741 3:1: Property 'b' is missing in type '{ a: string; }' but required in type 'Props'.
+    'b' is declared here. (src/types.ts:3:3)
+
+Found 3 errors in 2 files
+`
+	if result.Filtered != want {
+		t.Errorf("Filtered mismatch.\ngot:\n%s\nwant:\n%s", result.Filtered, want)
+	}
+}
+
+func TestTscStrategy_Filter_CapsFiles(t *testing.T) {
+	s := &TscStrategy{}
+	var b strings.Builder
+	for i := range 30 {
+		fmt.Fprintf(&b, "src/f%02d.ts(%d,1): error TS2304: Cannot find name 'v%d'.\n", i, i+1, i)
+	}
+
+	result := s.Filter([]byte(b.String()), "tsc", nil, 2)
+
+	if strings.Contains(result.Filtered, "src/f25.ts") {
+		t.Errorf("files beyond the cap should be hidden, got:\n%s", result.Filtered)
+	}
+	if !strings.Contains(result.Filtered, "... and 5 more files (5 diagnostics)\n\nFound 30 errors in 30 files\n") {
+		t.Errorf("expected hidden file count and total, got:\n%s", result.Filtered)
+	}
+}
+
+func TestTscStrategy_Filter_NoDiagnostics(t *testing.T) {
+	s := &TscStrategy{}
+	input := strings.Repeat("[12:00:00 PM] File change detected. Starting incremental compilation...\n", 12)
+
+	result := s.Filter([]byte(input), "tsc", []string{"--watch"}, 0)
+
+	if result.Filtered != input || result.WasReduced {
+		t.Errorf("expected passthrough, got:\n%s", result.Filtered)
+	}
+}
+
+func TestTscStrategy_ParseOutcome(t *testing.T) {
+	s := &TscStrategy{}
+	o, ok := s.ParseOutcome([]byte(tscPrettyOutput), "tsc", nil, 2)
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := []string{
+		"src/a.ts:12:5: error TS2322: Type 'string' is not assignable to type 'number'.",
+		"src/b.ts:3:1: error TS2741: Property 'b' is missing in type '{ a: string; }' but required in type 'Props'.",
+		"error TS5023: Unknown compiler option 'strictest'.",
+	}
+	if got := strings.Join(o.Errors, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Errors = %q, want %q", o.Errors, want)
+	}
+}
@@ -0,0 +1,198 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fuabioo/coc/internal/filter"
+	"github.com/Fuabioo/coc/internal/logpath"
+)
+
+const (
+	// maxTestRuns bounds how many test runs are kept per repository.
+	maxTestRuns = 30
+	// flakyWindow is how far back runs count as "recent" for flaky detection.
+	flakyWindow = 7 * 24 * time.Hour
+)
+
+// TestRun is the recorded outcome of one test run in a repository.
+type TestRun struct {
+	Time    time.Time      `json:"time"`
+	Code    string         `json:"code,omitempty"` // see CodeFingerprint
+	Command string         `json:"command"`
+	Outcome filter.Outcome `json:"outcome"`
+}
+
+// TestHistory is the per-repository record of recent test runs.
+type TestHistory struct {
+	Root string    `json:"root"`
+	Runs []TestRun `json:"runs"`
+}
+
+// FlakyTest is a test that failed in the current run but passed recently on
+// the same code.
+type FlakyTest struct {
+	Name   string
+	Passed int // recent runs on the same code where it passed
+	Failed int // recent runs on the same code where it failed, including this one
+}
+
+// RepoRoot returns the nearest ancestor of dir containing a .git entry, or dir
+// itself when it is not inside a git repository.
+func RepoRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// CodeFingerprint identifies the state of the code in a git repository: the
+// HEAD commit, uncommitted changes to tracked files and the contents of
+// untracked files that are not ignored. It returns "" when git is unavailable,
+// in which case all recent runs are treated as the same code.
+func CodeFingerprint(root string) string {
+	head, err := exec.Command("git", "-C", root, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	diff, err := exec.Command("git", "-C", root, "diff", "HEAD", "--no-ext-diff", "--no-color").Output()
+	if err != nil {
+		return ""
+	}
+	untracked, err := exec.Command("git", "-C", root, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(head)
+	h.Write(diff)
+	for _, name := range strings.Split(string(untracked), "\x00") {
+		if name == "" {
+			continue
+		}
+		h.Write([]byte(name + "\x00"))
+		if data, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// TestHistoryPath returns the test history file for the repository at root.
+// Layout: <base-dir>/history/repos/<key>.json.
+func TestHistoryPath(flagDir, root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(logpath.BaseDir(flagDir), "history", "repos", hex.EncodeToString(sum[:])[:16]+".json")
+}
+
+// LoadTestHistory reads the test history at path. A missing file yields an
+// empty history for root.
+func LoadTestHistory(path, root string) (*TestHistory, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &TestHistory{Root: root}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading test history %s: %w", path, err)
+	}
+	var h TestHistory
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parsing test history %s: %w", path, err)
+	}
+	return &h, nil
+}
+
+// SaveTestHistory writes the test history to path atomically.
+func SaveTestHistory(path string, h *TestHistory) error {
+	return writeJSON(path, h)
+}
+
+// UpdateTestHistory loads the test history at path, passes it to update and
+// saves it, holding a lock on the file throughout so concurrent coc runs in
+// the same repository do not lose each other's runs. A history that cannot be
+// read is replaced by an empty one for root.
+func UpdateTestHistory(path, root string, update func(h *TestHistory)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating history directory %s: %w", filepath.Dir(path), err)
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	h, err := LoadTestHistory(path, root)
+	if err != nil {
+		h = &TestHistory{Root: root}
+	}
+	update(h)
+	return SaveTestHistory(path, h)
+}
+
+// Record appends run to the history, dropping the oldest runs beyond maxTestRuns.
+func (h *TestHistory) Record(run TestRun) {
+	h.Runs = append(h.Runs, run)
+	if len(h.Runs) > maxTestRuns {
+		h.Runs = h.Runs[len(h.Runs)-maxTestRuns:]
+	}
+}
+
+// FlakyTests returns the tests that failed in cur and, in recent runs on the
+// same code, both passed and failed. cur must not have been recorded yet.
+func (h *TestHistory) FlakyTests(cur TestRun) []FlakyTest {
+	var recent []TestRun
+	for _, run := range h.Runs {
+		if run.Code == cur.Code && cur.Time.Sub(run.Time) <= flakyWindow {
+			recent = append(recent, run)
+		}
+	}
+	if len(recent) == 0 {
+		return nil
+	}
+
+	var flaky []FlakyTest
+	for _, name := range cur.Outcome.Failed {
+		ft := FlakyTest{Name: name}
+		for _, run := range recent {
+			// Only an explicit pass counts: a passing package says nothing
+			// about a test that may not have run (go test -run, -failfast)
+			switch {
+			case slices.Contains(run.Outcome.Passed, name):
+				ft.Passed++
+			case slices.Contains(run.Outcome.Failed, name):
+				ft.Failed++
+			}
+		}
+		if ft.Passed > 0 && ft.Failed > 0 {
+			ft.Failed++ // this run
+			flaky = append(flaky, ft)
+		}
+	}
+	return flaky
+}
+
+// FormatFlaky renders the flaky-test notice appended to curated output.
+func FormatFlaky(flaky []FlakyTest) string {
+	if len(flaky) == 0 {
+		return ""
+	}
+	lines := []string{"possibly flaky (passed and failed on the same code recently):"}
+	for _, ft := range flaky {
+		lines = append(lines, fmt.Sprintf("  %s (failed %d, passed %d of the last %d runs)", ft.Name, ft.Failed, ft.Passed, ft.Failed+ft.Passed))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,208 @@
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fuabioo/coc/internal/filter"
+)
+
+func TestRepoRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := RepoRoot(sub); got != root {
+		t.Errorf("RepoRoot(%q) = %q, want %q", sub, got, root)
+	}
+
+	plain := t.TempDir()
+	if got := RepoRoot(plain); got != plain {
+		t.Errorf("RepoRoot outside a repo = %q, want %q", got, plain)
+	}
+}
+
+func TestTestHistoryRecordBounded(t *testing.T) {
+	h := &TestHistory{}
+	for i := 0; i < maxTestRuns+5; i++ {
+		h.Record(TestRun{Command: "go test"})
+	}
+	if len(h.Runs) != maxTestRuns {
+		t.Errorf("len(Runs) = %d, want %d", len(h.Runs), maxTestRuns)
+	}
+}
+
+func TestFlakyTests(t *testing.T) {
+	now := time.Now()
+	h := &TestHistory{Runs: []TestRun{
+		{Time: now.Add(-time.Hour), Code: "c1", Outcome: filter.Outcome{Passed: []string{"pkg TestA"}}},
+		{Time: now.Add(-50 * time.Minute), Code: "c1", Outcome: filter.Outcome{Failed: []string{"other TestB"}}},
+		{Time: now.Add(-30 * time.Minute), Code: "c1", Outcome: filter.Outcome{Failed: []string{"pkg TestA"}}},
+		{Time: now.Add(-20 * time.Minute), Code: "c2", Outcome: filter.Outcome{Passed: []string{"other TestB"}}},
+		{Time: now.Add(-30 * 24 * time.Hour), Code: "c1", Outcome: filter.Outcome{Passed: []string{"other TestC"}}},
+	}}
+	cur := TestRun{
+		Time:    now,
+		Code:    "c1",
+		Outcome: filter.Outcome{Failed: []string{"pkg TestA", "other TestB", "other TestC"}},
+	}
+
+	flaky := h.FlakyTests(cur)
+	if len(flaky) != 1 {
+		t.Fatalf("expected 1 flaky test, got %+v", flaky)
+	}
+	if flaky[0].Name != "pkg TestA" || flaky[0].Passed != 1 || flaky[0].Failed != 2 {
+		t.Errorf("unexpected flaky entry %+v", flaky[0])
+	}
+}
+
+func TestFlakyTestsNeedsRecordedFailure(t *testing.T) {
+	now := time.Now()
+	h := &TestHistory{Runs: []TestRun{
+		{Time: now.Add(-time.Hour), Code: "c1", Outcome: filter.Outcome{Passed: []string{"pkg TestA"}}},
+		{Time: now.Add(-30 * time.Minute), Code: "c1", Outcome: filter.Outcome{Passed: []string{"pkg TestA"}}},
+	}}
+	cur := TestRun{Time: now, Code: "c1", Outcome: filter.Outcome{Failed: []string{"pkg TestA"}}}
+
+	// A first failure after passes is a regression, not yet a flaky test
+	if flaky := h.FlakyTests(cur); flaky != nil {
+		t.Errorf("expected no flaky tests without a recorded failure, got %+v", flaky)
+	}
+}
+
+func TestFlakyTestsIgnoresPackagePasses(t *testing.T) {
+	now := time.Now()
+	h := &TestHistory{Runs: []TestRun{
+		{Time: now.Add(-time.Hour), Code: "c1", Outcome: filter.Outcome{Passed: []string{"pkg"}}},
+		{Time: now.Add(-30 * time.Minute), Code: "c1", Outcome: filter.Outcome{Failed: []string{"pkg TestA"}}},
+	}}
+	cur := TestRun{Time: now, Code: "c1", Outcome: filter.Outcome{Failed: []string{"pkg TestA"}}}
+
+	// The package may have passed with TestA filtered out by -run
+	if flaky := h.FlakyTests(cur); flaky != nil {
+		t.Errorf("a package pass should not count as a pass of its tests, got %+v", flaky)
+	}
+}
+
+func TestFlakyTestsNoHistory(t *testing.T) {
+	h := &TestHistory{}
+	cur := TestRun{Time: time.Now(), Outcome: filter.Outcome{Failed: []string{"TestA"}}}
+	if flaky := h.FlakyTests(cur); flaky != nil {
+		t.Errorf("expected no flaky tests without history, got %+v", flaky)
+	}
+}
+
+func TestCodeFingerprintIncludesUntrackedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write(".gitignore", "*.log\n")
+	git("add", ".gitignore")
+	git("commit", "-q", "-m", "init")
+
+	clean := CodeFingerprint(root)
+	if clean == "" {
+		t.Fatal("expected a fingerprint inside a git repository")
+	}
+	write("run.log", "ignored")
+	if got := CodeFingerprint(root); got != clean {
+		t.Error("ignored files should not change the fingerprint")
+	}
+	write("new_test.go", "package a")
+	added := CodeFingerprint(root)
+	if added == clean {
+		t.Error("a new untracked file should change the fingerprint")
+	}
+	write("new_test.go", "package b")
+	if got := CodeFingerprint(root); got == added {
+		t.Error("editing an untracked file should change the fingerprint")
+	}
+}
+
+func TestFormatFlaky(t *testing.T) {
+	if got := FormatFlaky(nil); got != "" {
+		t.Errorf("FormatFlaky(nil) = %q, want empty", got)
+	}
+	got := FormatFlaky([]FlakyTest{{Name: "pkg TestA", Passed: 2, Failed: 1}})
+	if !strings.Contains(got, "possibly flaky") || !strings.Contains(got, "pkg TestA (failed 1, passed 2 of the last 3 runs)") {
+		t.Errorf("unexpected flaky notice:\n%s", got)
+	}
+}
+
+func TestTestHistoryRoundTrip(t *testing.T) {
+	base := t.TempDir()
+	path := TestHistoryPath(base, "/repo")
+
+	h, err := LoadTestHistory(path, "/repo")
+	if err != nil {
+		t.Fatalf("LoadTestHistory on missing file: %v", err)
+	}
+	if h.Root != "/repo" || len(h.Runs) != 0 {
+		t.Errorf("expected empty history for /repo, got %+v", h)
+	}
+
+	h.Record(TestRun{Command: "go test ./...", Outcome: filter.Outcome{Failed: []string{"TestA"}}})
+	if err := SaveTestHistory(path, h); err != nil {
+		t.Fatalf("SaveTestHistory: %v", err)
+	}
+	got, err := LoadTestHistory(path, "/repo")
+	if err != nil {
+		t.Fatalf("LoadTestHistory: %v", err)
+	}
+	if len(got.Runs) != 1 || got.Runs[0].Command != "go test ./..." {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+}
+
+func TestUpdateTestHistoryConcurrent(t *testing.T) {
+	path := TestHistoryPath(t.TempDir(), "/repo")
+
+	const runs = 8
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := UpdateTestHistory(path, "/repo", func(h *TestHistory) {
+				h.Record(TestRun{Command: "go test ./..."})
+			})
+			if err != nil {
+				t.Errorf("UpdateTestHistory: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	h, err := LoadTestHistory(path, "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Runs) != runs {
+		t.Errorf("len(Runs) = %d, want %d: concurrent updates were lost", len(h.Runs), runs)
+	}
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fuabioo/coc/internal/filter"
+	"github.com/Fuabioo/coc/internal/logpath"
+)
+
+// Snapshot is the parsed outcome of one run, stored so the next run of the
+// same command in the same directory can be compared against it.
+type Snapshot struct {
+	Command string         `json:"command"`
+	Args    []string       `json:"args"`
+	Dir     string         `json:"dir"`
+	Time    time.Time      `json:"time"`
+	Outcome filter.Outcome `json:"outcome"`
+}
+
+// Path returns the snapshot file for a command run in dir.
+// Layout: <base-dir>/history/<command-slug>/<key>.json, where key hashes the
+// directory, command and full argument list so `go test ./a` and `go test ./b`
+// are tracked separately.
+func Path(flagDir, dir, command string, args []string) string {
+	h := sha256.New()
+	h.Write([]byte(dir))
+	h.Write([]byte{0})
+	h.Write([]byte(command))
+	for _, a := range args {
+		h.Write([]byte{0})
+		h.Write([]byte(a))
+	}
+	key := hex.EncodeToString(h.Sum(nil))[:16]
+	return filepath.Join(logpath.BaseDir(flagDir), "history", logpath.Slug(command, args), key+".json")
+}
+
+// Load reads the snapshot at path. It returns an error wrapping os.ErrNotExist
+// when there is no previous run.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", path, err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing snapshot %s: %w", path, err)
+	}
+	return &snap, nil
+}
+
+// Save writes the snapshot to path atomically, creating parent directories.
+func Save(path string, snap *Snapshot) error {
+	return writeJSON(path, snap)
+}
+
+// writeJSON encodes v to path atomically (temp file + rename), creating parent
+// directories, so concurrent coc runs never observe a half-written file.
+func writeJSON(path string, v any) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating history directory %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(dir, strings.TrimSuffix(filepath.Base(path), ".json")+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	return nil
+}
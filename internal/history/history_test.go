@@ -0,0 +1,74 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fuabioo/coc/internal/filter"
+)
+
+func TestPath(t *testing.T) {
+	base := t.TempDir()
+
+	p := Path(base, "/repo", "go", []string{"test", "./..."})
+	if !strings.HasPrefix(p, filepath.Join(base, "history", "go-test")+string(filepath.Separator)) {
+		t.Errorf("Path = %q, want it under %s/history/go-test", p, base)
+	}
+	if filepath.Ext(p) != ".json" {
+		t.Errorf("Path = %q, want .json extension", p)
+	}
+
+	if p != Path(base, "/repo", "go", []string{"test", "./..."}) {
+		t.Error("Path should be stable for identical inputs")
+	}
+	if p == Path(base, "/other", "go", []string{"test", "./..."}) {
+		t.Error("Path should differ per directory")
+	}
+	if p == Path(base, "/repo", "go", []string{"test", "./pkg"}) {
+		t.Error("Path should differ per argument list")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load of missing snapshot should wrap os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "go-test", "abc.json")
+	snap := &Snapshot{
+		Command: "go",
+		Args:    []string{"test"},
+		Dir:     "/repo",
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Outcome: filter.Outcome{Failed: []string{"pkg TestA"}, Errors: []string{"./a.go:1:1: x"}},
+	}
+
+	if err := Save(path, snap); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !got.Time.Equal(snap.Time) || got.Dir != "/repo" {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if len(got.Outcome.Failed) != 1 || got.Outcome.Failed[0] != "pkg TestA" {
+		t.Errorf("Outcome.Failed = %v", got.Outcome.Failed)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the snapshot file, found %d entries", len(entries))
+	}
+}
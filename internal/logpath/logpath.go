@@ -13,14 +13,14 @@ import (
 // Resolve returns the full log file path for a given command and args.
 // Priority: flagDir > envDir > default (os.TempDir()/coc).
 func Resolve(flagDir string, command string, args []string) string {
-	dir := baseDir(flagDir)
+	dir := BaseDir(flagDir)
 	slug := Slug(command, args)
 	sessionID := SessionID()
 	return filepath.Join(dir, slug, sessionID+".log")
 }
 
-// baseDir determines the log directory from flag, env, or default.
-func baseDir(flagDir string) string {
+// BaseDir determines the log directory from flag, env, or default.
+func BaseDir(flagDir string) string {
 	if flagDir != "" {
 		return flagDir
 	}
@@ -49,6 +49,10 @@ func sanitizeSlugPart(s string) string {
 // Examples: ("git", ["status"]) -> "git-status", ("go", ["test", "./..."]) -> "go-test"
 func Slug(command string, args []string) string {
 	base := filepath.Base(command)
+	// Windows launchers (gradlew.bat, mvnw.cmd) share their Unix wrapper's slug
+	if ext := strings.ToLower(filepath.Ext(base)); ext == ".bat" || ext == ".cmd" || ext == ".exe" {
+		base = base[:len(base)-len(ext)]
+	}
 	parts := []string{sanitizeSlugPart(base)}
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "-") {
@@ -86,15 +90,25 @@ func randomHex(n int) (string, error) {
 	return fmt.Sprintf("%x", b), nil
 }
 
-// CreateLogFile creates the log file at the given path, including parent directories.
+// CreateLogFile creates the log file at the given path, including parent
+// directories. Logs may hold secrets printed by the command, so the file is
+// readable by the owner only and its directory is private, including a file
+// or directory left readable by an older version.
 func CreateLogFile(path string) (*os.File, error) {
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return nil, fmt.Errorf("creating log directory %s: %w", dir, err)
 	}
-	f, err := os.Create(path)
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("restricting log directory %s: %w", dir, err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
 		return nil, fmt.Errorf("creating log file %s: %w", path, err)
 	}
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("restricting log file %s: %w", path, err)
+	}
 	return f, nil
 }
@@ -2,6 +2,7 @@ package logpath
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -16,6 +17,8 @@ func TestSlug(t *testing.T) {
 	}{
 		{"simple command", "git", []string{"status"}, "git-status"},
 		{"command with path", "/usr/bin/git", []string{"status"}, "git-status"},
+		{"project wrapper", "./gradlew", []string{"build"}, "gradlew-build"},
+		{"windows wrapper", "mvnw.cmd", []string{"-B", "verify"}, "mvnw-verify"},
 		{"skip flags", "go", []string{"-v", "test", "./..."}, "go-test"},
 		{"no args", "ls", nil, "ls"},
 		{"only flags", "ls", []string{"-la"}, "ls"},
@@ -113,9 +116,9 @@ func TestResolve(t *testing.T) {
 
 func TestBaseDir(t *testing.T) {
 	// Test flag override
-	got := baseDir("/flag/dir")
+	got := BaseDir("/flag/dir")
 	if got != "/flag/dir" {
-		t.Errorf("baseDir with flag = %q, want /flag/dir", got)
+		t.Errorf("BaseDir with flag = %q, want /flag/dir", got)
 	}
 
 	// Test env override
@@ -127,22 +130,22 @@ func TestBaseDir(t *testing.T) {
 			t.Fatalf("unsetenv error: %v", err)
 		}
 	}()
-	got = baseDir("")
+	got = BaseDir("")
 	if got != "/env/dir" {
-		t.Errorf("baseDir with env = %q, want /env/dir", got)
+		t.Errorf("BaseDir with env = %q, want /env/dir", got)
 	}
 
 	// Test default (unset env)
 	if err := os.Unsetenv("COC_LOG_DIR"); err != nil {
 		t.Fatalf("unsetenv error: %v", err)
 	}
-	got = baseDir("")
+	got = BaseDir("")
 	if got == "" {
-		t.Error("baseDir default should not be empty")
+		t.Error("BaseDir default should not be empty")
 	}
 	// Default should end with /coc
 	if !strings.HasSuffix(got, "/coc") {
-		t.Errorf("baseDir default = %q, want suffix /coc", got)
+		t.Errorf("BaseDir default = %q, want suffix /coc", got)
 	}
 }
 
@@ -156,9 +159,9 @@ func TestBaseDirFlagTakesPriorityOverEnv(t *testing.T) {
 		}
 	}()
 
-	got := baseDir("/flag/dir")
+	got := BaseDir("/flag/dir")
 	if got != "/flag/dir" {
-		t.Errorf("baseDir should prefer flag over env, got %q, want /flag/dir", got)
+		t.Errorf("BaseDir should prefer flag over env, got %q, want /flag/dir", got)
 	}
 }
 
@@ -189,3 +192,53 @@ func TestSanitizeSlugPart(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateLogFileIsPrivate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coc", "git-status", "session.log")
+	f, err := CreateLogFile(path)
+	if err != nil {
+		t.Fatalf("CreateLogFile: %v", err)
+	}
+	f.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("log file mode = %o, want 600", perm)
+	}
+	info, err = os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("log directory mode = %o, want 700", perm)
+	}
+}
+
+func TestCreateLogFileRestrictsExistingFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "git-status")
+	path := filepath.Join(dir, "session.log")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := CreateLogFile(path)
+	if err != nil {
+		t.Fatalf("CreateLogFile: %v", err)
+	}
+	f.Close()
+
+	for p, want := range map[string]os.FileMode{path: 0o600, dir: 0o700} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != want {
+			t.Errorf("%s mode = %o, want %o", p, perm, want)
+		}
+	}
+}